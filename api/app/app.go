@@ -1,20 +1,218 @@
 package app
 
 import (
+	"context"
+	"fmt"
 	"github.com/gin-gonic/gin"
+	"interface-testing/api/cache"
+	"interface-testing/api/clients/restclient"
+	"interface-testing/api/providers/weather_provider"
+	"interface-testing/api/redact"
+	"interface-testing/api/services"
+	"interface-testing/api/stores/client_store"
+	"interface-testing/api/stores/usage_store"
+	"interface-testing/api/tracing"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 )
 
 var (
-	router = gin.Default()
+	router = gin.New()
+
+	UsageFlushInterval = time.Minute
+	//ShutdownTimeout is how long the requests in flight get to finish once the server is asked to stop
+	ShutdownTimeout = 10 * time.Second
 )
 
 func RunApp(){
+	//Nothing logged may show a secret, the admin token and redis password included
+	log.SetOutput(redact.Writer(os.Stderr))
+	redact.Register(os.Getenv("ADMIN_TOKEN"))
 
-	routes()
+	//TRACE_EXPORTER sends the request traces to "stdout" or to an "otlp" collector, at OTLP_ENDPOINT such as
+	//"localhost:4318". Trace context is propagated either way
+	shutdownTracing, err := tracing.Setup(os.Getenv("TRACE_EXPORTER"), os.Getenv("OTLP_ENDPOINT"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Println(fmt.Sprintf("error when trying to flush the traces: %s", err.Error()))
+		}
+	}()
+
+	//WEATHER_PROVIDERS is an ordered, comma separated failover chain such as "darksky,openmeteo"
+	if providers := os.Getenv("WEATHER_PROVIDERS"); providers != "" {
+		chain, err := weather_provider.NewProviderChainFromNames(providers)
+		if err != nil {
+			log.Fatal(err)
+		}
+		weather_provider.WeatherProvider = chain
+	}
 
-	if err := router.Run(":8080"); err != nil {
+	//WEATHER_DECODE_MODES picks how strictly each provider's responses are decoded, such as "darksky=standard"
+	if modes := os.Getenv("WEATHER_DECODE_MODES"); modes != "" {
+		if err := weather_provider.SetDecodeModes(modes); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	//WEATHER_HTTP_* configures the connections to the providers, such as WEATHER_HTTP_PROXY or WEATHER_HTTP_CA_FILE,
+	//and <PROVIDER>_HTTP_* overrides it for one provider, such as DARKSKY_HTTP_MAX_CONNS_PER_HOST or OPENMETEO_AIR_HTTP_PROXY
+	config, set, err := restclient.ConfigFromEnv("WEATHER_HTTP_", restclient.DefaultConfig)
+	if err != nil {
 		log.Fatal(err)
 	}
+	if set {
+		if restclient.ClientStruct, err = restclient.NewClient(config); err != nil {
+			log.Fatal(err)
+		}
+	}
+	names := []string{weather_provider.OpenMeteoAirQuality}
+	for name := range weather_provider.Providers {
+		names = append(names, name)
+	}
+	for _, name := range names {
+		providerConfig, set, err := restclient.ConfigFromEnv(strings.ToUpper(name)+"_HTTP_", config)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if set {
+			if restclient.Clients[name], err = restclient.NewClient(providerConfig); err != nil {
+				log.Fatal(err)
+			}
+		}
+	}
+
+	//WEATHER_MAX_BODY_SIZE caps the bytes read from an upstream response, such as "1048576"
+	if size := os.Getenv("WEATHER_MAX_BODY_SIZE"); size != "" {
+		maxBodySize, err := strconv.ParseInt(size, 10, 64)
+		if err != nil || maxBodySize <= 0 {
+			log.Fatal(fmt.Sprintf("invalid WEATHER_MAX_BODY_SIZE %q", size))
+		}
+		weather_provider.MaxBodySize = maxBodySize
+	}
+
+	//WEATHER_GRID_MAX_POINTS caps the points of one /grid request and WEATHER_GRID_CONCURRENCY how many of them
+	//are fetched at once, both protect the upstream quota
+	if points := os.Getenv("WEATHER_GRID_MAX_POINTS"); points != "" {
+		maxPoints, err := strconv.Atoi(points)
+		if err != nil || maxPoints <= 0 {
+			log.Fatal(fmt.Sprintf("invalid WEATHER_GRID_MAX_POINTS %q", points))
+		}
+		services.MaxGridPoints = maxPoints
+	}
+	if concurrency := os.Getenv("WEATHER_GRID_CONCURRENCY"); concurrency != "" {
+		gridConcurrency, err := strconv.Atoi(concurrency)
+		if err != nil || gridConcurrency <= 0 {
+			log.Fatal(fmt.Sprintf("invalid WEATHER_GRID_CONCURRENCY %q", concurrency))
+		}
+		services.GridConcurrency = gridConcurrency
+	}
+
+	//WEATHER_ROUTE_MAX_POINTS caps the samples of one /route request and WEATHER_ROUTE_CONCURRENCY how many of them
+	//are fetched at once
+	if points := os.Getenv("WEATHER_ROUTE_MAX_POINTS"); points != "" {
+		maxPoints, err := strconv.Atoi(points)
+		if err != nil || maxPoints <= 0 {
+			log.Fatal(fmt.Sprintf("invalid WEATHER_ROUTE_MAX_POINTS %q", points))
+		}
+		services.MaxRoutePoints = maxPoints
+	}
+	if concurrency := os.Getenv("WEATHER_ROUTE_CONCURRENCY"); concurrency != "" {
+		routeConcurrency, err := strconv.Atoi(concurrency)
+		if err != nil || routeConcurrency <= 0 {
+			log.Fatal(fmt.Sprintf("invalid WEATHER_ROUTE_CONCURRENCY %q", concurrency))
+		}
+		services.RouteConcurrency = routeConcurrency
+	}
+
+	//WEATHER_CACHE_REDIS shares the weather cache between replicas, such as "localhost:6379" or "redis://:password@host:6379/0"
+	if address := os.Getenv("WEATHER_CACHE_REDIS"); address != "" {
+		namespace := os.Getenv("WEATHER_CACHE_NAMESPACE")
+		if namespace == "" {
+			namespace = "weather"
+		}
+		cache.WeatherCache = cache.NewRedisCache(address, namespace)
+	}
+
+	//CLIENT_KEYS_FILE holds the hashed client keys the weather routes then require,
+	//ADMIN_TOKEN opens the /admin routes that issue them
+	keysFile, adminToken := os.Getenv("CLIENT_KEYS_FILE"), os.Getenv("ADMIN_TOKEN")
+	if adminToken != "" && keysFile == "" {
+		log.Fatal("ADMIN_TOKEN needs a CLIENT_KEYS_FILE to keep the keys in")
+	}
+	if keysFile != "" {
+		store, err := client_store.NewFileStore(keysFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		client_store.ClientStore = store
+	}
+
+	//USAGE_FILE keeps the per client usage across restarts, it is written every UsageFlushInterval and on shutdown
+	if usageFile := os.Getenv("USAGE_FILE"); usageFile != "" {
+		store, err := usage_store.NewFileStore(usageFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		usage_store.UsageStore = store
+		done, flushed := make(chan struct{}), make(chan struct{})
+		go func() {
+			flushUsage(done)
+			close(flushed)
+		}()
+		defer func() {
+			close(done)
+			<-flushed
+		}()
+	}
+
+	routes(keysFile != "", adminToken)
+
+	//The server stops on SIGINT or SIGTERM rather than being killed, so the deferred flushes run
+	server := &http.Server{Addr: ":8080", Handler: router}
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	select {
+	case err := <-serverErr:
+		log.Fatal(err)
+	case <-stop:
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		log.Println(fmt.Sprintf("error when trying to stop the server: %s", err.Error()))
+	}
+}
+
+//flushUsage writes the usage file every UsageFlushInterval until done is closed, and a last time then
+func flushUsage(done <-chan struct{}) {
+	ticker := time.NewTicker(UsageFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			writeUsage()
+		case <-done:
+			writeUsage()
+			return
+		}
+	}
 }
 
+func writeUsage() {
+	if err := usage_store.UsageStore.Flush(); err != nil {
+		log.Println(fmt.Sprintf("error when trying to write the usage file: %s", err.Error()))
+	}
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"interface-testing/api/domain/client_domain"
+	"interface-testing/api/stores/usage_store"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlushUsageOnShutdown(t *testing.T) {
+	dir, err := ioutil.TempDir("", "app")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "usage.json")
+	usage_store.UsageStore, err = usage_store.NewFileStore(path)
+	assert.Nil(t, err)
+	UsageFlushInterval = time.Hour
+
+	usage_store.UsageStore.Add("a", "2020-03-01", client_domain.Usage{Requests: 3})
+	done := make(chan struct{})
+	close(done)
+	//No tick comes within the test, the usage is only written because of done
+	flushUsage(done)
+
+	reloaded, err := usage_store.NewFileStore(path)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 3, reloaded.Month("a", "2020-03").Requests)
+}
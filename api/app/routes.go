@@ -1,7 +1,33 @@
 package app
 
-import "interface-testing/api/controllers/weather_controller"
+import (
+	"interface-testing/api/controllers/admin_controller"
+	"interface-testing/api/controllers/metrics_controller"
+	"interface-testing/api/controllers/weather_controller"
+	"interface-testing/api/middleware"
+)
 
-func routes() {
-	router.GET("/weather/:apiKey/:latitude/:longitude", weather_controller.GetWeather)
+//routes registers the weather routes behind client keys when clientKeys is set,
+//and the admin routes when there is an adminToken
+func routes(clientKeys bool, adminToken string) {
+	router.Use(middleware.Logger(), middleware.Recovery(), middleware.Tracing())
+	weather := router.Group("/weather")
+	if clientKeys {
+		weather.Use(middleware.ClientKey())
+	}
+	weather.GET("/:apiKey/:latitude/:longitude", weather_controller.GetWeather)
+	weather.GET("/:apiKey/grid", weather_controller.GetWeatherGrid)
+	weather.POST("/:apiKey/route", weather_controller.GetWeatherRoute)
+	weather.GET("/stream", weather_controller.StreamWeather)
+	weather.GET("/ws", weather_controller.StreamWeatherSocket)
+	router.GET("/metrics", metrics_controller.GetMetrics)
+
+	if adminToken != "" {
+		admin := router.Group("/admin", middleware.AdminToken(adminToken))
+		admin.POST("/keys", admin_controller.CreateKey)
+		admin.GET("/keys", admin_controller.ListKeys)
+		admin.POST("/keys/:id/rotate", admin_controller.RotateKey)
+		admin.DELETE("/keys/:id", admin_controller.RevokeKey)
+		admin.GET("/usage", admin_controller.ExportUsage)
+	}
 }
@@ -0,0 +1,23 @@
+package cache
+
+import (
+	"interface-testing/api/domain/weather_domain"
+	"time"
+)
+
+//Entry is a cached weather response along with when it was fetched from the provider
+type Entry struct {
+	Weather  *weather_domain.Weather `json:"weather"`
+	StoredAt time.Time               `json:"storedAt"`
+}
+
+type cacheInterface interface {
+	//Get returns the entry stored under key, unless there is none or its ttl has passed
+	Get(key string) (*Entry, bool)
+	//Set stores entry under key for ttl
+	Set(key string, entry *Entry, ttl time.Duration)
+}
+
+var (
+	WeatherCache cacheInterface = NewMemoryCache()
+)
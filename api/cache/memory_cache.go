@@ -0,0 +1,54 @@
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type memoryItem struct {
+	entry   *Entry
+	expires time.Time
+}
+
+//memoryCache keeps the entries of this process only
+type memoryCache struct {
+	mu        sync.Mutex
+	items     map[string]memoryItem
+	nextSweep time.Time
+}
+
+//MemorySweepInterval is how often Set drops the expired entries nobody asked for again
+var MemorySweepInterval = time.Minute
+
+func NewMemoryCache() cacheInterface {
+	return &memoryCache{items: make(map[string]memoryItem)}
+}
+
+func (m *memoryCache) Get(key string) (*Entry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	item, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+	if !time.Now().Before(item.expires) {
+		delete(m.items, key)
+		return nil, false
+	}
+	return item.entry, true
+}
+
+func (m *memoryCache) Set(key string, entry *Entry, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	if now.After(m.nextSweep) {
+		for k, item := range m.items {
+			if !now.Before(item.expires) {
+				delete(m.items, k)
+			}
+		}
+		m.nextSweep = now.Add(MemorySweepInterval)
+	}
+	m.items[key] = memoryItem{entry: entry, expires: now.Add(ttl)}
+}
@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"interface-testing/api/domain/weather_domain"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryCacheGetSet(t *testing.T) {
+	cache := NewMemoryCache()
+	_, ok := cache.Get("key")
+	assert.False(t, ok)
+
+	entry := &Entry{Weather: &weather_domain.Weather{TimeZone: "Europe/Paris"}, StoredAt: time.Now()}
+	cache.Set("key", entry, time.Minute)
+	cached, ok := cache.Get("key")
+	assert.True(t, ok)
+	assert.EqualValues(t, "Europe/Paris", cached.Weather.TimeZone)
+}
+
+func TestMemoryCacheExpires(t *testing.T) {
+	cache := NewMemoryCache()
+	cache.Set("key", &Entry{Weather: &weather_domain.Weather{}, StoredAt: time.Now()}, 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	_, ok := cache.Get("key")
+	assert.False(t, ok)
+}
+
+func TestMemoryCacheSweepsExpiredEntries(t *testing.T) {
+	cache := NewMemoryCache().(*memoryCache)
+	cache.Set("old", &Entry{Weather: &weather_domain.Weather{}}, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	cache.nextSweep = time.Time{}
+	cache.Set("new", &Entry{Weather: &weather_domain.Weather{}}, time.Minute)
+	assert.EqualValues(t, 1, len(cache.items))
+}
@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gomodule/redigo/redis"
+)
+
+//redisCache shares its entries between every replica pointed at the same redis server
+type redisCache struct {
+	pool      *redis.Pool
+	namespace string
+}
+
+var (
+	//RedisTimeout bounds connecting, reading and writing: a slow cache must not be slower than the provider
+	RedisTimeout = time.Second
+	RedisMaxIdle = 10
+)
+
+//NewRedisCache stores the entries as JSON under "namespace:key" on the server at address, which is either
+//host:port or a redis:// url carrying the password and database
+func NewRedisCache(address string, namespace string) cacheInterface {
+	return &redisCache{
+		namespace: namespace,
+		pool: &redis.Pool{
+			MaxIdle:     RedisMaxIdle,
+			IdleTimeout: 4 * time.Minute,
+			Dial: func() (redis.Conn, error) {
+				options := []redis.DialOption{
+					redis.DialConnectTimeout(RedisTimeout),
+					redis.DialReadTimeout(RedisTimeout),
+					redis.DialWriteTimeout(RedisTimeout),
+				}
+				if strings.Contains(address, "://") {
+					return redis.DialURL(address, options...)
+				}
+				return redis.Dial("tcp", address, options...)
+			},
+		},
+	}
+}
+
+//Get treats a redis failure as a miss, so the provider is asked instead
+func (r *redisCache) Get(key string) (*Entry, bool) {
+	conn := r.pool.Get()
+	defer conn.Close()
+	bytes, err := redis.Bytes(conn.Do("GET", r.key(key)))
+	if err != nil {
+		if err != redis.ErrNil {
+			log.Println(fmt.Sprintf("error when trying to get %s from the redis cache: %s", key, err.Error()))
+		}
+		return nil, false
+	}
+	var entry Entry
+	if err := json.Unmarshal(bytes, &entry); err != nil || entry.Weather == nil {
+		log.Println(fmt.Sprintf("invalid entry %s in the redis cache", key))
+		return nil, false
+	}
+	return &entry, true
+}
+
+func (r *redisCache) Set(key string, entry *Entry, ttl time.Duration) {
+	bytes, err := json.Marshal(entry)
+	if err != nil {
+		log.Println(fmt.Sprintf("error when trying to marshal cache entry %s: %s", key, err.Error()))
+		return
+	}
+	milliseconds := ttl.Milliseconds()
+	if milliseconds < 1 {
+		milliseconds = 1
+	}
+	conn := r.pool.Get()
+	defer conn.Close()
+	if _, err := conn.Do("SET", r.key(key), bytes, "PX", milliseconds); err != nil {
+		log.Println(fmt.Sprintf("error when trying to set %s in the redis cache: %s", key, err.Error()))
+	}
+}
+
+func (r *redisCache) key(key string) string {
+	if r.namespace == "" {
+		return key
+	}
+	return r.namespace + ":" + key
+}
@@ -0,0 +1,103 @@
+package cache
+
+import (
+	"interface-testing/api/domain/weather_domain"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func redisEntry() *Entry {
+	return &Entry{
+		Weather: &weather_domain.Weather{
+			Latitude:  48.85,
+			Longitude: 2.35,
+			TimeZone:  "Europe/Paris",
+			Currently: weather_domain.CurrentlyInfo{Temperature: 12.5, Summary: "Overcast", Humidity: 0.8, WindGust: 7.2},
+			Flags:     &weather_domain.FlagsInfo{Units: "si"},
+			Provider:  "openmeteo",
+			Cache:     &weather_domain.CacheInfo{Status: weather_domain.CacheMiss},
+		},
+		StoredAt: time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestRedisCacheGetSet(t *testing.T) {
+	server, err := miniredis.Run()
+	assert.Nil(t, err)
+	defer server.Close()
+	cache := NewRedisCache(server.Addr(), "weather")
+
+	_, ok := cache.Get("key")
+	assert.False(t, ok)
+
+	cache.Set("key", redisEntry(), time.Minute)
+	entry, ok := cache.Get("key")
+	assert.True(t, ok)
+	assert.EqualValues(t, redisEntry().StoredAt.Unix(), entry.StoredAt.Unix())
+	assert.EqualValues(t, "Europe/Paris", entry.Weather.TimeZone)
+	assert.EqualValues(t, redisEntry().Weather.Currently, entry.Weather.Currently)
+	assert.EqualValues(t, "si", entry.Weather.Flags.Units)
+	assert.EqualValues(t, "openmeteo", entry.Weather.Provider)
+	//How a response was served is not part of the cached data
+	assert.Nil(t, entry.Weather.Cache)
+}
+
+func TestRedisCacheNamespacesKeys(t *testing.T) {
+	server, err := miniredis.Run()
+	assert.Nil(t, err)
+	defer server.Close()
+
+	NewRedisCache(server.Addr(), "weather").Set("key", redisEntry(), time.Minute)
+	assert.EqualValues(t, []string{"weather:key"}, server.Keys())
+	_, ok := NewRedisCache(server.Addr(), "other").Get("key")
+	assert.False(t, ok)
+}
+
+func TestRedisCacheExpires(t *testing.T) {
+	server, err := miniredis.Run()
+	assert.Nil(t, err)
+	defer server.Close()
+	cache := NewRedisCache(server.Addr(), "weather")
+
+	cache.Set("key", redisEntry(), 90*time.Second)
+	assert.EqualValues(t, 90*time.Second, server.TTL("weather:key"))
+	server.FastForward(91 * time.Second)
+	_, ok := cache.Get("key")
+	assert.False(t, ok)
+}
+
+func TestRedisCacheInvalidEntry(t *testing.T) {
+	server, err := miniredis.Run()
+	assert.Nil(t, err)
+	defer server.Close()
+	server.Set("weather:key", "{not json")
+
+	_, ok := NewRedisCache(server.Addr(), "weather").Get("key")
+	assert.False(t, ok)
+}
+
+func TestRedisCacheUnreachable(t *testing.T) {
+	server, err := miniredis.Run()
+	assert.Nil(t, err)
+	cache := NewRedisCache(server.Addr(), "weather")
+	server.Close()
+
+	cache.Set("key", redisEntry(), time.Minute)
+	_, ok := cache.Get("key")
+	assert.False(t, ok)
+}
+
+func TestRedisCacheUrl(t *testing.T) {
+	server, err := miniredis.Run()
+	assert.Nil(t, err)
+	defer server.Close()
+	server.RequireAuth("secret")
+
+	cache := NewRedisCache("redis://:secret@"+server.Addr()+"/0", "weather")
+	cache.Set("key", redisEntry(), time.Minute)
+	_, ok := cache.Get("key")
+	assert.True(t, ok)
+}
@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"interface-testing/api/app"
+	"interface-testing/api/clients/restclient"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/redact"
+	"interface-testing/api/services"
+	"io"
+	"io/ioutil"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+const (
+	usage = `usage:
+  weather serve
+  weather get --lat <latitude> --lon <longitude> [--units us|si|ca|uk2|auto] [--format table|json] [--key <api key>] [--server <url>] [--client-key <key>]
+`
+	apiKeyEnv    = "WEATHER_API_KEY"
+	clientKeyEnv = "WEATHER_CLIENT_KEY"
+)
+
+var (
+	//serve is a variable so tests can check the dispatch without starting the server
+	serve = app.RunApp
+)
+
+//Run executes the subcommand in args and returns the process exit code.
+//Without a subcommand the server is started, like it always has been
+func Run(args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		serve()
+		return 0
+	}
+	switch args[0] {
+	case "serve":
+		serve()
+		return 0
+	case "get":
+		return get(args[1:], stdout, stderr)
+	default:
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+}
+
+func get(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("get", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	lat := flags.Float64("lat", 0, "latitude of the location")
+	long := flags.Float64("lon", 0, "longitude of the location")
+	units := flags.String("units", "", "units of the response: us, si, ca, uk2 or auto")
+	format := flags.String("format", "table", "output format: table or json")
+	apiKey := flags.String("key", os.Getenv(apiKeyEnv), "weather api key, defaults to $"+apiKeyEnv)
+	server := flags.String("server", "", "url of a running weather server; the upstream is called directly when empty")
+	clientKey := flags.String("client-key", os.Getenv(clientKeyEnv), "key the server issued this client, defaults to $"+clientKeyEnv)
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	//0 is a valid latitude and longitude, so a missing flag is told apart by whether it was set at all
+	set := map[string]bool{}
+	flags.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	for _, name := range []string{"lat", "lon"} {
+		if !set[name] {
+			fmt.Fprintf(stderr, "missing required flag --%s\n", name)
+			fmt.Fprint(stderr, usage)
+			return 2
+		}
+	}
+	if *format != "table" && *format != "json" {
+		fmt.Fprintf(stderr, "unknown format %q\n", *format)
+		return 2
+	}
+	request := weather_domain.WeatherRequest{
+		ApiKey:    *apiKey,
+		Latitude:  *lat,
+		Longitude: *long,
+		Units:     *units,
+	}
+
+	var result *weather_domain.Weather
+	var apiError weather_domain.WeatherErrorInterface
+	if *server != "" {
+		result, apiError = getFromServer(*server, *clientKey, request)
+	} else {
+		result, apiError = services.WeatherService.GetWeather(context.Background(), request)
+	}
+	if apiError != nil {
+		fmt.Fprintf(stderr, "error %d: %s\n", apiError.Status(), apiError.Message())
+		return 1
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		return 0
+	}
+	printTable(stdout, result)
+	return 0
+}
+
+func getFromServer(server string, clientKey string, request weather_domain.WeatherRequest) (*weather_domain.Weather, weather_domain.WeatherErrorInterface) {
+	url := fmt.Sprintf("%s/weather/%s/%v/%v", strings.TrimRight(server, "/"), request.ApiKey, request.Latitude, request.Longitude)
+	query := neturl.Values{}
+	if request.Units != "" {
+		query.Set("units", request.Units)
+	}
+	if clientKey != "" {
+		query.Set("client_key", clientKey)
+	}
+	if len(query) > 0 {
+		url += "?" + query.Encode()
+	}
+	//The errors of a failed request quote the url, api key and client key included
+	redacted := redact.NewURL(url, request.ApiKey, clientKey)
+	response, err := restclient.ClientStruct.Get(redacted.Reveal())
+	if err != nil {
+		return nil, weather_domain.NewWeatherError(http.StatusBadGateway, redacted.Error(err))
+	}
+	defer response.Body.Close()
+	bytes, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, weather_domain.NewWeatherError(http.StatusBadGateway, redacted.Error(err))
+	}
+	if response.StatusCode > 299 {
+		apiError, err := weather_domain.NewApiErrFromBytes(bytes)
+		if err != nil {
+			return nil, weather_domain.NewWeatherError(response.StatusCode, "invalid json response body")
+		}
+		return nil, apiError
+	}
+	var result weather_domain.Weather
+	if err := json.Unmarshal(bytes, &result); err != nil {
+		return nil, weather_domain.NewWeatherError(http.StatusInternalServerError, "error unmarshaling weather fetch response")
+	}
+	return &result, nil
+}
+
+func printTable(w io.Writer, weather *weather_domain.Weather) {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(table, "Latitude\t%v\n", weather.Latitude)
+	fmt.Fprintf(table, "Longitude\t%v\n", weather.Longitude)
+	fmt.Fprintf(table, "Time zone\t%s\n", weather.TimeZone)
+	fmt.Fprintf(table, "Summary\t%s\n", weather.Currently.Summary)
+	fmt.Fprintf(table, "Temperature\t%v\n", weather.Currently.Temperature)
+	fmt.Fprintf(table, "Dew point\t%v\n", weather.Currently.DewPoint)
+	fmt.Fprintf(table, "Pressure\t%v\n", weather.Currently.Pressure)
+	fmt.Fprintf(table, "Humidity\t%v\n", weather.Currently.Humidity)
+	fmt.Fprintf(table, "Wind speed\t%v\n", weather.Currently.WindSpeed)
+	if weather.Comfort != nil {
+		fmt.Fprintf(table, "Feels like\t%v\n", weather.Comfort.ApparentTemperature)
+		fmt.Fprintf(table, "Comfort\t%s\n", weather.Comfort.Category)
+	}
+	table.Flush()
+}
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	getWeatherFunc func(request weather_domain.WeatherRequest) (*weather_domain.Weather, weather_domain.WeatherErrorInterface)
+)
+
+type weatherServiceMock struct{}
+
+//We are mocking the service method "GetWeather"
+func (w *weatherServiceMock) GetWeather(ctx context.Context, request weather_domain.WeatherRequest) (*weather_domain.Weather, weather_domain.WeatherErrorInterface) {
+	return getWeatherFunc(request)
+}
+
+func weather(request weather_domain.WeatherRequest) *weather_domain.Weather {
+	return &weather_domain.Weather{
+		Latitude:  request.Latitude,
+		Longitude: request.Longitude,
+		TimeZone:  "Africa/Nouakchott",
+		Currently: weather_domain.CurrentlyInfo{
+			Temperature: 78.02,
+			Summary:     "Overcast",
+			DewPoint:    32.37,
+			Pressure:    1014.1,
+			Humidity:    0.19,
+		},
+	}
+}
+
+func TestRunServe(t *testing.T) {
+	served := 0
+	serve = func() { served++ }
+	assert.EqualValues(t, 0, Run([]string{"serve"}, &bytes.Buffer{}, &bytes.Buffer{}))
+	assert.EqualValues(t, 0, Run(nil, &bytes.Buffer{}, &bytes.Buffer{}))
+	assert.EqualValues(t, 2, served)
+}
+
+func TestRunUnknownCommand(t *testing.T) {
+	var stderr bytes.Buffer
+	assert.EqualValues(t, 2, Run([]string{"forecast"}, &bytes.Buffer{}, &stderr))
+	assert.Contains(t, stderr.String(), "usage:")
+}
+
+func TestGetJson(t *testing.T) {
+	var received weather_domain.WeatherRequest
+	getWeatherFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, weather_domain.WeatherErrorInterface) {
+		received = request
+		return weather(request), nil
+	}
+	services.WeatherService = &weatherServiceMock{}
+
+	var stdout bytes.Buffer
+	code := Run([]string{"get", "--lat", "20.34", "--lon", "-12.44", "--units", "si", "--format", "json", "--key", "right_api_key"}, &stdout, &bytes.Buffer{})
+	assert.EqualValues(t, 0, code)
+	assert.EqualValues(t, weather_domain.WeatherRequest{ApiKey: "right_api_key", Latitude: 20.34, Longitude: -12.44, Units: "si"}, received)
+
+	var result weather_domain.Weather
+	assert.Nil(t, json.Unmarshal(stdout.Bytes(), &result))
+	assert.EqualValues(t, 20.34, result.Latitude)
+	assert.EqualValues(t, "Overcast", result.Currently.Summary)
+}
+
+func TestGetTable(t *testing.T) {
+	getWeatherFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, weather_domain.WeatherErrorInterface) {
+		return weather(request), nil
+	}
+	services.WeatherService = &weatherServiceMock{}
+
+	var stdout bytes.Buffer
+	code := Run([]string{"get", "--lat", "20.34", "--lon", "-12.44", "--key", "right_api_key"}, &stdout, &bytes.Buffer{})
+	assert.EqualValues(t, 0, code)
+	assert.Contains(t, stdout.String(), "Summary      Overcast\n")
+	assert.Contains(t, stdout.String(), "Temperature  78.02\n")
+}
+
+func TestGetInvalidFormat(t *testing.T) {
+	var stderr bytes.Buffer
+	code := Run([]string{"get", "--lat", "20.34", "--lon", "-12.44", "--format", "xml"}, &bytes.Buffer{}, &stderr)
+	assert.EqualValues(t, 2, code)
+	assert.EqualValues(t, "unknown format \"xml\"\n", stderr.String())
+}
+
+func TestGetMissingLocation(t *testing.T) {
+	getWeatherFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, weather_domain.WeatherErrorInterface) {
+		t.Fatal("the weather must not be fetched without a location")
+		return nil, nil
+	}
+	services.WeatherService = &weatherServiceMock{}
+
+	var stderr bytes.Buffer
+	code := Run([]string{"get", "--lat", "20.34", "--key", "right_api_key"}, &bytes.Buffer{}, &stderr)
+	assert.EqualValues(t, 2, code)
+	assert.Contains(t, stderr.String(), "missing required flag --lon\n")
+	assert.Contains(t, stderr.String(), "usage:")
+
+	stderr.Reset()
+	assert.EqualValues(t, 2, Run([]string{"get", "--lon", "0", "--key", "right_api_key"}, &bytes.Buffer{}, &stderr))
+	assert.Contains(t, stderr.String(), "missing required flag --lat\n")
+}
+
+func TestGetZeroLocation(t *testing.T) {
+	var received weather_domain.WeatherRequest
+	getWeatherFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, weather_domain.WeatherErrorInterface) {
+		received = request
+		return weather(request), nil
+	}
+	services.WeatherService = &weatherServiceMock{}
+
+	code := Run([]string{"get", "--lat", "0", "--lon", "0", "--key", "right_api_key"}, &bytes.Buffer{}, &bytes.Buffer{})
+	assert.EqualValues(t, 0, code)
+	assert.EqualValues(t, weather_domain.WeatherRequest{ApiKey: "right_api_key"}, received)
+}
+
+func TestGetError(t *testing.T) {
+	getWeatherFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, weather_domain.WeatherErrorInterface) {
+		return nil, weather_domain.NewForbiddenError("permission denied")
+	}
+	services.WeatherService = &weatherServiceMock{}
+
+	var stderr bytes.Buffer
+	code := Run([]string{"get", "--lat", "20.34", "--lon", "-12.44", "--key", "wrong_api_key"}, &bytes.Buffer{}, &stderr)
+	assert.EqualValues(t, 1, code)
+	assert.EqualValues(t, "error 403: permission denied\n", stderr.String())
+}
+
+func TestGetFromServer(t *testing.T) {
+	var path, units, clientKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		units = r.URL.Query().Get("units")
+		clientKey = r.URL.Query().Get("client_key")
+		json.NewEncoder(w).Encode(weather(weather_domain.WeatherRequest{Latitude: 20.34, Longitude: -12.44}))
+	}))
+	defer server.Close()
+
+	var stdout bytes.Buffer
+	code := Run([]string{"get", "--lat", "20.34", "--lon", "-12.44", "--units", "si", "--format", "json", "--key", "right_api_key", "--server", server.URL, "--client-key", "wk_client"}, &stdout, &bytes.Buffer{})
+	assert.EqualValues(t, 0, code)
+	assert.EqualValues(t, "/weather/right_api_key/20.34/-12.44", path)
+	assert.EqualValues(t, "si", units)
+	assert.EqualValues(t, "wk_client", clientKey)
+
+	var result weather_domain.Weather
+	assert.Nil(t, json.Unmarshal(stdout.Bytes(), &result))
+	assert.EqualValues(t, "Overcast", result.Currently.Summary)
+}
+
+func TestGetFromServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"code": 403, "error": "permission denied"}`))
+	}))
+	defer server.Close()
+
+	var stderr bytes.Buffer
+	code := Run([]string{"get", "--lat", "20.34", "--lon", "-12.44", "--key", "wrong_api_key", "--server", server.URL}, &bytes.Buffer{}, &stderr)
+	assert.EqualValues(t, 1, code)
+	assert.EqualValues(t, "error 403: permission denied\n", stderr.String())
+}
+
+func TestGetFromServerUnreachableHidesKeys(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+
+	var stderr bytes.Buffer
+	code := Run([]string{"get", "--lat", "20.34", "--lon", "-12.44", "--key", "right_api_key", "--server", server.URL, "--client-key", "my_client_key"}, &bytes.Buffer{}, &stderr)
+	assert.EqualValues(t, 1, code)
+	assert.Contains(t, stderr.String(), "error 502: ")
+	assert.Contains(t, stderr.String(), "/weather/REDACTED/20.34/-12.44")
+	assert.NotContains(t, stderr.String(), "right_api_key")
+	assert.NotContains(t, stderr.String(), "my_client_key")
+}
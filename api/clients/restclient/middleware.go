@@ -0,0 +1,109 @@
+package restclient
+
+import (
+	"errors"
+	"fmt"
+	"interface-testing/api/redact"
+	"interface-testing/api/tracing"
+	"log"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//Middleware wraps a RoundTripper to add behavior to every outbound request, such as headers or logging
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+//RoundTripperFunc lets a function be used as a RoundTripper
+type RoundTripperFunc func(request *http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+//Chain wraps transport in middlewares, the first one seeing the request first and the response last
+func Chain(transport http.RoundTripper, middlewares ...Middleware) http.RoundTripper {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		transport = middlewares[i](transport)
+	}
+	return transport
+}
+
+//Headers sets headers on the requests that do not have them already
+func Headers(headers http.Header) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+			//A RoundTripper must not modify the request it is given
+			request = request.Clone(request.Context())
+			for name, values := range headers {
+				if request.Header.Get(name) == "" {
+					request.Header[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+				}
+			}
+			return next.RoundTrip(request)
+		})
+	}
+}
+
+//UserAgent sets the User-Agent of the requests that do not have one
+func UserAgent(userAgent string) Middleware {
+	return Headers(http.Header{"User-Agent": {userAgent}})
+}
+
+//Timing calls observe once each request is answered or has failed, with the time it took
+func Timing(observe func(request *http.Request, response *http.Response, err error, elapsed time.Duration)) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+			start := time.Now()
+			response, err := next.RoundTrip(request)
+			observe(request, response, err, time.Since(start))
+			return response, err
+		})
+	}
+}
+
+//Logging logs the method, host, outcome and duration of every request. The path and query are left out
+//because some providers take their api key there, and errors are masked with the secrets of the request context
+func Logging() Middleware {
+	return Timing(func(request *http.Request, response *http.Response, err error, elapsed time.Duration) {
+		outcome := ""
+		if err != nil {
+			outcome = redact.Context(request.Context(), err.Error())
+		} else {
+			outcome = response.Status
+		}
+		log.Println(fmt.Sprintf("%s %s: %s in %s", request.Method, request.URL.Host, outcome, elapsed.Round(time.Millisecond)))
+	})
+}
+
+//Tracing starts a client span for every request and sends its trace context along in the W3C headers.
+//Like Logging, it leaves out the path and query
+func Tracing() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+			ctx, span := tracing.Tracer().Start(request.Context(), request.Method+" "+request.URL.Host,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(attribute.String("http.request.method", request.Method), attribute.String("server.address", request.URL.Host)))
+			defer span.End()
+			request = request.Clone(ctx)
+			otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(request.Header))
+			response, err := next.RoundTrip(request)
+			if err != nil {
+				message := redact.Context(request.Context(), err.Error())
+				span.RecordError(errors.New(message))
+				span.SetStatus(codes.Error, message)
+				return response, err
+			}
+			span.SetAttributes(attribute.Int("http.response.status_code", response.StatusCode))
+			if response.StatusCode >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, response.Status)
+			}
+			return response, err
+		})
+	}
+}
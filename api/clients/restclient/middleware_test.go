@@ -0,0 +1,113 @@
+package restclient
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChainOrder(t *testing.T) {
+	var order []string
+	tag := func(name string) Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+				order = append(order, name+" in")
+				response, err := next.RoundTrip(request)
+				order = append(order, name+" out")
+				return response, err
+			})
+		}
+	}
+	transport := RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		order = append(order, "transport")
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+
+	request, _ := http.NewRequest(http.MethodGet, "http://weather.invalid", nil)
+	_, err := Chain(transport, tag("first"), tag("second")).RoundTrip(request)
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"first in", "second in", "transport", "second out", "first out"}, order)
+}
+
+func TestHeaders(t *testing.T) {
+	var received http.Header
+	transport := RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		received = request.Header
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+	chain := Chain(transport, Headers(http.Header{"X-Team": {"weather"}, "Accept": {"application/json"}}), UserAgent("weather-api/1.0"))
+
+	request, _ := http.NewRequest(http.MethodGet, "http://weather.invalid", nil)
+	request.Header.Set("Accept", "text/csv")
+	_, err := chain.RoundTrip(request)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "weather", received.Get("X-Team"))
+	assert.EqualValues(t, "weather-api/1.0", received.Get("User-Agent"))
+	//A header the caller set wins, and the caller's request is left as it was
+	assert.EqualValues(t, "text/csv", received.Get("Accept"))
+	assert.EqualValues(t, "", request.Header.Get("X-Team"))
+}
+
+func TestTimingAndLogging(t *testing.T) {
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	var observed time.Duration
+	failing := RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	})
+	chain := Chain(failing, Logging(), Timing(func(request *http.Request, response *http.Response, err error, elapsed time.Duration) {
+		observed = elapsed
+		assert.NotNil(t, err)
+	}))
+
+	request, _ := http.NewRequest(http.MethodGet, "http://weather.invalid/forecast/secret_key/1,2", nil)
+	_, err := chain.RoundTrip(request)
+	assert.NotNil(t, err)
+	assert.True(t, observed >= 0)
+	assert.Contains(t, logged.String(), "GET weather.invalid: connection refused in ")
+	assert.NotContains(t, logged.String(), "secret_key")
+}
+
+func TestNewClientMiddlewaresAndPost(t *testing.T) {
+	var method, contentType, userAgent, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, contentType, userAgent = r.Method, r.Header.Get("Content-Type"), r.Header.Get("User-Agent")
+		read, _ := ioutil.ReadAll(r.Body)
+		body = string(read)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	var timed int
+	config := DefaultConfig
+	config.UserAgent = "weather-api/1.0"
+	client, err := NewClient(config, Timing(func(*http.Request, *http.Response, error, time.Duration) { timed++ }))
+	assert.Nil(t, err)
+
+	response, err := client.Post(server.URL, "application/json", strings.NewReader(`{"latitude": 44.36}`))
+	assert.Nil(t, err)
+	response.Body.Close()
+	assert.EqualValues(t, http.StatusCreated, response.StatusCode)
+	assert.EqualValues(t, http.MethodPost, method)
+	assert.EqualValues(t, "application/json", contentType)
+	assert.EqualValues(t, "weather-api/1.0", userAgent)
+	assert.EqualValues(t, `{"latitude": 44.36}`, body)
+
+	request, _ := http.NewRequest(http.MethodDelete, server.URL, nil)
+	response, err = client.Do(request)
+	assert.Nil(t, err)
+	response.Body.Close()
+	assert.EqualValues(t, http.MethodDelete, method)
+	assert.EqualValues(t, 2, timed)
+}
@@ -1,24 +1,74 @@
 package restclient
 
 import (
+	"io"
 	"net/http"
 )
 
-type clientStruct struct{}
+//clientStruct sends the requests through its http.Client, whose transport keeps the connections alive between them
+type clientStruct struct {
+	client *http.Client
+}
 
 type ClientInterface interface {
-	Get(string) (*http.Response, error)
+	Get(url string) (*http.Response, error)
+	Post(url string, contentType string, body io.Reader) (*http.Response, error)
+	//Do sends any request, for the methods and headers Get and Post do not cover
+	Do(request *http.Request) (*http.Response, error)
 }
 var (
-	ClientStruct ClientInterface = &clientStruct{}
+	ClientStruct ClientInterface = &clientStruct{client: &http.Client{Transport: Chain(mustTransport(DefaultConfig), Tracing())}}
+
+	//Clients are the clients of the providers that need settings of their own, the others use ClientStruct
+	Clients = map[string]ClientInterface{}
 )
 
+//For returns the client the provider named name sends its requests with
+func For(name string) ClientInterface {
+	if client, ok := Clients[name]; ok {
+		return client
+	}
+	return ClientStruct
+}
+
+//NewClient returns a client with its own transport built from config, traced. The middlewares wrap it after the
+//ones config asks for, so they see the request with the configured headers
+func NewClient(config TransportConfig, middlewares ...Middleware) (ClientInterface, error) {
+	transport, err := NewTransport(config)
+	if err != nil {
+		return nil, err
+	}
+	chain := []Middleware{Tracing()}
+	if config.LogRequests {
+		chain = append(chain, Logging())
+	}
+	if len(config.Headers) > 0 {
+		chain = append(chain, Headers(config.Headers))
+	}
+	if config.UserAgent != "" {
+		chain = append(chain, UserAgent(config.UserAgent))
+	}
+	chain = append(chain, middlewares...)
+	return &clientStruct{client: &http.Client{Transport: Chain(transport, chain...), Timeout: config.Timeout}}, nil
+}
+
 func (ci *clientStruct) Get(url string) (*http.Response, error) {
 	request, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
-	client := http.Client{}
+	return ci.client.Do(request)
+}
+
+func (ci *clientStruct) Post(url string, contentType string, body io.Reader) (*http.Response, error) {
+	request, err := http.NewRequest(http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", contentType)
+	return ci.client.Do(request)
+}
 
-	return client.Do(request)
+func (ci *clientStruct) Do(request *http.Request) (*http.Response, error) {
+	return ci.client.Do(request)
 }
@@ -0,0 +1,171 @@
+package restclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//TransportConfig holds the connection settings of a client
+type TransportConfig struct {
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	//MaxConnsPerHost limits the connections to one host, zero means no limit
+	MaxConnsPerHost       int
+	IdleConnTimeout       time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+	//Timeout bounds a whole request, reading the body included, zero means no limit
+	Timeout time.Duration
+	//Proxy is the url of the HTTP proxy, when empty HTTP_PROXY, HTTPS_PROXY and NO_PROXY decide
+	Proxy string
+	//CAFile is a PEM file of root certificates trusted instead of the system ones
+	CAFile string
+	//CertFile and KeyFile are the PEM client certificate and key presented to servers that ask for one
+	CertFile string
+	KeyFile  string
+	//UserAgent and Headers are set on the requests that do not have them, LogRequests logs every request
+	UserAgent   string
+	Headers     http.Header
+	LogRequests bool
+}
+
+var DefaultConfig = TransportConfig{
+	MaxIdleConns:          100,
+	MaxIdleConnsPerHost:   10,
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ResponseHeaderTimeout: 30 * time.Second,
+}
+
+//NewTransport builds the transport config describes, reading its certificate files
+func NewTransport(config TransportConfig) (*http.Transport, error) {
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          config.MaxIdleConns,
+		MaxIdleConnsPerHost:   config.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       config.MaxConnsPerHost,
+		IdleConnTimeout:       config.IdleConnTimeout,
+		TLSHandshakeTimeout:   config.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: config.ResponseHeaderTimeout,
+		ExpectContinueTimeout: time.Second,
+		TLSClientConfig:       &tls.Config{MinVersion: tls.VersionTLS12},
+	}
+	if config.Proxy != "" {
+		proxy, err := neturl.Parse(config.Proxy)
+		if err != nil || proxy.Host == "" {
+			return nil, fmt.Errorf("invalid proxy url %q", config.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	if config.CAFile != "" {
+		pem, err := ioutil.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", config.CAFile)
+		}
+		transport.TLSClientConfig.RootCAs = roots
+	}
+	if config.CertFile != "" || config.KeyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{certificate}
+	}
+	return transport, nil
+}
+
+//mustTransport is for the default config, which has no files to fail on
+func mustTransport(config TransportConfig) *http.Transport {
+	transport, err := NewTransport(config)
+	if err != nil {
+		panic(err)
+	}
+	return transport
+}
+
+//ConfigFromEnv overrides the settings of base with the environment variables named prefix followed by
+//MAX_IDLE_CONNS, MAX_IDLE_CONNS_PER_HOST, MAX_CONNS_PER_HOST, IDLE_CONN_TIMEOUT, TLS_HANDSHAKE_TIMEOUT,
+//RESPONSE_HEADER_TIMEOUT, TIMEOUT, PROXY, CA_FILE, CERT_FILE, KEY_FILE, USER_AGENT, HEADERS, such as
+//"X-Team: weather; X-Env: prod", or LOG_REQUESTS. set tells whether any was found
+func ConfigFromEnv(prefix string, base TransportConfig) (config TransportConfig, set bool, err error) {
+	config = base
+	ints := map[string]*int{
+		"MAX_IDLE_CONNS":          &config.MaxIdleConns,
+		"MAX_IDLE_CONNS_PER_HOST": &config.MaxIdleConnsPerHost,
+		"MAX_CONNS_PER_HOST":      &config.MaxConnsPerHost,
+	}
+	for name, field := range ints {
+		if value := os.Getenv(prefix + name); value != "" {
+			if *field, err = strconv.Atoi(value); err != nil || *field < 0 {
+				return base, false, fmt.Errorf("invalid %s%s %q", prefix, name, value)
+			}
+			set = true
+		}
+	}
+	durations := map[string]*time.Duration{
+		"IDLE_CONN_TIMEOUT":       &config.IdleConnTimeout,
+		"TLS_HANDSHAKE_TIMEOUT":   &config.TLSHandshakeTimeout,
+		"RESPONSE_HEADER_TIMEOUT": &config.ResponseHeaderTimeout,
+		"TIMEOUT":                 &config.Timeout,
+	}
+	for name, field := range durations {
+		if value := os.Getenv(prefix + name); value != "" {
+			if *field, err = time.ParseDuration(value); err != nil || *field < 0 {
+				return base, false, fmt.Errorf("invalid %s%s %q", prefix, name, value)
+			}
+			set = true
+		}
+	}
+	texts := map[string]*string{
+		"PROXY":      &config.Proxy,
+		"CA_FILE":    &config.CAFile,
+		"CERT_FILE":  &config.CertFile,
+		"KEY_FILE":   &config.KeyFile,
+		"USER_AGENT": &config.UserAgent,
+	}
+	for name, field := range texts {
+		if value := os.Getenv(prefix + name); value != "" {
+			*field = value
+			set = true
+		}
+	}
+	if value := os.Getenv(prefix + "HEADERS"); value != "" {
+		config.Headers = http.Header{}
+		for name, values := range base.Headers {
+			config.Headers[name] = values
+		}
+		for _, header := range strings.Split(value, ";") {
+			parts := strings.SplitN(header, ":", 2)
+			if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+				return base, false, fmt.Errorf("invalid %sHEADERS %q, expected \"Name: value; ...\"", prefix, value)
+			}
+			config.Headers.Set(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+		}
+		set = true
+	}
+	if value := os.Getenv(prefix + "LOG_REQUESTS"); value != "" {
+		if config.LogRequests, err = strconv.ParseBool(value); err != nil {
+			return base, false, fmt.Errorf("invalid %sLOG_REQUESTS %q", prefix, value)
+		}
+		set = true
+	}
+	return config, set, nil
+}
@@ -0,0 +1,154 @@
+package restclient
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTransportDefaults(t *testing.T) {
+	transport, err := NewTransport(DefaultConfig)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 10, transport.MaxIdleConnsPerHost)
+	assert.EqualValues(t, 90*time.Second, transport.IdleConnTimeout)
+	assert.Nil(t, transport.TLSClientConfig.RootCAs)
+}
+
+func TestNewClientCustomRootCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	//The test server certificate is not trusted by the system
+	client, err := NewClient(DefaultConfig)
+	assert.Nil(t, err)
+	_, err = client.Get(server.URL)
+	assert.NotNil(t, err)
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	assert.Nil(t, ioutil.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+	config := DefaultConfig
+	config.CAFile = caFile
+	client, err = NewClient(config)
+	assert.Nil(t, err)
+	response, err := client.Get(server.URL)
+	assert.Nil(t, err)
+	assert.EqualValues(t, http.StatusNoContent, response.StatusCode)
+	response.Body.Close()
+}
+
+func TestNewClientCertificate(t *testing.T) {
+	var presented int
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		presented = len(r.TLS.PeerCertificates)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	//The server certificate doubles as the client one
+	dir := t.TempDir()
+	key, err := x509.MarshalPKCS8PrivateKey(server.TLS.Certificates[0].PrivateKey)
+	assert.Nil(t, err)
+	config := DefaultConfig
+	config.CAFile, config.CertFile, config.KeyFile = filepath.Join(dir, "ca.pem"), filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	assert.Nil(t, ioutil.WriteFile(config.CAFile, certificate, 0600))
+	assert.Nil(t, ioutil.WriteFile(config.CertFile, certificate, 0600))
+	assert.Nil(t, ioutil.WriteFile(config.KeyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key}), 0600))
+
+	client, err := NewClient(config)
+	assert.Nil(t, err)
+	response, err := client.Get(server.URL)
+	assert.Nil(t, err)
+	response.Body.Close()
+	assert.EqualValues(t, 1, presented)
+}
+
+func TestNewClientProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	config := DefaultConfig
+	config.Proxy = proxy.URL
+	client, err := NewClient(config)
+	assert.Nil(t, err)
+	response, err := client.Get("http://weather.invalid/forecast?lat=1")
+	assert.Nil(t, err)
+	response.Body.Close()
+	assert.EqualValues(t, "http://weather.invalid/forecast?lat=1", proxied)
+}
+
+func TestNewTransportInvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+	notPem := filepath.Join(dir, "ca.pem")
+	assert.Nil(t, ioutil.WriteFile(notPem, []byte("not a certificate"), 0600))
+
+	_, err := NewTransport(TransportConfig{CAFile: notPem})
+	assert.EqualValues(t, "no certificate found in "+notPem, err.Error())
+	_, err = NewTransport(TransportConfig{CAFile: filepath.Join(dir, "missing.pem")})
+	assert.NotNil(t, err)
+	_, err = NewTransport(TransportConfig{CertFile: notPem, KeyFile: notPem})
+	assert.NotNil(t, err)
+	_, err = NewTransport(TransportConfig{Proxy: "not a url"})
+	assert.EqualValues(t, `invalid proxy url "not a url"`, err.Error())
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	os.Setenv("TEST_HTTP_MAX_CONNS_PER_HOST", "4")
+	os.Setenv("TEST_HTTP_TIMEOUT", "5s")
+	os.Setenv("TEST_HTTP_PROXY", "http://proxy:3128")
+	os.Setenv("TEST_HTTP_HEADERS", "X-Team: weather; X-Env: prod")
+	os.Setenv("TEST_HTTP_LOG_REQUESTS", "true")
+	defer os.Unsetenv("TEST_HTTP_MAX_CONNS_PER_HOST")
+	defer os.Unsetenv("TEST_HTTP_HEADERS")
+	defer os.Unsetenv("TEST_HTTP_LOG_REQUESTS")
+	defer os.Unsetenv("TEST_HTTP_TIMEOUT")
+	defer os.Unsetenv("TEST_HTTP_PROXY")
+
+	config, set, err := ConfigFromEnv("TEST_HTTP_", DefaultConfig)
+	assert.Nil(t, err)
+	assert.True(t, set)
+	assert.EqualValues(t, 4, config.MaxConnsPerHost)
+	assert.EqualValues(t, 5*time.Second, config.Timeout)
+	assert.EqualValues(t, "http://proxy:3128", config.Proxy)
+	assert.EqualValues(t, http.Header{"X-Team": {"weather"}, "X-Env": {"prod"}}, config.Headers)
+	assert.True(t, config.LogRequests)
+	//What is not set keeps the base value
+	assert.EqualValues(t, DefaultConfig.MaxIdleConns, config.MaxIdleConns)
+
+	_, set, err = ConfigFromEnv("OTHER_HTTP_", DefaultConfig)
+	assert.Nil(t, err)
+	assert.False(t, set)
+
+	os.Setenv("TEST_HTTP_HEADERS", "no colon")
+	_, _, err = ConfigFromEnv("TEST_HTTP_", DefaultConfig)
+	assert.NotNil(t, err)
+	os.Setenv("TEST_HTTP_HEADERS", "X-Team: weather")
+
+	os.Setenv("TEST_HTTP_TIMEOUT", "soon")
+	_, _, err = ConfigFromEnv("TEST_HTTP_", DefaultConfig)
+	assert.EqualValues(t, `invalid TEST_HTTP_TIMEOUT "soon"`, err.Error())
+}
+
+func TestFor(t *testing.T) {
+	defer func() { Clients = map[string]ClientInterface{} }()
+	own, _ := NewClient(DefaultConfig)
+	Clients["darksky"] = own
+	assert.Equal(t, own, For("darksky"))
+	assert.Equal(t, ClientStruct, For("openmeteo"))
+}
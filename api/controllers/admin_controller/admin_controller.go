@@ -0,0 +1,48 @@
+package admin_controller
+
+import (
+	"interface-testing/api/domain/client_domain"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+//CreateKey issues a key to a new client, the response is the only place the key ever appears
+func CreateKey(c *gin.Context) {
+	var request client_domain.ClientRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		apiErr := weather_domain.NewBadRequestError("invalid json body")
+		c.JSON(apiErr.Status(), apiErr)
+		return
+	}
+	issued, apiErr := services.ClientService.Create(request)
+	if apiErr != nil {
+		c.JSON(apiErr.Status(), apiErr)
+		return
+	}
+	c.JSON(http.StatusCreated, issued)
+}
+
+func ListKeys(c *gin.Context) {
+	c.JSON(http.StatusOK, services.ClientService.List())
+}
+
+func RotateKey(c *gin.Context) {
+	issued, apiErr := services.ClientService.Rotate(c.Param("id"))
+	if apiErr != nil {
+		c.JSON(apiErr.Status(), apiErr)
+		return
+	}
+	c.JSON(http.StatusOK, issued)
+}
+
+func RevokeKey(c *gin.Context) {
+	client, apiErr := services.ClientService.Revoke(c.Param("id"))
+	if apiErr != nil {
+		c.JSON(apiErr.Status(), apiErr)
+		return
+	}
+	c.JSON(http.StatusOK, client)
+}
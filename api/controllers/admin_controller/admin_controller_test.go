@@ -0,0 +1,79 @@
+package admin_controller
+
+import (
+	"encoding/json"
+	"interface-testing/api/domain/client_domain"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/stores/client_store"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+//adminRequest runs handler against the real service with an in-memory store
+func adminRequest(handler gin.HandlerFunc, method string, id string, body string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(response)
+	c.Request, _ = http.NewRequest(method, "/admin/keys", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: id}}
+	handler(c)
+	return response
+}
+
+func TestAdminKeyLifecycle(t *testing.T) {
+	client_store.ClientStore, _ = client_store.NewFileStore("")
+
+	response := adminRequest(CreateKey, http.MethodPost, "", `{"owner": "mobile team", "tier": "standard", "expiresAt": "2099-01-01T00:00:00Z"}`)
+	assert.EqualValues(t, http.StatusCreated, response.Code)
+	var issued client_domain.IssuedKey
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &issued))
+	assert.NotEmpty(t, issued.Key)
+	assert.NotEmpty(t, issued.Id)
+	assert.EqualValues(t, "standard", issued.Tier)
+	assert.EqualValues(t, 2099, issued.ExpiresAt.Year())
+
+	response = adminRequest(ListKeys, http.MethodGet, "", "")
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	//Neither the key nor its hash are ever listed
+	assert.NotContains(t, response.Body.String(), issued.Key)
+	assert.NotContains(t, response.Body.String(), "keyHash")
+	var clients []client_domain.Client
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &clients))
+	assert.EqualValues(t, 1, len(clients))
+	assert.EqualValues(t, "mobile team", clients[0].Owner)
+
+	response = adminRequest(RotateKey, http.MethodPost, issued.Id, "")
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	var rotated client_domain.IssuedKey
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &rotated))
+	assert.NotEqual(t, issued.Key, rotated.Key)
+
+	response = adminRequest(RevokeKey, http.MethodDelete, issued.Id, "")
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), "revokedAt")
+}
+
+func TestAdminCreateKeyInvalid(t *testing.T) {
+	client_store.ClientStore, _ = client_store.NewFileStore("")
+
+	response := adminRequest(CreateKey, http.MethodPost, "", `{"owner": `)
+	assert.EqualValues(t, http.StatusBadRequest, response.Code)
+	apiErr, err := weather_domain.NewApiErrFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, "invalid json body", apiErr.Message())
+
+	response = adminRequest(CreateKey, http.MethodPost, "", `{"tier": "free"}`)
+	assert.EqualValues(t, http.StatusBadRequest, response.Code)
+}
+
+func TestAdminUnknownKey(t *testing.T) {
+	client_store.ClientStore, _ = client_store.NewFileStore("")
+
+	assert.EqualValues(t, http.StatusNotFound, adminRequest(RotateKey, http.MethodPost, "unknown", "").Code)
+	assert.EqualValues(t, http.StatusNotFound, adminRequest(RevokeKey, http.MethodDelete, "unknown", "").Code)
+}
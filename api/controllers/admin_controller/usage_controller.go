@@ -0,0 +1,47 @@
+package admin_controller
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"interface-testing/api/domain/client_domain"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/services"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+//ExportUsage returns the usage per client and day between ?from= and ?to= (both included, formatted as 2006-01-02)
+//as JSON, or as CSV with ?format=csv
+func ExportUsage(c *gin.Context) {
+	from, to := c.Query("from"), c.Query("to")
+	for _, day := range []string{from, to} {
+		if _, err := time.Parse(client_domain.DayFormat, day); day != "" && err != nil {
+			apiErr := weather_domain.NewBadRequestError(fmt.Sprintf("invalid day %s, expected %s", day, client_domain.DayFormat))
+			c.JSON(apiErr.Status(), apiErr)
+			return
+		}
+	}
+	records := services.UsageService.Export(from, to)
+	switch c.DefaultQuery("format", "json") {
+	case "json":
+		c.JSON(http.StatusOK, records)
+	case "csv":
+		var body bytes.Buffer
+		writer := csv.NewWriter(&body)
+		writer.Write([]string{"day", "clientId", "owner", "tier", "requests", "upstreamCalls", "cacheHits"})
+		for _, record := range records {
+			writer.Write([]string{record.Day, record.ClientId, record.Owner, record.Tier,
+				strconv.FormatInt(record.Requests, 10), strconv.FormatInt(record.UpstreamCalls, 10), strconv.FormatInt(record.CacheHits, 10)})
+		}
+		writer.Flush()
+		c.Header("Content-Disposition", `attachment; filename="usage.csv"`)
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", body.Bytes())
+	default:
+		apiErr := weather_domain.NewBadRequestError("format must be json or csv")
+		c.JSON(apiErr.Status(), apiErr)
+	}
+}
@@ -0,0 +1,58 @@
+package admin_controller
+
+import (
+	"encoding/json"
+	"interface-testing/api/domain/client_domain"
+	"interface-testing/api/stores/client_store"
+	"interface-testing/api/stores/usage_store"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func exportUsage(url string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(response)
+	c.Request, _ = http.NewRequest(http.MethodGet, url, nil)
+	ExportUsage(c)
+	return response
+}
+
+func usageFixture() {
+	client_store.ClientStore, _ = client_store.NewFileStore("")
+	client_store.ClientStore.Save(client_domain.Client{Id: "abc", Owner: "mobile, ios", Tier: client_domain.TierStandard})
+	usage_store.UsageStore, _ = usage_store.NewFileStore("")
+	usage_store.UsageStore.Add("abc", "2020-03-01", client_domain.Usage{Requests: 3, UpstreamCalls: 1, CacheHits: 2})
+	usage_store.UsageStore.Add("abc", "2020-04-01", client_domain.Usage{Requests: 1, UpstreamCalls: 1})
+}
+
+func TestExportUsageJson(t *testing.T) {
+	usageFixture()
+	response := exportUsage("/admin/usage?from=2020-03-01&to=2020-03-31")
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	var records []client_domain.UsageRecord
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &records))
+	assert.EqualValues(t, []client_domain.UsageRecord{{
+		ClientId: "abc", Owner: "mobile, ios", Tier: "standard", Day: "2020-03-01",
+		Usage: client_domain.Usage{Requests: 3, UpstreamCalls: 1, CacheHits: 2},
+	}}, records)
+}
+
+func TestExportUsageCsv(t *testing.T) {
+	usageFixture()
+	response := exportUsage("/admin/usage?format=csv")
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	assert.EqualValues(t, "text/csv; charset=utf-8", response.Header().Get("Content-Type"))
+	assert.EqualValues(t, "day,clientId,owner,tier,requests,upstreamCalls,cacheHits\n"+
+		"2020-03-01,abc,\"mobile, ios\",standard,3,1,2\n"+
+		"2020-04-01,abc,\"mobile, ios\",standard,1,1,0\n", response.Body.String())
+}
+
+func TestExportUsageInvalid(t *testing.T) {
+	usageFixture()
+	assert.EqualValues(t, http.StatusBadRequest, exportUsage("/admin/usage?from=March").Code)
+	assert.EqualValues(t, http.StatusBadRequest, exportUsage("/admin/usage?format=xml").Code)
+}
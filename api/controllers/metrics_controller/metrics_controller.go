@@ -0,0 +1,15 @@
+package metrics_controller
+
+import (
+	"interface-testing/api/metrics"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+//GetMetrics exposes the service counters in the Prometheus text format
+func GetMetrics(c *gin.Context) {
+	c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.Status(http.StatusOK)
+	metrics.WriteText(c.Writer)
+}
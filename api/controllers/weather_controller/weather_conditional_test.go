@@ -0,0 +1,79 @@
+package weather_controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func getWeatherWith(url string, headers map[string]string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(response)
+	c.Request, _ = http.NewRequest(http.MethodGet, url, nil)
+	for name, value := range headers {
+		c.Request.Header.Set(name, value)
+	}
+	c.Params = gin.Params{
+		{Key: "apiKey", Value: "right_api_key"},
+		{Key: "latitude", Value: "20.34"},
+		{Key: "longitude", Value: "-12.44"},
+	}
+	GetWeather(c)
+	return response
+}
+
+func TestGetWeatherValidators(t *testing.T) {
+	mockOvercast()
+	response := getWeatherWith("/weather", nil)
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	etag := response.Header().Get("ETag")
+	assert.Regexp(t, `^"[A-Za-z0-9_-]{43}"$`, etag)
+	assert.EqualValues(t, "Sun, 01 Mar 2020 12:00:00 GMT", response.Header().Get("Last-Modified"))
+
+	//The same weather gets the same ETag, another format another one
+	assert.EqualValues(t, etag, getWeatherWith("/weather", nil).Header().Get("ETag"))
+	assert.NotEqual(t, etag, getWeatherWith("/weather?format=xml", nil).Header().Get("ETag"))
+}
+
+func TestGetWeatherIfNoneMatch(t *testing.T) {
+	mockOvercast()
+	etag := getWeatherWith("/weather", nil).Header().Get("ETag")
+
+	response := getWeatherWith("/weather", map[string]string{"If-None-Match": `"other", ` + etag})
+	assert.EqualValues(t, http.StatusNotModified, response.Code)
+	assert.EqualValues(t, 0, response.Body.Len())
+	assert.EqualValues(t, etag, response.Header().Get("ETag"))
+
+	response = getWeatherWith("/weather", map[string]string{"If-None-Match": "W/" + etag})
+	assert.EqualValues(t, http.StatusNotModified, response.Code)
+
+	response = getWeatherWith("/weather", map[string]string{"If-None-Match": `"other"`})
+	assert.EqualValues(t, http.StatusOK, response.Code)
+
+	//A matching date does not help when the ETag does not match
+	response = getWeatherWith("/weather", map[string]string{"If-None-Match": `"other"`, "If-Modified-Since": "Sun, 01 Mar 2020 12:00:00 GMT"})
+	assert.EqualValues(t, http.StatusOK, response.Code)
+}
+
+func TestGetWeatherIfModifiedSince(t *testing.T) {
+	mockOvercast()
+	response := getWeatherWith("/weather", map[string]string{"If-Modified-Since": "Sun, 01 Mar 2020 12:00:00 GMT"})
+	assert.EqualValues(t, http.StatusNotModified, response.Code)
+
+	response = getWeatherWith("/weather", map[string]string{"If-Modified-Since": "Sun, 01 Mar 2020 11:59:59 GMT"})
+	assert.EqualValues(t, http.StatusOK, response.Code)
+
+	response = getWeatherWith("/weather", map[string]string{"If-Modified-Since": "yesterday"})
+	assert.EqualValues(t, http.StatusOK, response.Code)
+}
+
+func TestGetWeatherErrorHasNoValidators(t *testing.T) {
+	mockOvercast()
+	getWeatheFunc = nil
+	response := getWeatherWith("/weather?ensemble=true&method=mode", map[string]string{"If-None-Match": "*"})
+	assert.EqualValues(t, http.StatusBadRequest, response.Code)
+	assert.EqualValues(t, "", response.Header().Get("ETag"))
+}
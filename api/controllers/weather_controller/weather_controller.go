@@ -1,25 +1,75 @@
 package weather_controller
 
 import (
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/middleware"
+	"interface-testing/api/render"
 	"interface-testing/api/services"
 	"net/http"
 	"strconv"
+	"time"
 )
 func GetWeather(c *gin.Context){
 	long, _ := strconv.ParseFloat(c.Param("longitude"), 64)
 	lat, _ := strconv.ParseFloat(c.Param("latitude"), 64)
 	request :=  weather_domain.WeatherRequest{
-		ApiKey:    c.Param("apiKey"),
-		Latitude:  lat,
-		Longitude: long,
+		ApiKey:     c.Param("apiKey"),
+		Latitude:   lat,
+		Longitude:  long,
+		Units:      c.Query("units"),
+		Lang:       render.Language(c),
+		AirQuality: c.Query("airQuality") == "true",
 	}
-	result, apiError := services.WeatherService.GetWeather(request)
+	ensemble, apiError := ensembleMethod(c)
 	if apiError != nil {
-		c.JSON(apiError.Status(), apiError)
+		render.Render(c, apiError.Status(), apiError)
 		return
 	}
-	c.JSON(http.StatusOK, result)
+	request.Ensemble = ensemble
+	result, apiError := services.WeatherService.GetWeather(c.Request.Context(), request)
+	if apiError != nil {
+		render.Render(c, apiError.Status(), apiError)
+		return
+	}
+	if client := middleware.Client(c); client != nil {
+		services.UsageService.Served(client.Id, result.Cache)
+	}
+	cacheHeaders(c, result.Cache)
+	render.Render(c, http.StatusOK, result)
+}
+
+//ensembleMethod is the merge method asked for with ?ensemble=true&method=, empty when a single provider will do
+func ensembleMethod(c *gin.Context) (string, weather_domain.WeatherErrorInterface) {
+	if c.Query("ensemble") != "true" {
+		return "", nil
+	}
+	method := c.DefaultQuery("method", weather_domain.EnsembleMedian)
+	if method != weather_domain.EnsembleMedian && method != weather_domain.EnsembleMean {
+		return "", weather_domain.NewBadRequestError("invalid ensemble method")
+	}
+	return method, nil
+}
+
+//cacheHeaders tells the client how the response was served, how long it may keep it and, when it came from
+//the cache, how old it is.
+//Expired responses carry a Warning: 110 while they are being refreshed, 111 when the refresh failed
+func cacheHeaders(c *gin.Context, cache *weather_domain.CacheInfo) {
+	if cache == nil {
+		return
+	}
+	c.Header("X-Cache", cache.Status)
+	c.Header("Cache-Control", fmt.Sprintf("max-age=%d", int64(cache.MaxAge/time.Second)))
+	if cache.Status == weather_domain.CacheMiss {
+		return
+	}
+	c.Header("Age", strconv.FormatInt(int64(cache.Age/time.Second), 10))
+	switch cache.Status {
+	case weather_domain.CacheStale:
+		c.Header("Warning", `110 - "Response is Stale"`)
+	case weather_domain.CacheStaleIfError:
+		c.Header("Warning", `111 - "Revalidation Failed"`)
+	}
 }
 
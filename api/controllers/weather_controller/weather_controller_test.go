@@ -1,13 +1,18 @@
 package weather_controller
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"interface-testing/api/domain/client_domain"
 	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/middleware"
 	"interface-testing/api/services"
+	"interface-testing/api/stores/usage_store"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -20,7 +25,7 @@ var (
 type weatherServiceMock struct{}
 
 //We are mocking the service method "GetWeather"
-func (w *weatherServiceMock) GetWeather(request weather_domain.WeatherRequest) (*weather_domain.Weather, weather_domain.WeatherErrorInterface) {
+func (w *weatherServiceMock) GetWeather(ctx context.Context, request weather_domain.WeatherRequest) (*weather_domain.Weather, weather_domain.WeatherErrorInterface) {
 	return getWeatheFunc(request)
 }
 
@@ -175,3 +180,119 @@ func TestGetWeatherSuccess(t *testing.T) {
 	assert.EqualValues(t, 32.37, weather.Currently.DewPoint)
 	assert.EqualValues(t, "Overcast", weather.Currently.Summary)
 }
+
+func TestGetWeatherEnsemble(t *testing.T) {
+	var asked weather_domain.WeatherRequest
+	getWeatheFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, weather_domain.WeatherErrorInterface) {
+		asked = request
+		return &weather_domain.Weather{Provider: "ensemble"}, nil
+	}
+	services.WeatherService = &weatherServiceMock{}
+
+	response := getWeatherAs("/weather?ensemble=true", "")
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	assert.EqualValues(t, weather_domain.EnsembleMedian, asked.Ensemble)
+
+	response = getWeatherAs("/weather?ensemble=true&method=mean", "")
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	assert.EqualValues(t, weather_domain.EnsembleMean, asked.Ensemble)
+
+	response = getWeatherAs("/weather", "")
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	assert.EqualValues(t, "", asked.Ensemble)
+}
+
+func TestGetWeatherEnsembleInvalidMethod(t *testing.T) {
+	getWeatheFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, weather_domain.WeatherErrorInterface) {
+		t.Fatal("the service must not be called")
+		return nil, nil
+	}
+	services.WeatherService = &weatherServiceMock{}
+
+	response := getWeatherAs("/weather?ensemble=true&method=mode", "")
+	assert.EqualValues(t, http.StatusBadRequest, response.Code)
+	apiErr, err := weather_domain.NewApiErrFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, "invalid ensemble method", apiErr.Message())
+}
+
+func TestGetWeatherAirQuality(t *testing.T) {
+	var asked weather_domain.WeatherRequest
+	pm25 := 12.0
+	getWeatheFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, weather_domain.WeatherErrorInterface) {
+		asked = request
+		return &weather_domain.Weather{AirQuality: &weather_domain.AirQualityInfo{
+			Pollutants: weather_domain.PollutantsInfo{PM25: &pm25},
+			Aqi:        weather_domain.NewAqiInfo(weather_domain.PollutantsInfo{PM25: &pm25}),
+		}}, nil
+	}
+	services.WeatherService = &weatherServiceMock{}
+
+	response := getWeatherAs("/weather?airQuality=true", "")
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	assert.True(t, asked.AirQuality)
+	var weather weather_domain.Weather
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &weather))
+	assert.EqualValues(t, 12, *weather.AirQuality.Pollutants.PM25)
+	assert.EqualValues(t, 56, weather.AirQuality.Aqi.Epa.Value)
+	assert.EqualValues(t, "pm2_5", weather.AirQuality.Aqi.Epa.Dominant)
+	assert.EqualValues(t, weather_domain.EaqiFair, weather.AirQuality.Aqi.Eu.Category)
+
+	getWeatherAs("/weather", "")
+	assert.False(t, asked.AirQuality)
+}
+
+func TestGetWeatherCacheHeaders(t *testing.T) {
+	cacheInfo := &weather_domain.CacheInfo{Status: weather_domain.CacheMiss}
+	getWeatheFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, weather_domain.WeatherErrorInterface) {
+		return &weather_domain.Weather{Cache: cacheInfo}, nil
+	}
+	services.WeatherService = &weatherServiceMock{}
+
+	response := getWeatherAs("/weather", "")
+	assert.EqualValues(t, "MISS", response.Header().Get("X-Cache"))
+	assert.EqualValues(t, "", response.Header().Get("Age"))
+	assert.EqualValues(t, "max-age=0", response.Header().Get("Cache-Control"))
+
+	cacheInfo = &weather_domain.CacheInfo{Status: weather_domain.CacheMiss, MaxAge: 5 * time.Minute}
+	response = getWeatherAs("/weather", "")
+	assert.EqualValues(t, "max-age=300", response.Header().Get("Cache-Control"))
+
+	cacheInfo = &weather_domain.CacheInfo{Status: weather_domain.CacheHit, Age: 42500 * time.Millisecond, MaxAge: 257500 * time.Millisecond}
+	response = getWeatherAs("/weather", "")
+	assert.EqualValues(t, "HIT", response.Header().Get("X-Cache"))
+	assert.EqualValues(t, "42", response.Header().Get("Age"))
+	assert.EqualValues(t, "max-age=257", response.Header().Get("Cache-Control"))
+	assert.EqualValues(t, "", response.Header().Get("Warning"))
+	assert.NotContains(t, response.Body.String(), "HIT")
+
+	cacheInfo = &weather_domain.CacheInfo{Status: weather_domain.CacheStale, Age: 400 * time.Second}
+	response = getWeatherAs("/weather", "")
+	assert.EqualValues(t, "STALE", response.Header().Get("X-Cache"))
+	assert.EqualValues(t, "400", response.Header().Get("Age"))
+	assert.EqualValues(t, `110 - "Response is Stale"`, response.Header().Get("Warning"))
+	assert.EqualValues(t, "max-age=0", response.Header().Get("Cache-Control"))
+
+	cacheInfo = &weather_domain.CacheInfo{Status: weather_domain.CacheStaleIfError, Age: time.Hour}
+	response = getWeatherAs("/weather", "")
+	assert.EqualValues(t, "3600", response.Header().Get("Age"))
+	assert.EqualValues(t, `111 - "Revalidation Failed"`, response.Header().Get("Warning"))
+}
+
+func TestGetWeatherRecordsUsage(t *testing.T) {
+	getWeatheFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, weather_domain.WeatherErrorInterface) {
+		return &weather_domain.Weather{Cache: &weather_domain.CacheInfo{Status: weather_domain.CacheHit}}, nil
+	}
+	services.WeatherService = &weatherServiceMock{}
+	usage_store.UsageStore, _ = usage_store.NewFileStore("")
+
+	response := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(response)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/weather", nil)
+	c.Set(middleware.ClientContextKey, &client_domain.Client{Id: "abc"})
+	GetWeather(c)
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	usage := usage_store.UsageStore.Month("abc", time.Now().UTC().Format("2006-01"))
+	assert.EqualValues(t, 1, usage.CacheHits)
+	assert.EqualValues(t, 0, usage.UpstreamCalls)
+}
@@ -0,0 +1,82 @@
+package weather_controller
+
+import (
+	"fmt"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/i18n"
+	"interface-testing/api/middleware"
+	"interface-testing/api/render"
+	"interface-testing/api/services"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+//GetWeatherGrid samples the weather over "bbox=west,south,east,north" every "resolution" degrees
+func GetWeatherGrid(c *gin.Context) {
+	request, apiError := gridRequest(c)
+	if apiError != nil {
+		render.Render(c, apiError.Status(), apiError)
+		return
+	}
+	grid, apiError := services.WeatherGridService.GetGrid(c.Request.Context(), request)
+	if apiError != nil {
+		render.Render(c, apiError.Status(), apiError)
+		return
+	}
+	lang := render.Language(c)
+	for _, cell := range grid.Cells {
+		if cell.Error != nil {
+			cell.Error.ErrorMessage = i18n.Translate(lang, cell.Error.ErrorMessage)
+		}
+	}
+	if client := middleware.Client(c); client != nil {
+		for _, cell := range grid.Cells {
+			if cell.Weather != nil {
+				services.UsageService.Served(client.Id, cell.Weather.Cache)
+			}
+		}
+	}
+	render.Render(c, http.StatusOK, grid)
+}
+
+func gridRequest(c *gin.Context) (weather_domain.GridRequest, weather_domain.WeatherErrorInterface) {
+	var request weather_domain.GridRequest
+	bounds := strings.Split(c.Query("bbox"), ",")
+	if len(bounds) != 4 {
+		return request, weather_domain.NewBadRequestError("bbox must be west,south,east,north")
+	}
+	var corners [4]float64
+	for i, bound := range bounds {
+		value, err := strconv.ParseFloat(strings.TrimSpace(bound), 64)
+		if err != nil {
+			return request, weather_domain.NewBadRequestError(fmt.Sprintf("invalid bbox %q", c.Query("bbox")))
+		}
+		corners[i] = value
+	}
+	resolution, err := strconv.ParseFloat(c.Query("resolution"), 64)
+	if err != nil {
+		return request, weather_domain.NewBadRequestError("resolution must be a positive number of degrees")
+	}
+	ensemble, apiError := ensembleMethod(c)
+	if apiError != nil {
+		return request, apiError
+	}
+	request = weather_domain.GridRequest{
+		Request: weather_domain.WeatherRequest{
+			ApiKey:   c.Param("apiKey"),
+			Units:    c.Query("units"),
+			Lang:     render.Language(c),
+			Ensemble: ensemble,
+		},
+		West:       corners[0],
+		South:      corners[1],
+		East:       corners[2],
+		North:      corners[3],
+		Resolution: resolution,
+		Client:     middleware.Client(c),
+	}
+	return request, nil
+}
@@ -0,0 +1,100 @@
+package weather_controller
+
+import (
+	"encoding/json"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func getWeatherGrid(url string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(response)
+	c.Request, _ = http.NewRequest(http.MethodGet, url, nil)
+	c.Params = gin.Params{{Key: "apiKey", Value: "right_api_key"}}
+	GetWeatherGrid(c)
+	return response
+}
+
+//mockGridPoints answers every point with its own latitude as temperature, except the failing longitude
+func mockGridPoints(failing float64) {
+	getWeatheFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, weather_domain.WeatherErrorInterface) {
+		if request.Longitude == failing {
+			return nil, weather_domain.NewWeatherError(http.StatusBadGateway, "upstream failed")
+		}
+		return &weather_domain.Weather{Latitude: request.Latitude, Longitude: request.Longitude, Currently: weather_domain.CurrentlyInfo{Temperature: request.Latitude}}, nil
+	}
+	services.WeatherService = &weatherServiceMock{}
+}
+
+func TestGetWeatherGrid(t *testing.T) {
+	mockGridPoints(-72)
+	response := getWeatherGrid("/weather/right_api_key/grid?bbox=-73,40,-72,41.5&resolution=0.5")
+	assert.EqualValues(t, http.StatusOK, response.Code)
+
+	var grid weather_domain.Grid
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &grid))
+	assert.EqualValues(t, 4, grid.Rows)
+	assert.EqualValues(t, 3, grid.Columns)
+	assert.Len(t, grid.Cells, 12)
+	assert.EqualValues(t, 40.5, grid.Cells[3].Latitude)
+	assert.EqualValues(t, -73, grid.Cells[3].Longitude)
+	assert.EqualValues(t, 40.5, grid.Cells[3].Weather.Currently.Temperature)
+	assert.Nil(t, grid.Cells[5].Weather)
+	assert.EqualValues(t, "upstream failed", grid.Cells[5].Error.ErrorMessage)
+}
+
+func TestGetWeatherGridCsv(t *testing.T) {
+	mockGridPoints(-72)
+	response := getWeatherGrid("/weather/right_api_key/grid?bbox=-73,40,-72,40&resolution=1&format=csv")
+	assert.EqualValues(t, http.StatusOK, response.Code)
+
+	lines := strings.Split(strings.TrimSpace(response.Body.String()), "\n")
+	//One row per cell under one header
+	assert.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "row,column,latitude,longitude,weather.latitude,"))
+	assert.True(t, strings.HasPrefix(lines[1], "0,0,40,-73,40,-73,"))
+	assert.True(t, strings.HasSuffix(lines[2], ",502,upstream failed"))
+}
+
+func TestGetWeatherGridProtobuf(t *testing.T) {
+	mockGridPoints(-72)
+	response := getWeatherGrid("/weather/right_api_key/grid?bbox=-73,40,-72,40&resolution=1&format=protobuf")
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	assert.EqualValues(t, "application/x-protobuf", response.Header().Get("Content-Type"))
+
+	var grid weather_domain.GridMessage
+	assert.Nil(t, proto.Unmarshal(response.Body.Bytes(), &grid))
+	assert.EqualValues(t, 1, grid.Rows)
+	assert.EqualValues(t, 2, grid.Columns)
+	assert.Len(t, grid.Cells, 2)
+	assert.EqualValues(t, 40, grid.Cells[0].Weather.Currently.Temperature)
+	assert.Nil(t, grid.Cells[0].Error)
+	assert.Nil(t, grid.Cells[1].Weather)
+	assert.EqualValues(t, http.StatusBadGateway, grid.Cells[1].Error.Code)
+}
+
+func TestGetWeatherGridInvalid(t *testing.T) {
+	mockGridPoints(0)
+	for url, message := range map[string]string{
+		"/weather/right_api_key/grid?resolution=1":                        "bbox must be west,south,east,north",
+		"/weather/right_api_key/grid?bbox=-73,40,-72,north&resolution=1":  `invalid bbox "-73,40,-72,north"`,
+		"/weather/right_api_key/grid?bbox=-73,40,-72,41":                  "resolution must be a positive number of degrees",
+		"/weather/right_api_key/grid?bbox=-73,40,-72,41&resolution=-1":    "resolution must be a positive number of degrees",
+		"/weather/right_api_key/grid?bbox=-73,40,-72,41&resolution=NaN":   "resolution must be a positive number of degrees",
+		"/weather/right_api_key/grid?bbox=-180,-90,180,90&resolution=0.1": "grid of 1801 x 3601 points exceeds the limit of 100 points, use a coarser resolution or a smaller box",
+	} {
+		response := getWeatherGrid(url)
+		assert.EqualValues(t, http.StatusBadRequest, response.Code, url)
+		apiErr, err := weather_domain.NewApiErrFromBytes(response.Body.Bytes())
+		assert.Nil(t, err)
+		assert.EqualValues(t, message, apiErr.Message(), url)
+	}
+}
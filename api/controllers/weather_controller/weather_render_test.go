@@ -0,0 +1,157 @@
+package weather_controller
+
+import (
+	"encoding/xml"
+	"fmt"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func getWeatherAs(url string, accept string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(response)
+	c.Request, _ = http.NewRequest(http.MethodGet, url, nil)
+	if accept != "" {
+		c.Request.Header.Set("Accept", accept)
+	}
+	c.Params = gin.Params{
+		{Key: "apiKey", Value: "right_api_key"},
+		{Key: "latitude", Value: fmt.Sprintf("%f", 20.34)},
+		{Key: "longitude", Value: fmt.Sprintf("%f", -12.44)},
+	}
+	GetWeather(c)
+	return response
+}
+
+func mockOvercast() {
+	getWeatheFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, weather_domain.WeatherErrorInterface) {
+		return &weather_domain.Weather{
+			Latitude:  20.34,
+			Longitude: -12.44,
+			TimeZone:  "Africa/Nouakchott",
+			Currently: weather_domain.CurrentlyInfo{
+				Temperature: 78.02,
+				Summary:     "Overcast",
+				DewPoint:    32.37,
+				Pressure:    1014.1,
+				Humidity:    0.19,
+				WindSpeed:   3.4,
+				Time:        1583064000,
+			},
+			Comfort: &weather_domain.ComfortInfo{ApparentTemperature: 76.3, HeatIndex: 78, WindChill: 78, Humidex: 78, Category: weather_domain.ComfortComfortable},
+		}, nil
+	}
+	services.WeatherService = &weatherServiceMock{}
+}
+
+func TestGetWeatherXml(t *testing.T) {
+	mockOvercast()
+	response := getWeatherAs("/weather", "application/xml")
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Header().Get("Content-Type"), "application/xml")
+
+	var weather weather_domain.Weather
+	assert.Nil(t, xml.Unmarshal(response.Body.Bytes(), &weather))
+	assert.EqualValues(t, 20.34, weather.Latitude)
+	assert.EqualValues(t, "Overcast", weather.Currently.Summary)
+	assert.EqualValues(t, 1014.1, weather.Currently.Pressure)
+}
+
+func TestGetWeatherXmlError(t *testing.T) {
+	getWeatheFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, weather_domain.WeatherErrorInterface) {
+		return nil, weather_domain.NewForbiddenError("permission denied")
+	}
+	services.WeatherService = &weatherServiceMock{}
+	response := getWeatherAs("/weather?format=xml", "")
+	assert.EqualValues(t, http.StatusForbidden, response.Code)
+	assert.EqualValues(t, "<WeatherError><code>403</code><error>permission denied</error></WeatherError>", response.Body.String())
+}
+
+func TestGetWeatherCsv(t *testing.T) {
+	mockOvercast()
+	response := getWeatherAs("/weather?format=csv", "application/json")
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	assert.EqualValues(t, "text/csv; charset=utf-8", response.Header().Get("Content-Type"))
+	assert.EqualValues(t, "latitude,longitude,timezone,currently.temperature,currently.summary,currently.dewPoint,currently.pressure,currently.humidity,currently.windSpeed,currently.windGust,currently.time,currently.unavailable,"+
+		"localTime.observedUtc,localTime.observedLocal,localTime.utcOffset,localTime.abbreviation,localTime.dst,localTime.timeZoneDerived,"+
+		"comfort.apparentTemperature,comfort.heatIndex,comfort.windChill,comfort.humidex,comfort.category,"+
+		"astronomy.sunrise,astronomy.sunset,astronomy.solarNoon,astronomy.dayLength,astronomy.civilTwilight.dawn,astronomy.civilTwilight.dusk,"+
+		"astronomy.nauticalTwilight.dawn,astronomy.nauticalTwilight.dusk,astronomy.astronomicalTwilight.dawn,astronomy.astronomicalTwilight.dusk,"+
+		"astronomy.moon.phase,astronomy.moon.phaseName,astronomy.moon.illumination,flags.units,provider,"+
+		"ensemble.method,ensemble.spread.temperature.min,ensemble.spread.temperature.max,ensemble.spread.dewPoint.min,ensemble.spread.dewPoint.max,"+
+		"ensemble.spread.pressure.min,ensemble.spread.pressure.max,ensemble.spread.humidity.min,ensemble.spread.humidity.max,"+
+		"ensemble.spread.windSpeed.min,ensemble.spread.windSpeed.max,ensemble.spread.windGust.min,ensemble.spread.windGust.max,ensemble.providers,hourly,"+
+		"airQuality.time,airQuality.pollutants.pm2_5,airQuality.pollutants.pm10,airQuality.pollutants.o3,airQuality.pollutants.no2,airQuality.pollutants.so2,airQuality.pollutants.co,"+
+		"airQuality.pollen.alder,airQuality.pollen.birch,airQuality.pollen.grass,airQuality.pollen.mugwort,airQuality.pollen.olive,airQuality.pollen.ragweed,"+
+		"airQuality.aqi.epa.value,airQuality.aqi.epa.category,airQuality.aqi.epa.dominant,airQuality.aqi.eu.value,airQuality.aqi.eu.category,airQuality.aqi.eu.dominant,airQuality.provider\n"+
+		"20.34,-12.44,Africa/Nouakchott,78.02,Overcast,32.37,1014.1,0.19,3.4,0,1583064000,,,,,,,,76.3,78,78,78,comfortable,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,,\n", response.Body.String())
+}
+
+func TestGetWeatherProtobuf(t *testing.T) {
+	mockOvercast()
+	response := getWeatherAs("/weather", "application/x-protobuf")
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	assert.EqualValues(t, "application/x-protobuf", response.Header().Get("Content-Type"))
+
+	var weather weather_domain.WeatherMessage
+	assert.Nil(t, proto.Unmarshal(response.Body.Bytes(), &weather))
+	assert.EqualValues(t, 20.34, weather.Latitude)
+	assert.EqualValues(t, "Africa/Nouakchott", weather.TimeZone)
+	assert.EqualValues(t, "Overcast", weather.Currently.Summary)
+	assert.EqualValues(t, 32.37, weather.Currently.DewPoint)
+	assert.EqualValues(t, 3.4, weather.Currently.WindSpeed)
+	assert.EqualValues(t, weather_domain.ComfortComfortable, weather.Comfort.Category)
+}
+
+func TestGetWeatherAcceptWildcard(t *testing.T) {
+	mockOvercast()
+	response := getWeatherAs("/weather", "text/html, */*;q=0.8")
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Header().Get("Content-Type"), "application/json")
+}
+
+func TestGetWeatherNotAcceptable(t *testing.T) {
+	mockOvercast()
+	response := getWeatherAs("/weather", "application/yaml")
+	assert.EqualValues(t, http.StatusNotAcceptable, response.Code)
+	apiErr, err := weather_domain.NewApiErrFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, http.StatusNotAcceptable, apiErr.Status())
+
+	response = getWeatherAs("/weather?format=yaml", "")
+	assert.EqualValues(t, http.StatusNotAcceptable, response.Code)
+}
+
+func TestGetWeatherLocalizedError(t *testing.T) {
+	var received weather_domain.WeatherRequest
+	getWeatheFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, weather_domain.WeatherErrorInterface) {
+		received = request
+		return nil, weather_domain.NewForbiddenError("permission denied")
+	}
+	services.WeatherService = &weatherServiceMock{}
+
+	response := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(response)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/weather", nil)
+	c.Request.Header.Set("Accept-Language", "fr-FR, en;q=0.5")
+	GetWeather(c)
+	assert.EqualValues(t, "fr", received.Lang)
+	assert.EqualValues(t, http.StatusForbidden, response.Code)
+	assert.EqualValues(t, "fr", response.Header().Get("Content-Language"))
+	apiErr, err := weather_domain.NewApiErrFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, "Permission refusée", apiErr.Message())
+
+	response = getWeatherAs("/weather?lang=xx", "")
+	apiErr, err = weather_domain.NewApiErrFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, "xx", received.Lang)
+	assert.EqualValues(t, "permission denied", apiErr.Message())
+}
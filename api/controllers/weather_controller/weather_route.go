@@ -0,0 +1,100 @@
+package weather_controller
+
+import (
+	"encoding/json"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/geo"
+	"interface-testing/api/i18n"
+	"interface-testing/api/middleware"
+	"interface-testing/api/render"
+	"interface-testing/api/services"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+//maxRouteBody bounds the route a client can send, a GeoJSON line of some ten thousand points
+const maxRouteBody = 1 << 20
+
+//routeBody is the body of a route request: the route as an encoded "polyline" or a GeoJSON LineString "geometry",
+//the "departure" time, the "speed" in km/h and optionally the "interval" between samples in km
+type routeBody struct {
+	Polyline string `json:"polyline"`
+	//Precision is the number of decimals the polyline was encoded with, 5 unless set
+	Precision int             `json:"precision"`
+	Geometry  json.RawMessage `json:"geometry"`
+	Departure time.Time       `json:"departure"`
+	Speed     float64         `json:"speed"`
+	Interval  float64         `json:"interval"`
+}
+
+//GetWeatherRoute forecasts the weather along a route at the time the vehicle is expected at each point
+func GetWeatherRoute(c *gin.Context) {
+	request, apiError := routeRequest(c)
+	if apiError != nil {
+		render.Render(c, apiError.Status(), apiError)
+		return
+	}
+	route, apiError := services.WeatherRouteService.GetRoute(c.Request.Context(), request)
+	if apiError != nil {
+		render.Render(c, apiError.Status(), apiError)
+		return
+	}
+	lang := render.Language(c)
+	for _, point := range route.Points {
+		if point.Error != nil {
+			point.Error.ErrorMessage = i18n.Translate(lang, point.Error.ErrorMessage)
+		}
+	}
+	if client := middleware.Client(c); client != nil {
+		for _, point := range route.Points {
+			if point.Cache != nil {
+				services.UsageService.Served(client.Id, point.Cache)
+			}
+		}
+	}
+	render.Render(c, http.StatusOK, route)
+}
+
+func routeRequest(c *gin.Context) (weather_domain.RouteRequest, weather_domain.WeatherErrorInterface) {
+	var body routeBody
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxRouteBody)
+	if err := c.ShouldBindJSON(&body); err != nil {
+		return weather_domain.RouteRequest{}, weather_domain.NewBadRequestError("invalid json body")
+	}
+	var path []geo.Point
+	var err error
+	switch {
+	case body.Polyline != "" && body.Geometry != nil:
+		return weather_domain.RouteRequest{}, weather_domain.NewBadRequestError("send either a polyline or a geometry, not both")
+	case body.Polyline != "":
+		precision := body.Precision
+		if precision == 0 {
+			precision = 5
+		}
+		if precision < 1 || precision > 9 {
+			return weather_domain.RouteRequest{}, weather_domain.NewBadRequestError("precision must be between 1 and 9")
+		}
+		path, err = geo.DecodePolyline(body.Polyline, precision)
+	case body.Geometry != nil:
+		path, err = geo.DecodeLineString(body.Geometry)
+	default:
+		return weather_domain.RouteRequest{}, weather_domain.NewBadRequestError("a polyline or a geometry is required")
+	}
+	if err != nil {
+		return weather_domain.RouteRequest{}, weather_domain.NewBadRequestError(err.Error())
+	}
+	return weather_domain.RouteRequest{
+		Request: weather_domain.WeatherRequest{
+			ApiKey: c.Param("apiKey"),
+			Units:  c.Query("units"),
+			Lang:   render.Language(c),
+		},
+		Path:      path,
+		Departure: body.Departure,
+		Speed:     body.Speed,
+		Interval:  body.Interval,
+		Client:    middleware.Client(c),
+	}, nil
+}
@@ -0,0 +1,108 @@
+package weather_controller
+
+import (
+	"encoding/json"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func postWeatherRoute(url string, body string) *httptest.ResponseRecorder {
+	response := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(response)
+	c.Request, _ = http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "apiKey", Value: "right_api_key"}}
+	GetWeatherRoute(c)
+	return response
+}
+
+//mockHourly forecasts two hours from 2020-03-01T12:00:00Z, raining in the second one
+func mockHourly() {
+	getWeatheFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, weather_domain.WeatherErrorInterface) {
+		return &weather_domain.Weather{TimeZone: "America/Los_Angeles", Hourly: []weather_domain.HourlyInfo{
+			{Time: 1583064000, Summary: "Clear", Temperature: 12},
+			{Time: 1583067600, Summary: "Rain", Temperature: 9, PrecipProbability: 0.8},
+		}}, nil
+	}
+	services.WeatherService = &weatherServiceMock{}
+}
+
+func TestGetWeatherRoutePolyline(t *testing.T) {
+	mockHourly()
+	//The documented polyline, some 790 km long, driven at 250 km/h
+	response := postWeatherRoute("/weather/right_api_key/route",
+		`{"polyline": "_p~iF~ps|U_ulLnnqC_mqNvxq`+"`"+`@", "departure": "2020-03-01T12:00:00Z", "speed": 250, "interval": 250}`)
+	assert.EqualValues(t, http.StatusOK, response.Code)
+
+	var route weather_domain.Route
+	assert.Nil(t, json.Unmarshal(response.Body.Bytes(), &route))
+	assert.Len(t, route.Points, 5)
+	assert.EqualValues(t, 38.5, route.Points[0].Latitude)
+	assert.EqualValues(t, -120.2, route.Points[0].Longitude)
+	assert.EqualValues(t, "Clear", route.Points[0].Forecast.Summary)
+	assert.EqualValues(t, "2020-03-01T13:00:00Z", route.Points[1].Eta.Format("2006-01-02T15:04:05Z07:00"))
+	assert.EqualValues(t, "Rain", route.Points[1].Forecast.Summary)
+	assert.EqualValues(t, "America/Los_Angeles", route.Points[1].TimeZone)
+	//The vehicle gets to the end after the forecast
+	assert.Nil(t, route.Points[4].Forecast)
+	assert.EqualValues(t, http.StatusUnprocessableEntity, route.Points[4].Error.Code)
+}
+
+func TestGetWeatherRouteProtobuf(t *testing.T) {
+	mockHourly()
+	response := postWeatherRoute("/weather/right_api_key/route?format=protobuf",
+		`{"polyline": "_p~iF~ps|U_ulLnnqC_mqNvxq`+"`"+`@", "departure": "2020-03-01T12:00:00Z", "speed": 250, "interval": 250}`)
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	assert.EqualValues(t, "application/x-protobuf", response.Header().Get("Content-Type"))
+
+	var route weather_domain.RouteMessage
+	assert.Nil(t, proto.Unmarshal(response.Body.Bytes(), &route))
+	assert.EqualValues(t, 1583064000, route.Departure)
+	assert.Len(t, route.Points, 5)
+	assert.EqualValues(t, 1583067600, route.Points[1].Eta)
+	assert.EqualValues(t, "Rain", route.Points[1].Forecast.Summary)
+	assert.EqualValues(t, "America/Los_Angeles", route.Points[1].TimeZone)
+	assert.Nil(t, route.Points[4].Forecast)
+	assert.EqualValues(t, http.StatusUnprocessableEntity, route.Points[4].Error.Code)
+}
+
+func TestGetWeatherRouteGeoJson(t *testing.T) {
+	mockHourly()
+	response := postWeatherRoute("/weather/right_api_key/route?format=csv",
+		`{"geometry": {"type": "LineString", "coordinates": [[-120.2, 38.5], [-120.2, 38.6]]}, "departure": "2020-03-01T12:30:00Z", "speed": 50}`)
+	assert.EqualValues(t, http.StatusOK, response.Code)
+
+	lines := strings.Split(strings.TrimSpace(response.Body.String()), "\n")
+	//One row per point under one header, the ETA in a single column
+	assert.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "latitude,longitude,distance,eta,timezone,forecast.time,"))
+	assert.True(t, strings.HasPrefix(lines[1], "38.5,-120.2,0,2020-03-01T12:30:00Z,America/Los_Angeles,1583064000,12,Clear,"))
+}
+
+func TestGetWeatherRouteInvalid(t *testing.T) {
+	mockHourly()
+	for body, message := range map[string]string{
+		`not json`:      "invalid json body",
+		`{"speed": 80}`: "a polyline or a geometry is required",
+		`{"polyline": "_p~iF~ps|U", "geometry": {}}`:      "send either a polyline or a geometry, not both",
+		`{"polyline": "_p~iF~ps|U", "precision": 12}`:     "precision must be between 1 and 9",
+		`{"polyline": "_p~iF~ps", "speed": 80}`:           "polyline ends in the middle of a point",
+		`{"geometry": {"type": "Point"}, "speed": 80}`:    `expected a GeoJSON LineString, got "Point"`,
+		`{"polyline": "_p~iF~ps|U", "departure": "soon"}`: "invalid json body",
+		`{"polyline": "_p~iF~ps|U"}`:                      "speed must be a positive number of km/h",
+	} {
+		response := postWeatherRoute("/weather/right_api_key/route", body)
+		assert.EqualValues(t, http.StatusBadRequest, response.Code, body)
+		apiErr, err := weather_domain.NewApiErrFromBytes(response.Body.Bytes())
+		assert.Nil(t, err)
+		assert.EqualValues(t, message, apiErr.Message(), body)
+	}
+}
@@ -0,0 +1,168 @@
+package weather_controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/i18n"
+	"interface-testing/api/render"
+	"interface-testing/api/services"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	subscribeAction   = "subscribe"
+	unsubscribeAction = "unsubscribe"
+)
+
+var (
+	upgrader = websocket.Upgrader{
+		CheckOrigin: func(r *http.Request) bool { return true },
+	}
+)
+
+//StreamWeather pushes weather for every "location=lat,long" query value as Server-Sent Events
+func StreamWeather(c *gin.Context) {
+	requests, apiError := streamRequests(c)
+	if apiError == nil && len(requests) == 0 {
+		apiError = weather_domain.NewBadRequestError("at least one location is required")
+	}
+	if apiError != nil {
+		render.Render(c, apiError.Status(), apiError)
+		return
+	}
+	updates := make(chan weather_domain.WeatherUpdate, 16)
+	for _, request := range requests {
+		unsubscribe := services.WeatherStreamService.Subscribe(request, updates)
+		defer unsubscribe()
+	}
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case update := <-updates:
+			if update.Err != nil {
+				c.SSEvent("error", weather_domain.NewWeatherError(update.Err.Status(), i18n.Translate(update.Request.Lang, update.Err.Message())))
+			} else {
+				c.SSEvent("weather", update.Weather)
+			}
+			return true
+		}
+	})
+}
+
+//StreamWeatherSocket is the websocket equivalent of StreamWeather. Besides the locations in the query,
+//clients can send {"action": "subscribe"|"unsubscribe", "latitude": .., "longitude": ..} messages
+func StreamWeatherSocket(c *gin.Context) {
+	requests, apiError := streamRequests(c)
+	if apiError != nil {
+		render.Render(c, apiError.Status(), apiError)
+		return
+	}
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	updates := make(chan weather_domain.WeatherUpdate, 16)
+	subscriptions := make(map[weather_domain.WeatherRequest]func())
+	defer func() {
+		for _, unsubscribe := range subscriptions {
+			unsubscribe()
+		}
+	}()
+	subscribe := func(request weather_domain.WeatherRequest) {
+		if _, ok := subscriptions[request]; !ok {
+			subscriptions[request] = services.WeatherStreamService.Subscribe(request, updates)
+		}
+	}
+	for _, request := range requests {
+		subscribe(request)
+	}
+
+	//gorilla allows one concurrent reader and one writer, so the reader only hands messages over to this goroutine
+	messages := make(chan weather_domain.WeatherSubscription)
+	closed := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		defer close(closed)
+		for {
+			var message weather_domain.WeatherSubscription
+			if err := conn.ReadJSON(&message); err != nil {
+				switch err.(type) {
+				case *json.SyntaxError, *json.UnmarshalTypeError:
+					message = weather_domain.WeatherSubscription{}
+				default:
+					return
+				}
+			}
+			select {
+			case messages <- message:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case message := <-messages:
+			request := weather_domain.WeatherRequest{ApiKey: c.Query("apiKey"), Latitude: message.Latitude, Longitude: message.Longitude, Units: c.Query("units"), Lang: render.Language(c)}
+			switch message.Action {
+			case subscribeAction:
+				subscribe(request)
+			case unsubscribeAction:
+				if unsubscribe, ok := subscriptions[request]; ok {
+					unsubscribe()
+					delete(subscriptions, request)
+				}
+			default:
+				if err := conn.WriteJSON(weather_domain.NewBadRequestError(i18n.Translate(render.Language(c), "invalid subscription message"))); err != nil {
+					return
+				}
+			}
+		case update := <-updates:
+			if _, ok := subscriptions[update.Request]; !ok {
+				continue
+			}
+			var payload interface{} = update.Weather
+			if update.Err != nil {
+				payload = weather_domain.NewWeatherError(update.Err.Status(), i18n.Translate(update.Request.Lang, update.Err.Message()))
+			}
+			if err := conn.WriteJSON(payload); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func streamRequests(c *gin.Context) ([]weather_domain.WeatherRequest, weather_domain.WeatherErrorInterface) {
+	apiKey := c.Query("apiKey")
+	if apiKey == "" {
+		return nil, weather_domain.NewBadRequestError("apiKey is required")
+	}
+	var requests []weather_domain.WeatherRequest
+	for _, location := range c.QueryArray("location") {
+		coordinates := strings.Split(location, ",")
+		if len(coordinates) != 2 {
+			return nil, weather_domain.NewBadRequestError(fmt.Sprintf("invalid location %q", location))
+		}
+		lat, latErr := strconv.ParseFloat(strings.TrimSpace(coordinates[0]), 64)
+		long, longErr := strconv.ParseFloat(strings.TrimSpace(coordinates[1]), 64)
+		if latErr != nil || longErr != nil {
+			return nil, weather_domain.NewBadRequestError(fmt.Sprintf("invalid location %q", location))
+		}
+		requests = append(requests, weather_domain.WeatherRequest{ApiKey: apiKey, Latitude: lat, Longitude: long, Units: c.Query("units"), Lang: render.Language(c)})
+	}
+	return requests, nil
+}
@@ -0,0 +1,128 @@
+package weather_controller
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	subscribeFunc func(request weather_domain.WeatherRequest, updates chan<- weather_domain.WeatherUpdate) func()
+)
+
+type weatherStreamServiceMock struct{}
+
+//We are mocking the stream service method "Subscribe"
+func (w *weatherStreamServiceMock) Subscribe(request weather_domain.WeatherRequest, updates chan<- weather_domain.WeatherUpdate) func() {
+	return subscribeFunc(request, updates)
+}
+
+//Every subscription immediately receives the weather of its own location
+func subscribeWithWeather(subscribed chan<- weather_domain.WeatherRequest) {
+	subscribeFunc = func(request weather_domain.WeatherRequest, updates chan<- weather_domain.WeatherUpdate) func() {
+		subscribed <- request
+		updates <- weather_domain.WeatherUpdate{
+			Request: request,
+			Weather: &weather_domain.Weather{Latitude: request.Latitude, Longitude: request.Longitude, Currently: weather_domain.CurrentlyInfo{Summary: "Clear"}},
+		}
+		return func() {}
+	}
+	services.WeatherStreamService = &weatherStreamServiceMock{}
+}
+
+func streamServer() *httptest.Server {
+	router := gin.New()
+	router.GET("/weather/stream", StreamWeather)
+	router.GET("/weather/ws", StreamWeatherSocket)
+	return httptest.NewServer(router)
+}
+
+func TestStreamWeatherInvalidLocation(t *testing.T) {
+	response := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(response)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/weather/stream?apiKey=right_api_key&location=12.3", nil)
+	StreamWeather(c)
+	assert.EqualValues(t, http.StatusBadRequest, response.Code)
+	apiErr, err := weather_domain.NewApiErrFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, `invalid location "12.3"`, apiErr.Message())
+}
+
+func TestStreamWeatherNoApiKey(t *testing.T) {
+	response := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(response)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/weather/stream?location=12.3,42.1", nil)
+	StreamWeather(c)
+	assert.EqualValues(t, http.StatusBadRequest, response.Code)
+	apiErr, err := weather_domain.NewApiErrFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, "apiKey is required", apiErr.Message())
+}
+
+func TestStreamWeatherServerSentEvents(t *testing.T) {
+	subscribed := make(chan weather_domain.WeatherRequest, 2)
+	subscribeWithWeather(subscribed)
+	server := streamServer()
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	request, _ := http.NewRequest(http.MethodGet, server.URL+"/weather/stream?apiKey=right_api_key&location=12.3,42.1&location=20.34,-12.44", nil)
+	response, err := http.DefaultClient.Do(request.WithContext(ctx))
+	assert.Nil(t, err)
+	defer response.Body.Close()
+	assert.EqualValues(t, http.StatusOK, response.StatusCode)
+	assert.EqualValues(t, "text/event-stream", response.Header.Get("Content-Type"))
+
+	var events []weather_domain.Weather
+	scanner := bufio.NewScanner(response.Body)
+	for len(events) < 2 && scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "data:") {
+			var weather weather_domain.Weather
+			assert.Nil(t, json.Unmarshal([]byte(strings.TrimPrefix(scanner.Text(), "data:")), &weather))
+			events = append(events, weather)
+		}
+	}
+	assert.Len(t, events, 2)
+	assert.EqualValues(t, "Clear", events[0].Currently.Summary)
+	assert.ElementsMatch(t, []float64{12.3, 20.34}, []float64{events[0].Latitude, events[1].Latitude})
+	assert.EqualValues(t, "right_api_key", (<-subscribed).ApiKey)
+}
+
+func TestStreamWeatherSocket(t *testing.T) {
+	subscribed := make(chan weather_domain.WeatherRequest, 2)
+	subscribeWithWeather(subscribed)
+	server := streamServer()
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/weather/ws?apiKey=right_api_key&location=12.3,42.1", nil)
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	var weather weather_domain.Weather
+	assert.Nil(t, conn.ReadJSON(&weather))
+	assert.EqualValues(t, 12.3, weather.Latitude)
+	assert.EqualValues(t, "Clear", weather.Currently.Summary)
+
+	assert.Nil(t, conn.WriteJSON(weather_domain.WeatherSubscription{Action: "subscribe", Latitude: 20.34, Longitude: -12.44}))
+	assert.Nil(t, conn.ReadJSON(&weather))
+	assert.EqualValues(t, 20.34, weather.Latitude)
+	assert.EqualValues(t, -12.44, weather.Longitude)
+
+	assert.Nil(t, conn.WriteJSON(weather_domain.WeatherSubscription{Action: "unknown"}))
+	var apiErr weather_domain.WeatherError
+	assert.Nil(t, conn.ReadJSON(&apiErr))
+	assert.EqualValues(t, http.StatusBadRequest, apiErr.Code)
+	assert.EqualValues(t, "invalid subscription message", apiErr.ErrorMessage)
+}
@@ -0,0 +1,48 @@
+package client_domain
+
+import "time"
+
+//Tiers a client can be issued, they decide its quotas
+const (
+	TierFree       = "free"
+	TierStandard   = "standard"
+	TierEnterprise = "enterprise"
+)
+
+var (
+	Tiers = map[string]bool{TierFree: true, TierStandard: true, TierEnterprise: true}
+)
+
+//Client is a team consuming the service. Only the hash of its key is kept, the key itself is shown once when issued
+type Client struct {
+	Id        string     `json:"id"`
+	Owner     string     `json:"owner"`
+	Tier      string     `json:"tier"`
+	KeyPrefix string     `json:"keyPrefix"`
+	KeyHash   string     `json:"-"`
+	CreatedAt time.Time  `json:"createdAt"`
+	RotatedAt *time.Time `json:"rotatedAt,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+}
+
+//ClientRequest is the body of an admin request issuing a key
+type ClientRequest struct {
+	Owner     string     `json:"owner"`
+	Tier      string     `json:"tier"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+//IssuedKey is the answer to creating or rotating a key, the only time Key is known
+type IssuedKey struct {
+	Client
+	Key string `json:"key"`
+}
+
+func (c *Client) Revoked() bool {
+	return c.RevokedAt != nil
+}
+
+func (c *Client) Expired(now time.Time) bool {
+	return c.ExpiresAt != nil && !now.Before(*c.ExpiresAt)
+}
@@ -0,0 +1,39 @@
+package client_domain
+
+//DayFormat is how usage days are written, in UTC, and MonthFormat the month they belong to
+const (
+	DayFormat   = "2006-01-02"
+	MonthFormat = "2006-01"
+)
+
+var (
+	//MonthlyQuotas is how many weather requests a client of each tier may make per calendar month, 0 is unlimited
+	MonthlyQuotas = map[string]int64{
+		TierFree:       1000,
+		TierStandard:   100000,
+		TierEnterprise: 0,
+	}
+)
+
+//Usage counts the weather requests of a client. UpstreamCalls and CacheHits split the requests that
+//were served: by asking the provider, or from the cache
+type Usage struct {
+	Requests      int64 `json:"requests"`
+	UpstreamCalls int64 `json:"upstreamCalls"`
+	CacheHits     int64 `json:"cacheHits"`
+}
+
+//UsageRecord is the usage of one client on one day
+type UsageRecord struct {
+	ClientId string `json:"clientId"`
+	Owner    string `json:"owner,omitempty"`
+	Tier     string `json:"tier,omitempty"`
+	Day      string `json:"day"`
+	Usage
+}
+
+func (u *Usage) Add(other Usage) {
+	u.Requests += other.Requests
+	u.UpstreamCalls += other.UpstreamCalls
+	u.CacheHits += other.CacheHits
+}
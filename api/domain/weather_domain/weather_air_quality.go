@@ -0,0 +1,50 @@
+package weather_domain
+
+//AirQualityInfo is the air at a location. Concentrations are in µg/m³ and pollen counts in grains/m³,
+//a value the provider does not have for the location is left out
+type AirQualityInfo struct {
+	//Time is when the values were observed, in unix seconds
+	Time       int64          `json:"time" xml:"time"`
+	Pollutants PollutantsInfo `json:"pollutants" xml:"pollutants"`
+	//Pollen is only measured in some regions, such as Europe during the pollen season
+	Pollen   *PollenInfo `json:"pollen,omitempty" xml:"pollen,omitempty"`
+	Aqi      AqiInfo     `json:"aqi" xml:"aqi"`
+	Provider string      `json:"provider,omitempty" xml:"provider,omitempty"`
+}
+
+type PollutantsInfo struct {
+	PM25 *float64 `json:"pm2_5,omitempty" xml:"pm2_5,omitempty"`
+	PM10 *float64 `json:"pm10,omitempty" xml:"pm10,omitempty"`
+	O3   *float64 `json:"o3,omitempty" xml:"o3,omitempty"`
+	NO2  *float64 `json:"no2,omitempty" xml:"no2,omitempty"`
+	SO2  *float64 `json:"so2,omitempty" xml:"so2,omitempty"`
+	CO   *float64 `json:"co,omitempty" xml:"co,omitempty"`
+}
+
+type PollenInfo struct {
+	Alder   *float64 `json:"alder,omitempty" xml:"alder,omitempty"`
+	Birch   *float64 `json:"birch,omitempty" xml:"birch,omitempty"`
+	Grass   *float64 `json:"grass,omitempty" xml:"grass,omitempty"`
+	Mugwort *float64 `json:"mugwort,omitempty" xml:"mugwort,omitempty"`
+	Olive   *float64 `json:"olive,omitempty" xml:"olive,omitempty"`
+	Ragweed *float64 `json:"ragweed,omitempty" xml:"ragweed,omitempty"`
+}
+
+//AqiInfo holds the air quality index on the US EPA scale and on the European one, nil when no pollutant they use
+//was measured
+type AqiInfo struct {
+	Epa *IndexInfo `json:"epa,omitempty" xml:"epa,omitempty"`
+	Eu  *IndexInfo `json:"eu,omitempty" xml:"eu,omitempty"`
+}
+
+//IndexInfo is the index of the worst pollutant, which is named by its json name in Dominant
+type IndexInfo struct {
+	Value    int    `json:"value" xml:"value"`
+	Category string `json:"category" xml:"category"`
+	Dominant string `json:"dominant" xml:"dominant"`
+}
+
+//Empty tells whether no pollen count is known, in which case there is no point sending the block
+func (p PollenInfo) Empty() bool {
+	return p.Alder == nil && p.Birch == nil && p.Grass == nil && p.Mugwort == nil && p.Olive == nil && p.Ragweed == nil
+}
@@ -0,0 +1,133 @@
+package weather_domain
+
+import "math"
+
+//The US EPA categories, by the highest index they go to
+const (
+	AqiGood                        = "Good"
+	AqiModerate                    = "Moderate"
+	AqiUnhealthyForSensitiveGroups = "Unhealthy for Sensitive Groups"
+	AqiUnhealthy                   = "Unhealthy"
+	AqiVeryUnhealthy               = "Very Unhealthy"
+	AqiHazardous                   = "Hazardous"
+)
+
+//The European categories, index 1 to 6
+const (
+	EaqiGood          = "Good"
+	EaqiFair          = "Fair"
+	EaqiModerate      = "Moderate"
+	EaqiPoor          = "Poor"
+	EaqiVeryPoor      = "Very poor"
+	EaqiExtremelyPoor = "Extremely poor"
+)
+
+//molarVolume is the volume of a mole of gas in liters at 25°C and 1 atm, which turns µg/m³ into ppb
+const molarVolume = 24.45
+
+//epaBreakpoint maps the concentrations from low to high to the indexes from indexLow to indexHigh
+type epaBreakpoint struct {
+	low, high           float64
+	indexLow, indexHigh int
+}
+
+var (
+	epaCategories = []struct {
+		high     int
+		category string
+	}{
+		{50, AqiGood}, {100, AqiModerate}, {150, AqiUnhealthyForSensitiveGroups},
+		{200, AqiUnhealthy}, {300, AqiVeryUnhealthy}, {500, AqiHazardous},
+	}
+	euCategories = []string{EaqiGood, EaqiFair, EaqiModerate, EaqiPoor, EaqiVeryPoor, EaqiExtremelyPoor}
+
+	//aqiPollutants are the breakpoints of each pollutant. The EPA tables are in µg/m³ for particles, ppb for
+	//ozone, NO2 and SO2 and ppm for CO, as of the 2024 revision, epa converts and truncates a concentration to them.
+	//The EU limits are the upper bounds of the European levels, in µg/m³
+	aqiPollutants = []struct {
+		name     string
+		value    func(p PollutantsInfo) *float64
+		epa      func(concentration float64) float64
+		epaTable []epaBreakpoint
+		euLimits []float64
+	}{
+		{"pm2_5", func(p PollutantsInfo) *float64 { return p.PM25 },
+			func(c float64) float64 { return math.Floor(c*10) / 10 },
+			[]epaBreakpoint{{0, 9, 0, 50}, {9.1, 35.4, 51, 100}, {35.5, 55.4, 101, 150}, {55.5, 125.4, 151, 200}, {125.5, 225.4, 201, 300}, {225.5, 325.4, 301, 500}},
+			[]float64{10, 20, 25, 50, 75}},
+		{"pm10", func(p PollutantsInfo) *float64 { return p.PM10 },
+			math.Floor,
+			[]epaBreakpoint{{0, 54, 0, 50}, {55, 154, 51, 100}, {155, 254, 101, 150}, {255, 354, 151, 200}, {355, 424, 201, 300}, {425, 604, 301, 500}},
+			[]float64{20, 40, 50, 100, 150}},
+		//The EPA rates ozone past 200 ppb on hourly values only, which top at Very Unhealthy here
+		{"o3", func(p PollutantsInfo) *float64 { return p.O3 },
+			func(c float64) float64 { return math.Floor(c * molarVolume / 48.00) },
+			[]epaBreakpoint{{0, 54, 0, 50}, {55, 70, 51, 100}, {71, 85, 101, 150}, {86, 105, 151, 200}, {106, 200, 201, 300}},
+			[]float64{50, 100, 130, 240, 380}},
+		{"no2", func(p PollutantsInfo) *float64 { return p.NO2 },
+			func(c float64) float64 { return math.Floor(c * molarVolume / 46.01) },
+			[]epaBreakpoint{{0, 53, 0, 50}, {54, 100, 51, 100}, {101, 360, 101, 150}, {361, 649, 151, 200}, {650, 1249, 201, 300}, {1250, 2049, 301, 500}},
+			[]float64{40, 90, 120, 230, 340}},
+		{"so2", func(p PollutantsInfo) *float64 { return p.SO2 },
+			func(c float64) float64 { return math.Floor(c * molarVolume / 64.07) },
+			[]epaBreakpoint{{0, 35, 0, 50}, {36, 75, 51, 100}, {76, 185, 101, 150}, {186, 304, 151, 200}, {305, 604, 201, 300}, {605, 1004, 301, 500}},
+			[]float64{100, 200, 350, 500, 750}},
+		//CO is not part of the European index
+		{"co", func(p PollutantsInfo) *float64 { return p.CO },
+			func(c float64) float64 { return math.Floor(c*molarVolume/28.01/100) / 10 },
+			[]epaBreakpoint{{0, 4.4, 0, 50}, {4.5, 9.4, 51, 100}, {9.5, 12.4, 101, 150}, {12.5, 15.4, 151, 200}, {15.5, 30.4, 201, 300}, {30.5, 50.4, 301, 500}},
+			nil},
+	}
+)
+
+//NewAqiInfo rates the concentrations on both scales, the index being that of the worst pollutant. Both scales are
+//meant for concentrations averaged over hours, the current values give an indication of where they are heading
+func NewAqiInfo(pollutants PollutantsInfo) AqiInfo {
+	var aqi AqiInfo
+	for _, pollutant := range aqiPollutants {
+		value := pollutant.value(pollutants)
+		if value == nil || *value < 0 {
+			continue
+		}
+		if index := epaIndex(pollutant.epa(*value), pollutant.epaTable); aqi.Epa == nil || index > aqi.Epa.Value {
+			aqi.Epa = &IndexInfo{Value: index, Category: epaCategory(index), Dominant: pollutant.name}
+		}
+		if pollutant.euLimits == nil {
+			continue
+		}
+		if level := euLevel(*value, pollutant.euLimits); aqi.Eu == nil || level > aqi.Eu.Value {
+			aqi.Eu = &IndexInfo{Value: level, Category: euCategories[level-1], Dominant: pollutant.name}
+		}
+	}
+	return aqi
+}
+
+//epaIndex interpolates linearly within the breakpoint of the concentration, past the table it is the top index
+func epaIndex(concentration float64, table []epaBreakpoint) int {
+	for _, breakpoint := range table {
+		if concentration <= breakpoint.high {
+			ratio := float64(breakpoint.indexHigh-breakpoint.indexLow) / (breakpoint.high - breakpoint.low)
+			return int(math.Round(ratio*(concentration-breakpoint.low))) + breakpoint.indexLow
+		}
+	}
+	return table[len(table)-1].indexHigh
+}
+
+func epaCategory(index int) string {
+	for _, category := range epaCategories {
+		if index <= category.high {
+			return category.category
+		}
+	}
+	return AqiHazardous
+}
+
+//euLevel is 1 to 6, the first level whose limit the concentration does not exceed
+func euLevel(concentration float64, limits []float64) int {
+	for i, limit := range limits {
+		if concentration <= limit {
+			return i + 1
+		}
+	}
+	return len(limits) + 1
+}
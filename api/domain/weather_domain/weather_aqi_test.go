@@ -0,0 +1,63 @@
+package weather_domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func concentration(value float64) *float64 {
+	return &value
+}
+
+//Values worked out from the EPA technical assistance document for the AQI (2024) and the EEA index bands
+func TestNewAqiInfoSinglePollutant(t *testing.T) {
+	table := []struct {
+		pollutants  PollutantsInfo
+		epa         int
+		epaCategory string
+		eu          int
+		euCategory  string
+	}{
+		{PollutantsInfo{PM25: concentration(0)}, 0, AqiGood, 1, EaqiGood},
+		{PollutantsInfo{PM25: concentration(9.04)}, 50, AqiGood, 1, EaqiGood},
+		{PollutantsInfo{PM25: concentration(12)}, 56, AqiModerate, 2, EaqiFair},
+		{PollutantsInfo{PM25: concentration(35.4)}, 100, AqiModerate, 4, EaqiPoor},
+		{PollutantsInfo{PM25: concentration(55.5)}, 151, AqiUnhealthy, 5, EaqiVeryPoor},
+		{PollutantsInfo{PM25: concentration(900)}, 500, AqiHazardous, 6, EaqiExtremelyPoor},
+		{PollutantsInfo{PM10: concentration(154.9)}, 100, AqiModerate, 6, EaqiExtremelyPoor},
+		{PollutantsInfo{O3: concentration(100)}, 46, AqiGood, 2, EaqiFair},
+		{PollutantsInfo{O3: concentration(1000)}, 300, AqiVeryUnhealthy, 6, EaqiExtremelyPoor},
+		{PollutantsInfo{NO2: concentration(200)}, 102, AqiUnhealthyForSensitiveGroups, 4, EaqiPoor},
+		{PollutantsInfo{SO2: concentration(100)}, 54, AqiModerate, 1, EaqiGood},
+	}
+	for _, row := range table {
+		aqi := NewAqiInfo(row.pollutants)
+		assert.NotNil(t, aqi.Epa)
+		assert.NotNil(t, aqi.Eu)
+		assert.EqualValues(t, row.epa, aqi.Epa.Value, "%+v", row.pollutants)
+		assert.EqualValues(t, row.epaCategory, aqi.Epa.Category, "%+v", row.pollutants)
+		assert.EqualValues(t, row.eu, aqi.Eu.Value, "%+v", row.pollutants)
+		assert.EqualValues(t, row.euCategory, aqi.Eu.Category, "%+v", row.pollutants)
+	}
+}
+
+func TestNewAqiInfoCarbonMonoxide(t *testing.T) {
+	aqi := NewAqiInfo(PollutantsInfo{CO: concentration(10000)})
+	assert.NotNil(t, aqi.Epa)
+	assert.EqualValues(t, 93, aqi.Epa.Value)
+	assert.EqualValues(t, "co", aqi.Epa.Dominant)
+	assert.Nil(t, aqi.Eu)
+}
+
+func TestNewAqiInfoDominantPollutant(t *testing.T) {
+	aqi := NewAqiInfo(PollutantsInfo{PM25: concentration(12), PM10: concentration(30), NO2: concentration(200), CO: concentration(10000)})
+	assert.EqualValues(t, IndexInfo{Value: 102, Category: AqiUnhealthyForSensitiveGroups, Dominant: "no2"}, *aqi.Epa)
+	assert.EqualValues(t, IndexInfo{Value: 4, Category: EaqiPoor, Dominant: "no2"}, *aqi.Eu)
+}
+
+func TestNewAqiInfoNothingMeasured(t *testing.T) {
+	aqi := NewAqiInfo(PollutantsInfo{PM25: concentration(-1)})
+	assert.Nil(t, aqi.Epa)
+	assert.Nil(t, aqi.Eu)
+}
@@ -0,0 +1,212 @@
+package weather_domain
+
+import (
+	"math"
+	"time"
+)
+
+//The phases of the moon, each named for the eighth of the lunar cycle around it
+const (
+	MoonNew            = "New Moon"
+	MoonWaxingCrescent = "Waxing Crescent"
+	MoonFirstQuarter   = "First Quarter"
+	MoonWaxingGibbous  = "Waxing Gibbous"
+	MoonFull           = "Full Moon"
+	MoonWaningGibbous  = "Waning Gibbous"
+	MoonLastQuarter    = "Last Quarter"
+	MoonWaningCrescent = "Waning Crescent"
+)
+
+//The zenith angles of the sun in degrees at each event. Sunrise and sunset allow for refraction and the half
+//diameter of the sun, the twilights begin and end with the center of the sun 6, 12 and 18 degrees below the horizon
+const (
+	sunriseZenith      = 90.833
+	civilZenith        = 96
+	nauticalZenith     = 102
+	astronomicalZenith = 108
+)
+
+const (
+	secondsPerDay = 86400
+	//unixJulianDay is the julian day of the unix epoch and j2000 that of 2000-01-01T12:00:00Z
+	unixJulianDay = 2440587.5
+	j2000         = 2451545.0
+	degrees       = math.Pi / 180
+)
+
+var moonPhases = []string{MoonNew, MoonWaxingCrescent, MoonFirstQuarter, MoonWaxingGibbous, MoonFull,
+	MoonWaningGibbous, MoonLastQuarter, MoonWaningCrescent}
+
+//AstronomyInfo holds the sun of the local day and the moon at the time of the weather. Times are in unix seconds,
+//an event the sun does not reach that day, such as a sunset in the polar summer, is left out
+type AstronomyInfo struct {
+	Sunrise   int64 `json:"sunrise,omitempty" xml:"sunrise,omitempty"`
+	Sunset    int64 `json:"sunset,omitempty" xml:"sunset,omitempty"`
+	SolarNoon int64 `json:"solarNoon" xml:"solarNoon"`
+	//DayLength is the time from sunrise to sunset in seconds, a whole day when the sun does not set
+	DayLength            int64         `json:"dayLength" xml:"dayLength"`
+	CivilTwilight        *TwilightInfo `json:"civilTwilight,omitempty" xml:"civilTwilight,omitempty"`
+	NauticalTwilight     *TwilightInfo `json:"nauticalTwilight,omitempty" xml:"nauticalTwilight,omitempty"`
+	AstronomicalTwilight *TwilightInfo `json:"astronomicalTwilight,omitempty" xml:"astronomicalTwilight,omitempty"`
+	Moon                 MoonInfo      `json:"moon" xml:"moon"`
+}
+
+//TwilightInfo is when the morning twilight begins and the evening twilight ends
+type TwilightInfo struct {
+	Dawn int64 `json:"dawn" xml:"dawn"`
+	Dusk int64 `json:"dusk" xml:"dusk"`
+}
+
+type MoonInfo struct {
+	//Phase goes from 0 at the new moon through 0.25 at the first quarter, 0.5 at the full moon and 0.75 at the last quarter
+	Phase     float64 `json:"phase" xml:"phase"`
+	PhaseName string  `json:"phaseName" xml:"phaseName"`
+	//Illumination is the lit fraction of the disc, from 0 to 1
+	Illumination float64 `json:"illumination" xml:"illumination"`
+}
+
+//NewAstronomyInfo works out the sun and the moon at a location with the NOAA solar equations and a low precision
+//lunar theory, to within a minute or two of the almanacs away from the poles. The day of the sun is the calendar
+//day of at in its location, which should be the time zone of the location
+func NewAstronomyInfo(latitude float64, longitude float64, at time.Time) AstronomyInfo {
+	year, month, day := at.Date()
+	local := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	//The equations count from midnight UTC. Where the zone is far from the longitude, such as in Kiribati, the noon
+	//of that UTC day falls on another local day, the date is moved by the days in between
+	date := float64(local.Unix())
+	noon := solarNoon(date, longitude)
+	year, month, day = time.Unix(int64(noon), 0).In(at.Location()).Date()
+	if shift := local.Sub(time.Date(year, month, day, 0, 0, 0, 0, time.UTC)); shift != 0 {
+		date += shift.Seconds()
+		noon = solarNoon(date, longitude)
+	}
+	astronomy := AstronomyInfo{SolarNoon: int64(math.Round(noon)), Moon: NewMoonInfo(at)}
+	if daylight := sunEvents(date, noon, latitude, longitude, sunriseZenith); daylight != nil {
+		astronomy.Sunrise, astronomy.Sunset = daylight.Dawn, daylight.Dusk
+		astronomy.DayLength = daylight.Dusk - daylight.Dawn
+	} else if declination, _ := sunPosition(julianDay(noon)); cosHourAngle(latitude, declination, sunriseZenith) < -1 {
+		astronomy.DayLength = secondsPerDay
+	}
+	astronomy.CivilTwilight = sunEvents(date, noon, latitude, longitude, civilZenith)
+	astronomy.NauticalTwilight = sunEvents(date, noon, latitude, longitude, nauticalZenith)
+	astronomy.AstronomicalTwilight = sunEvents(date, noon, latitude, longitude, astronomicalZenith)
+	return astronomy
+}
+
+//NewMoonInfo is the phase of the moon at a time, from the angle between the moon and the sun seen from the earth
+func NewMoonInfo(at time.Time) MoonInfo {
+	days := julianDay(float64(at.Unix())) - j2000
+	sunAscension, sunDeclination := sunCoordinates(days)
+	moonAscension, moonDeclination, moonDistance := moonCoordinates(days)
+	//The distance to the sun in km
+	const sunDistance = 149598000
+	elongation := math.Acos(math.Sin(sunDeclination)*math.Sin(moonDeclination) +
+		math.Cos(sunDeclination)*math.Cos(moonDeclination)*math.Cos(sunAscension-moonAscension))
+	phaseAngle := math.Atan2(sunDistance*math.Sin(elongation), moonDistance-sunDistance*math.Cos(elongation))
+	//The position angle of the lit limb, negative while the moon is east of the sun, that is waxing
+	limb := math.Atan2(math.Cos(sunDeclination)*math.Sin(sunAscension-moonAscension),
+		math.Sin(sunDeclination)*math.Cos(moonDeclination)-math.Cos(sunDeclination)*math.Sin(moonDeclination)*math.Cos(sunAscension-moonAscension))
+	phase := 0.5 + 0.5*phaseAngle/math.Pi
+	if limb < 0 {
+		phase = 0.5 - 0.5*phaseAngle/math.Pi
+	}
+	phase = math.Mod(phase, 1)
+	return MoonInfo{
+		Phase:        phase,
+		PhaseName:    moonPhases[int(math.Floor(phase*8+0.5))%len(moonPhases)],
+		Illumination: (1 + math.Cos(phaseAngle)) / 2,
+	}
+}
+
+func julianDay(unix float64) float64 {
+	return unix/secondsPerDay + unixJulianDay
+}
+
+//solarNoon is when the sun crosses the meridian of longitude on the day starting at date, both in unix seconds
+func solarNoon(date float64, longitude float64) float64 {
+	noon := date + secondsPerDay/2 - longitude*240
+	for i := 0; i < 2; i++ {
+		_, equationOfTime := sunPosition(julianDay(noon))
+		noon = date + (720-4*longitude-equationOfTime)*60
+	}
+	return noon
+}
+
+//sunEvents is when the sun goes up and down through zenith on the day starting at date, nil when it stays on
+//one side all day. Each time is refined with the position of the sun at the time before
+func sunEvents(date float64, noon float64, latitude float64, longitude float64, zenith float64) *TwilightInfo {
+	var events [2]float64
+	for i, sign := range []float64{-1, 1} {
+		at := noon
+		for j := 0; j < 3; j++ {
+			declination, equationOfTime := sunPosition(julianDay(at))
+			cosine := cosHourAngle(latitude, declination, zenith)
+			if cosine < -1 || cosine > 1 || math.IsNaN(cosine) {
+				return nil
+			}
+			hourAngle := math.Acos(cosine) / degrees
+			at = date + (720-4*longitude-equationOfTime+sign*4*hourAngle)*60
+		}
+		events[i] = at
+	}
+	return &TwilightInfo{Dawn: int64(math.Round(events[0])), Dusk: int64(math.Round(events[1]))}
+}
+
+//cosHourAngle is the cosine of the hour angle at which the sun is at zenith degrees: below -1 the sun stays
+//further up all day, above 1 it never gets that high
+func cosHourAngle(latitude float64, declination float64, zenith float64) float64 {
+	latitude, declination = latitude*degrees, declination*degrees
+	return (math.Cos(zenith*degrees) - math.Sin(latitude)*math.Sin(declination)) / (math.Cos(latitude) * math.Cos(declination))
+}
+
+//sunPosition is the declination of the sun in degrees and the equation of time in minutes at a julian day,
+//from the NOAA solar calculator
+func sunPosition(julianDay float64) (declination float64, equationOfTime float64) {
+	t := (julianDay - j2000) / 36525
+	meanLongitude := math.Mod(280.46646+t*(36000.76983+t*0.0003032), 360) * degrees
+	meanAnomaly := (357.52911 + t*(35999.05029-0.0001537*t)) * degrees
+	eccentricity := 0.016708634 - t*(0.000042037+0.0000001267*t)
+	center := math.Sin(meanAnomaly)*(1.914602-t*(0.004817+0.000014*t)) +
+		math.Sin(2*meanAnomaly)*(0.019993-0.000101*t) + math.Sin(3*meanAnomaly)*0.000289
+	omega := (125.04 - 1934.136*t) * degrees
+	apparentLongitude := meanLongitude + (center-0.00569-0.00478*math.Sin(omega))*degrees
+	obliquity := (23 + (26+(21.448-t*(46.815+t*(0.00059-t*0.001813)))/60)/60 + 0.00256*math.Cos(omega)) * degrees
+
+	declination = math.Asin(math.Sin(obliquity)*math.Sin(apparentLongitude)) / degrees
+	y := math.Pow(math.Tan(obliquity/2), 2)
+	equationOfTime = 4 / degrees * (y*math.Sin(2*meanLongitude) - 2*eccentricity*math.Sin(meanAnomaly) +
+		4*eccentricity*y*math.Sin(meanAnomaly)*math.Cos(2*meanLongitude) -
+		0.5*y*y*math.Sin(4*meanLongitude) - 1.25*eccentricity*eccentricity*math.Sin(2*meanAnomaly))
+	return declination, equationOfTime
+}
+
+//sunCoordinates is the right ascension and declination of the sun in radians, days after j2000
+func sunCoordinates(days float64) (float64, float64) {
+	meanAnomaly := (357.5291 + 0.98560028*days) * degrees
+	center := (1.9148*math.Sin(meanAnomaly) + 0.02*math.Sin(2*meanAnomaly) + 0.0003*math.Sin(3*meanAnomaly)) * degrees
+	//102.9372 degrees is the perihelion of the earth
+	longitude := meanAnomaly + center + 102.9372*degrees + math.Pi
+	return equatorial(longitude, 0)
+}
+
+//moonCoordinates is the right ascension and declination of the moon in radians and its distance in km, days
+//after j2000, with the largest terms of the lunar theory only
+func moonCoordinates(days float64) (float64, float64, float64) {
+	meanLongitude := (218.316 + 13.176396*days) * degrees
+	meanAnomaly := (134.963 + 13.064993*days) * degrees
+	argumentOfLatitude := (93.272 + 13.229350*days) * degrees
+
+	longitude := meanLongitude + 6.289*degrees*math.Sin(meanAnomaly)
+	latitude := 5.128 * degrees * math.Sin(argumentOfLatitude)
+	distance := 385001 - 20905*math.Cos(meanAnomaly)
+	ascension, declination := equatorial(longitude, latitude)
+	return ascension, declination, distance
+}
+
+//equatorial turns ecliptic coordinates into the right ascension and declination, in radians
+func equatorial(longitude float64, latitude float64) (float64, float64) {
+	const obliquity = 23.4397 * degrees
+	ascension := math.Atan2(math.Sin(longitude)*math.Cos(obliquity)-math.Tan(latitude)*math.Sin(obliquity), math.Cos(longitude))
+	declination := math.Asin(math.Sin(latitude)*math.Cos(obliquity) + math.Cos(latitude)*math.Sin(obliquity)*math.Sin(longitude))
+	return ascension, declination
+}
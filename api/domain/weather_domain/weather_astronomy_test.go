@@ -0,0 +1,132 @@
+package weather_domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//almanacTolerance allows for the almanacs rounding to the minute and for the approximations of the equations
+const almanacTolerance = 2 * time.Minute
+
+func assertAlmanac(t *testing.T, expected string, actual int64, location *time.Location, name string) {
+	day := time.Unix(actual, 0).In(location)
+	clock, err := time.ParseInLocation("2006-01-02 15:04", day.Format("2006-01-02 ")+expected, location)
+	assert.Nil(t, err)
+	assert.InDelta(t, clock.Unix(), actual, almanacTolerance.Seconds(), "%s at %s, expected %s", name, day.Format("2006-01-02 15:04:05"), expected)
+}
+
+//Sun times of the USNO almanac (https://aa.usno.navy.mil/data/RS_OneDay), in local time
+func TestNewAstronomyInfoAlmanac(t *testing.T) {
+	london := time.FixedZone("GMT", 0)
+	bst := time.FixedZone("BST", 3600)
+	edt := time.FixedZone("EDT", -4*3600)
+	aedt := time.FixedZone("AEDT", 11*3600)
+	almanac := []struct {
+		name                string
+		latitude, longitude float64
+		at                  time.Time
+		location            *time.Location
+		sunrise, sunset     string
+		solarNoon           string
+	}{
+		{"London summer solstice", 51.5074, -0.1278, time.Date(2024, 6, 21, 12, 0, 0, 0, time.UTC), bst, "04:43", "21:21", "13:02"},
+		{"London winter solstice", 51.5074, -0.1278, time.Date(2024, 12, 21, 12, 0, 0, 0, time.UTC), london, "08:04", "15:53", "11:59"},
+		{"New York summer solstice", 40.7128, -74.0060, time.Date(2024, 6, 20, 16, 0, 0, 0, time.UTC), edt, "05:25", "20:31", "12:58"},
+		{"Sydney summer solstice", -33.8688, 151.2093, time.Date(2024, 12, 21, 2, 0, 0, 0, time.UTC), aedt, "05:41", "20:05", "12:53"},
+	}
+	for _, day := range almanac {
+		astronomy := NewAstronomyInfo(day.latitude, day.longitude, day.at)
+		assertAlmanac(t, day.sunrise, astronomy.Sunrise, day.location, day.name+" sunrise")
+		assertAlmanac(t, day.sunset, astronomy.Sunset, day.location, day.name+" sunset")
+		assertAlmanac(t, day.solarNoon, astronomy.SolarNoon, day.location, day.name+" solar noon")
+		assert.EqualValues(t, astronomy.Sunset-astronomy.Sunrise, astronomy.DayLength)
+	}
+}
+
+func TestNewAstronomyInfoTwilight(t *testing.T) {
+	bst := time.FixedZone("BST", 3600)
+	astronomy := NewAstronomyInfo(51.5074, -0.1278, time.Date(2024, 6, 21, 12, 0, 0, 0, time.UTC))
+	assert.NotNil(t, astronomy.CivilTwilight)
+	assertAlmanac(t, "03:56", astronomy.CivilTwilight.Dawn, bst, "civil dawn")
+	assertAlmanac(t, "22:08", astronomy.CivilTwilight.Dusk, bst, "civil dusk")
+	assert.NotNil(t, astronomy.NauticalTwilight)
+	//At midsummer the sun stays less than 18 degrees below the horizon of London, the night never gets fully dark
+	assert.Nil(t, astronomy.AstronomicalTwilight)
+
+	gmt := time.FixedZone("GMT", 0)
+	astronomy = NewAstronomyInfo(51.5074, -0.1278, time.Date(2024, 12, 21, 12, 0, 0, 0, time.UTC))
+	assertAlmanac(t, "07:24", astronomy.CivilTwilight.Dawn, gmt, "civil dawn")
+	assertAlmanac(t, "16:34", astronomy.CivilTwilight.Dusk, gmt, "civil dusk")
+	assert.True(t, astronomy.NauticalTwilight.Dawn < astronomy.CivilTwilight.Dawn)
+	assert.True(t, astronomy.AstronomicalTwilight.Dawn < astronomy.NauticalTwilight.Dawn)
+	assert.True(t, astronomy.AstronomicalTwilight.Dusk > astronomy.NauticalTwilight.Dusk)
+}
+
+func TestNewAstronomyInfoPolar(t *testing.T) {
+	//Tromsø has the midnight sun from late May to late July and the polar night from late November to mid January
+	midnightSun := NewAstronomyInfo(69.6492, 18.9553, time.Date(2024, 6, 21, 12, 0, 0, 0, time.UTC))
+	assert.EqualValues(t, 0, midnightSun.Sunrise)
+	assert.EqualValues(t, 0, midnightSun.Sunset)
+	assert.EqualValues(t, 24*60*60, midnightSun.DayLength)
+	assert.Nil(t, midnightSun.CivilTwilight)
+	assert.NotZero(t, midnightSun.SolarNoon)
+
+	polarNight := NewAstronomyInfo(69.6492, 18.9553, time.Date(2024, 12, 21, 12, 0, 0, 0, time.UTC))
+	assert.EqualValues(t, 0, polarNight.Sunrise)
+	assert.EqualValues(t, 0, polarNight.DayLength)
+	assert.NotNil(t, polarNight.CivilTwilight)
+	assert.NotNil(t, polarNight.AstronomicalTwilight)
+}
+
+//The local day is the calendar day of the location, not the UTC day of the time
+func TestNewAstronomyInfoLocalDay(t *testing.T) {
+	edt := time.FixedZone("EDT", -4*3600)
+	//22:00 in New York on the 20th is already the 21st in UTC
+	at := time.Date(2024, 6, 21, 2, 0, 0, 0, time.UTC)
+	astronomy := NewAstronomyInfo(40.7128, -74.0060, at.In(edt))
+	assert.EqualValues(t, 20, time.Unix(astronomy.Sunrise, 0).In(edt).Day())
+	astronomy = NewAstronomyInfo(40.7128, -74.0060, at)
+	assert.EqualValues(t, 21, time.Unix(astronomy.Sunrise, 0).In(edt).Day())
+
+	//Kiritimati keeps UTC+14 at a longitude of UTC-10:30, its solar noon is on the previous UTC day
+	lint := time.FixedZone("LINT", 14*3600)
+	for _, hour := range []int{0, 12, 23} {
+		astronomy = NewAstronomyInfo(1.8721, -157.4278, time.Date(2024, 6, 21, hour, 30, 0, 0, lint))
+		for name, event := range map[string]int64{"sunrise": astronomy.Sunrise, "solar noon": astronomy.SolarNoon, "sunset": astronomy.Sunset} {
+			assert.EqualValues(t, 21, time.Unix(event, 0).In(lint).Day(), "%s at %02d:30", name, hour)
+		}
+	}
+}
+
+//Lunar phases of the USNO (https://aa.usno.navy.mil/data/MoonPhases) for 2024, in UTC
+func TestNewMoonInfoPhases(t *testing.T) {
+	phases := []struct {
+		at           time.Time
+		phase        float64
+		name         string
+		illumination float64
+	}{
+		{time.Date(2024, 4, 8, 18, 21, 0, 0, time.UTC), 0, MoonNew, 0},
+		{time.Date(2024, 4, 15, 19, 13, 0, 0, time.UTC), 0.25, MoonFirstQuarter, 0.5},
+		{time.Date(2024, 4, 23, 23, 49, 0, 0, time.UTC), 0.5, MoonFull, 1},
+		{time.Date(2024, 5, 1, 11, 27, 0, 0, time.UTC), 0.75, MoonLastQuarter, 0.5},
+	}
+	for _, phase := range phases {
+		moon := NewMoonInfo(phase.at)
+		//A new moon may come out just before or after the turn of the cycle
+		distance := moon.Phase - phase.phase
+		if distance > 0.5 {
+			distance--
+		}
+		assert.InDelta(t, 0, distance, 0.01, "%s", phase.at)
+		assert.EqualValues(t, phase.name, moon.PhaseName, "%s", phase.at)
+		assert.InDelta(t, phase.illumination, moon.Illumination, 0.02, "%s", phase.at)
+	}
+
+	waxing := NewMoonInfo(time.Date(2024, 4, 11, 12, 0, 0, 0, time.UTC))
+	assert.EqualValues(t, MoonWaxingCrescent, waxing.PhaseName)
+	waning := NewMoonInfo(time.Date(2024, 4, 27, 12, 0, 0, 0, time.UTC))
+	assert.EqualValues(t, MoonWaningGibbous, waning.PhaseName)
+}
@@ -0,0 +1,148 @@
+package weather_domain
+
+import "math"
+
+//The unit systems of the upstream: us is °F and mph, si is °C and m/s, ca is °C and km/h, uk2 is °C and mph
+const (
+	UnitsUS  = "us"
+	UnitsSI  = "si"
+	UnitsCA  = "ca"
+	UnitsUK2 = "uk2"
+)
+
+//Comfort categories, the bands follow the Environment Canada humidex and wind chill guidance
+const (
+	ComfortDangerousHeat   = "dangerous heat"
+	ComfortGreatDiscomfort = "great discomfort"
+	ComfortSomeDiscomfort  = "some discomfort"
+	ComfortComfortable     = "comfortable"
+	ComfortCool            = "cool"
+	ComfortCold            = "cold"
+	ComfortVeryCold        = "very cold"
+	ComfortExtremeCold     = "extreme cold"
+)
+
+//ComfortInfo holds what the weather feels like. Temperatures are in the unit of the response they belong to
+type ComfortInfo struct {
+	ApparentTemperature float64 `json:"apparentTemperature" xml:"apparentTemperature"`
+	HeatIndex           float64 `json:"heatIndex" xml:"heatIndex"`
+	WindChill           float64 `json:"windChill" xml:"windChill"`
+	Humidex             float64 `json:"humidex" xml:"humidex"`
+	Category            string  `json:"category" xml:"category"`
+}
+
+//NewComfortInfo derives the comfort metrics from the raw values, whichever unit system they are in
+func NewComfortInfo(currently CurrentlyInfo, units string) ComfortInfo {
+	celsius := currently.Temperature
+	dewPointCelsius := currently.DewPoint
+	if units != UnitsSI && units != UnitsCA && units != UnitsUK2 {
+		units = UnitsUS
+		celsius = fahrenheitToCelsius(currently.Temperature)
+		dewPointCelsius = fahrenheitToCelsius(currently.DewPoint)
+	}
+	windMetersPerSecond := currently.WindSpeed
+	switch units {
+	case UnitsUS, UnitsUK2:
+		windMetersPerSecond = currently.WindSpeed * 0.44704
+	case UnitsCA:
+		windMetersPerSecond = currently.WindSpeed / 3.6
+	}
+	relativeHumidity := currently.Humidity * 100
+
+	apparent := ApparentTemperature(celsius, relativeHumidity, windMetersPerSecond)
+	heatIndex := fahrenheitToCelsius(HeatIndex(celsiusToFahrenheit(celsius), relativeHumidity))
+	windChill := fahrenheitToCelsius(WindChill(celsiusToFahrenheit(celsius), windMetersPerSecond/0.44704))
+	humidex := Humidex(celsius, dewPointCelsius)
+	category := comfortCategory(celsius, apparent, windChill, humidex)
+
+	if units == UnitsUS {
+		apparent = celsiusToFahrenheit(apparent)
+		heatIndex = celsiusToFahrenheit(heatIndex)
+		windChill = celsiusToFahrenheit(windChill)
+		humidex = celsiusToFahrenheit(humidex)
+	}
+	return ComfortInfo{
+		ApparentTemperature: round(apparent),
+		HeatIndex:           round(heatIndex),
+		WindChill:           round(windChill),
+		Humidex:             round(humidex),
+		Category:            category,
+	}
+}
+
+//ApparentTemperature is the Australian Bureau of Meteorology (Steadman) apparent temperature in °C,
+//from the air temperature in °C, relative humidity in % and wind speed in m/s
+func ApparentTemperature(celsius float64, relativeHumidity float64, windMetersPerSecond float64) float64 {
+	vapourPressure := relativeHumidity / 100 * 6.105 * math.Exp(17.27*celsius/(237.7+celsius))
+	return celsius + 0.33*vapourPressure - 0.70*windMetersPerSecond - 4.00
+}
+
+//HeatIndex is the US National Weather Service heat index in °F, from °F and relative humidity in %.
+//Like the NWS calculator it is the air temperature itself at 40°F and below
+func HeatIndex(fahrenheit float64, relativeHumidity float64) float64 {
+	t, rh := fahrenheit, relativeHumidity
+	if t <= 40 {
+		return t
+	}
+	heatIndex := 0.5 * (t + 61.0 + (t-68.0)*1.2 + rh*0.094)
+	if (heatIndex+t)/2 < 80 {
+		return heatIndex
+	}
+	heatIndex = -42.379 + 2.04901523*t + 10.14333127*rh - 0.22475541*t*rh - 0.00683783*t*t -
+		0.05481717*rh*rh + 0.00122874*t*t*rh + 0.00085282*t*rh*rh - 0.00000199*t*t*rh*rh
+	if rh < 13 && t >= 80 && t <= 112 {
+		heatIndex -= (13 - rh) / 4 * math.Sqrt((17-math.Abs(t-95))/17)
+	} else if rh > 85 && t >= 80 && t <= 87 {
+		heatIndex += (rh - 85) / 10 * ((87 - t) / 5)
+	}
+	return heatIndex
+}
+
+//WindChill is the US National Weather Service wind chill in °F, from °F and wind speed in mph.
+//Outside of the range the formula is defined for (50°F and below, 3 mph and above) it is the air temperature
+func WindChill(fahrenheit float64, windMilesPerHour float64) float64 {
+	if fahrenheit > 50 || windMilesPerHour < 3 {
+		return fahrenheit
+	}
+	wind := math.Pow(windMilesPerHour, 0.16)
+	return 35.74 + 0.6215*fahrenheit - 35.75*wind + 0.4275*fahrenheit*wind
+}
+
+//Humidex is the Environment Canada humidex, from the air temperature and dew point in °C
+func Humidex(celsius float64, dewPointCelsius float64) float64 {
+	vapourPressure := 6.11 * math.Exp(5417.7530*(1/273.16-1/(273.15+dewPointCelsius)))
+	return celsius + 0.5555*(vapourPressure-10)
+}
+
+func comfortCategory(celsius float64, apparent float64, windChill float64, humidex float64) string {
+	switch {
+	case celsius >= 20 && humidex > 45:
+		return ComfortDangerousHeat
+	case celsius >= 20 && humidex >= 40:
+		return ComfortGreatDiscomfort
+	case celsius >= 20 && humidex >= 30:
+		return ComfortSomeDiscomfort
+	case windChill <= -40:
+		return ComfortExtremeCold
+	case windChill <= -28:
+		return ComfortVeryCold
+	case windChill <= -10 || apparent <= 0:
+		return ComfortCold
+	case apparent < 10:
+		return ComfortCool
+	default:
+		return ComfortComfortable
+	}
+}
+
+func fahrenheitToCelsius(fahrenheit float64) float64 {
+	return (fahrenheit - 32) * 5 / 9
+}
+
+func celsiusToFahrenheit(celsius float64) float64 {
+	return celsius*9/5 + 32
+}
+
+func round(value float64) float64 {
+	return math.Round(value*10) / 10
+}
@@ -0,0 +1,92 @@
+package weather_domain
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//Values from the NWS heat index chart (https://www.weather.gov/safety/heat-index), temperature in °F and humidity in %
+func TestHeatIndexNwsChart(t *testing.T) {
+	chart := []struct{ temperature, humidity, heatIndex float64 }{
+		{80, 40, 80},
+		{90, 40, 91},
+		{90, 50, 95},
+		{90, 60, 100},
+		{90, 70, 106},
+		{90, 80, 113},
+		{90, 90, 122},
+		{96, 50, 108},
+		{100, 40, 109},
+		{100, 50, 118},
+		{100, 55, 124},
+		{104, 55, 137},
+		{86, 90, 105},
+	}
+	for _, row := range chart {
+		assert.EqualValues(t, row.heatIndex, math.Round(HeatIndex(row.temperature, row.humidity)), "%v°F at %v%%", row.temperature, row.humidity)
+	}
+	assert.EqualValues(t, 20, HeatIndex(20, 90))
+}
+
+//Values from the NWS wind chill chart (https://www.weather.gov/safety/cold-wind-chill-chart), temperature in °F and wind in mph
+func TestWindChillNwsChart(t *testing.T) {
+	chart := []struct{ temperature, wind, windChill float64 }{
+		{40, 5, 36},
+		{20, 5, 13},
+		{-10, 5, -22},
+		{10, 15, -7},
+		{0, 15, -19},
+		{30, 10, 21},
+		{-10, 20, -35},
+		{30, 30, 15},
+		{0, 30, -26},
+		{-10, 30, -39},
+	}
+	for _, row := range chart {
+		assert.EqualValues(t, row.windChill, math.Round(WindChill(row.temperature, row.wind)), "%v°F at %v mph", row.temperature, row.wind)
+	}
+	//The formula is not defined above 50°F or below 3 mph
+	assert.EqualValues(t, 60, WindChill(60, 20))
+	assert.EqualValues(t, 20, WindChill(20, 2))
+}
+
+//Examples from Environment Canada's humidex description, temperature and dew point in °C
+func TestHumidexEnvironmentCanada(t *testing.T) {
+	assert.EqualValues(t, 34, math.Round(Humidex(30, 15)))
+	assert.EqualValues(t, 42, math.Round(Humidex(30, 25)))
+	assert.EqualValues(t, 47, math.Round(Humidex(35, 25)))
+}
+
+func TestApparentTemperature(t *testing.T) {
+	//e = 0.5 * 6.105 * exp(17.27 * 30 / 267.7) = 21.21 hPa, so AT = 30 + 0.33 * 21.21 - 4
+	assert.InDelta(t, 33.0, ApparentTemperature(30, 50, 0), 0.05)
+	assert.InDelta(t, 5.3, ApparentTemperature(10, 70, 5), 0.05)
+}
+
+func TestNewComfortInfoUnits(t *testing.T) {
+	//The same conditions in every unit system give the same comfort
+	us := NewComfortInfo(CurrentlyInfo{Temperature: 95, DewPoint: 77, Humidity: 0.56, WindSpeed: 11.18}, UnitsUS)
+	si := NewComfortInfo(CurrentlyInfo{Temperature: 35, DewPoint: 25, Humidity: 0.56, WindSpeed: 5}, UnitsSI)
+	ca := NewComfortInfo(CurrentlyInfo{Temperature: 35, DewPoint: 25, Humidity: 0.56, WindSpeed: 18}, UnitsCA)
+
+	assert.InDelta(t, si.ApparentTemperature, ca.ApparentTemperature, 0.1)
+	assert.InDelta(t, celsiusToFahrenheit(si.ApparentTemperature), us.ApparentTemperature, 0.2)
+	assert.InDelta(t, celsiusToFahrenheit(si.HeatIndex), us.HeatIndex, 0.2)
+	assert.InDelta(t, celsiusToFahrenheit(si.Humidex), us.Humidex, 0.2)
+	assert.EqualValues(t, 47.3, si.Humidex)
+	assert.EqualValues(t, ComfortDangerousHeat, si.Category)
+	assert.EqualValues(t, ComfortDangerousHeat, us.Category)
+	assert.EqualValues(t, ComfortDangerousHeat, ca.Category)
+}
+
+func TestNewComfortInfoCategories(t *testing.T) {
+	assert.EqualValues(t, ComfortComfortable, NewComfortInfo(CurrentlyInfo{Temperature: 21, DewPoint: 10, Humidity: 0.5, WindSpeed: 2}, UnitsSI).Category)
+	assert.EqualValues(t, ComfortSomeDiscomfort, NewComfortInfo(CurrentlyInfo{Temperature: 30, DewPoint: 15, Humidity: 0.4}, UnitsSI).Category)
+	assert.EqualValues(t, ComfortGreatDiscomfort, NewComfortInfo(CurrentlyInfo{Temperature: 30, DewPoint: 25, Humidity: 0.75}, UnitsSI).Category)
+	assert.EqualValues(t, ComfortCool, NewComfortInfo(CurrentlyInfo{Temperature: 8, DewPoint: 2, Humidity: 0.6, WindSpeed: 1}, UnitsSI).Category)
+	assert.EqualValues(t, ComfortCold, NewComfortInfo(CurrentlyInfo{Temperature: 14, DewPoint: 0, Humidity: 0.5, WindSpeed: 15}, UnitsUS).Category)
+	assert.EqualValues(t, ComfortVeryCold, NewComfortInfo(CurrentlyInfo{Temperature: -20, DewPoint: -25, Humidity: 0.6, WindSpeed: 30}, UnitsCA).Category)
+	assert.EqualValues(t, ComfortExtremeCold, NewComfortInfo(CurrentlyInfo{Temperature: -20, DewPoint: -30, Humidity: 0.6, WindSpeed: 30}, UnitsUS).Category)
+}
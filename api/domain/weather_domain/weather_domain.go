@@ -1,24 +1,114 @@
 package weather_domain
 
+import "time"
+
 type Weather struct {
-	Latitude float64 `json:"latitude"`
-	Longitude float64 `json:"longitude"`
-	TimeZone string `json:"timezone"`
-	Currently CurrentlyInfo `json:"currently"`
+	Latitude float64 `json:"latitude" xml:"latitude"`
+	Longitude float64 `json:"longitude" xml:"longitude"`
+	TimeZone string `json:"timezone" xml:"timezone"`
+	Currently CurrentlyInfo `json:"currently" xml:"currently"`
+	//LocalTime resolves TimeZone, it is left out when neither the provider nor the coordinates give a usable one
+	LocalTime *LocalTimeInfo `json:"localTime,omitempty" xml:"localTime,omitempty"`
+	Comfort *ComfortInfo `json:"comfort,omitempty" xml:"comfort,omitempty"`
+	//Astronomy is computed from the location, it costs no upstream call
+	Astronomy *AstronomyInfo `json:"astronomy,omitempty" xml:"astronomy,omitempty"`
+	Flags *FlagsInfo `json:"flags,omitempty" xml:"flags,omitempty"`
+	Provider string `json:"provider,omitempty" xml:"provider,omitempty"`
+	Ensemble *EnsembleInfo `json:"ensemble,omitempty" xml:"ensemble,omitempty"`
+	//Hourly is the forecast hour by hour, only filled when the request asked for it
+	Hourly []HourlyInfo `json:"hourly,omitempty" xml:"hourly>hour,omitempty"`
+	//AirQuality is only filled when the request asked for it and the air quality provider answered
+	AirQuality *AirQualityInfo `json:"airQuality,omitempty" xml:"airQuality,omitempty"`
+	//Cache is sent in the response headers rather than in the body
+	Cache *CacheInfo `json:"-" xml:"-"`
 }
 
 type CurrentlyInfo struct {
-	Temperature float64 `json:"temperature"`
-	Summary string `json:"summary"`
-	DewPoint float64 `json:"dewPoint"`
-	Pressure float64 `json:"pressure"`
-	Humidity float64 `json:"humidity"`
+	Temperature float64 `json:"temperature" xml:"temperature"`
+	Summary string `json:"summary" xml:"summary"`
+	DewPoint float64 `json:"dewPoint" xml:"dewPoint"`
+	Pressure float64 `json:"pressure" xml:"pressure"`
+	Humidity float64 `json:"humidity" xml:"humidity"`
+	WindSpeed float64 `json:"windSpeed" xml:"windSpeed"`
+	WindGust float64 `json:"windGust" xml:"windGust"`
+	//Time is when the values were observed, in unix seconds
+	Time int64 `json:"time" xml:"time"`
+	//Unavailable names the fields the upstream did not send or sent unusable, their value is left at zero
+	Unavailable []string `json:"unavailable,omitempty" xml:"unavailable>field,omitempty"`
+}
+
+//IsUnavailable tells whether field, named as in json, was not sent by the upstream
+func (c CurrentlyInfo) IsUnavailable(field string) bool {
+	for _, unavailable := range c.Unavailable {
+		if unavailable == field {
+			return true
+		}
+	}
+	return false
+}
+
+//HourlyInfo is the forecast for the hour starting at Time, in unix seconds
+type HourlyInfo struct {
+	Time int64 `json:"time" xml:"time"`
+	Temperature float64 `json:"temperature" xml:"temperature"`
+	Summary string `json:"summary" xml:"summary"`
+	DewPoint float64 `json:"dewPoint" xml:"dewPoint"`
+	Pressure float64 `json:"pressure" xml:"pressure"`
+	Humidity float64 `json:"humidity" xml:"humidity"`
+	WindSpeed float64 `json:"windSpeed" xml:"windSpeed"`
+	WindGust float64 `json:"windGust" xml:"windGust"`
+	//PrecipProbability is the chance of precipitation, between 0 and 1
+	PrecipProbability float64 `json:"precipProbability" xml:"precipProbability"`
+}
+
+//FlagsInfo is the metadata the upstream sends along, Units tells which unit system the values are in
+type FlagsInfo struct {
+	Units string `json:"units" xml:"units"`
+}
+
+//How a response was served: from the provider, from a fresh cache entry, or from an expired one
+const (
+	CacheMiss         = "MISS"
+	CacheHit          = "HIT"
+	CacheStale        = "STALE"
+	//CacheStaleIfError is an expired entry served because the provider failed
+	CacheStaleIfError = "STALE-IF-ERROR"
+)
+
+type CacheInfo struct {
+	Status string
+	Age time.Duration
+	//MaxAge is how long the response stays fresh, zero once it is stale
+	MaxAge time.Duration
+	//FetchedAt is when the response came from the provider, it stays the same for as long as the response is cached
+	FetchedAt time.Time
 }
 
 type WeatherRequest struct {
 	ApiKey string `json:"api_key"`
 	Latitude float64 `json:"latitude"`
 	Longitude float64 `json:"longitude"`
+	Units string `json:"units,omitempty"`
+	Lang string `json:"lang,omitempty"`
+	//Ensemble is the merge method when every provider should be asked, empty for a single provider
+	Ensemble string `json:"ensemble,omitempty"`
+	//Hourly asks the provider for the hourly forecast along with the current conditions
+	Hourly bool `json:"hourly,omitempty"`
+	//AirQuality asks for the pollutants, pollen and air quality index along with the weather
+	AirQuality bool `json:"airQuality,omitempty"`
 }
 
 
+//WeatherUpdate is what a stream subscriber receives whenever the polled weather for a location changes
+type WeatherUpdate struct {
+	Request WeatherRequest
+	Weather *Weather
+	Err     WeatherErrorInterface
+}
+
+//WeatherSubscription is sent by websocket clients to add or remove a location from their stream
+type WeatherSubscription struct {
+	Action    string  `json:"action"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
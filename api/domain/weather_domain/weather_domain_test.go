@@ -2,8 +2,10 @@ package weather_domain
 
 import (
 	"encoding/json"
+	"github.com/golang/protobuf/proto"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestWeather(t *testing.T) {
@@ -52,4 +54,184 @@ func TestWeatherError(t *testing.T) {
 	assert.EqualValues(t, errResult.Code, request.Code)
 	assert.EqualValues(t, errResult.ErrorMessage, request.ErrorMessage)
 
-}
\ No newline at end of file
+}
+func TestWeatherProto(t *testing.T) {
+	request := Weather{
+		Latitude:  12.33,
+		Longitude: 90.34,
+		TimeZone:  "America/New_York",
+		Currently: CurrentlyInfo{Temperature: 10, Summary: "Clear", DewPoint: 20.433, Pressure: 95.33, Humidity: 71.34},
+		Flags:     &FlagsInfo{Units: "si"},
+	}
+	bytes, err := proto.Marshal(request.Proto())
+	assert.Nil(t, err)
+
+	var result WeatherMessage
+	assert.Nil(t, proto.Unmarshal(bytes, &result))
+	assert.EqualValues(t, request.Latitude, result.Latitude)
+	assert.EqualValues(t, request.TimeZone, result.TimeZone)
+	assert.EqualValues(t, request.Currently.Summary, result.Currently.Summary)
+	assert.EqualValues(t, request.Currently.Humidity, result.Currently.Humidity)
+	assert.EqualValues(t, "si", result.Flags.Units)
+
+	bytes, err = proto.Marshal((&Weather{}).Proto())
+	assert.Nil(t, err)
+	result = WeatherMessage{}
+	assert.Nil(t, proto.Unmarshal(bytes, &result))
+	assert.Nil(t, result.Flags)
+
+	errBytes, err := proto.Marshal((&WeatherError{Code: 400, ErrorMessage: "Bad Request Error"}).Proto())
+	assert.Nil(t, err)
+	var errResult WeatherErrorMessage
+	assert.Nil(t, proto.Unmarshal(errBytes, &errResult))
+	assert.EqualValues(t, 400, errResult.Code)
+	assert.EqualValues(t, "Bad Request Error", errResult.Error)
+}
+
+func TestWeatherProtoEnsemble(t *testing.T) {
+	request := Weather{
+		Currently: CurrentlyInfo{Temperature: 10.5, Summary: "Clear"},
+		Ensemble: &EnsembleInfo{
+			Method: EnsembleMedian,
+			Spread: SpreadInfo{Temperature: RangeInfo{Min: 10, Max: 11}, Humidity: RangeInfo{Min: 0.4, Max: 0.6}},
+			Providers: []EnsembleMember{
+				{Provider: "darksky", Weight: 1, TimeZone: "America/New_York", Currently: &CurrentlyInfo{Temperature: 10, Summary: "Clear"}},
+				{Provider: "openmeteo", Weight: 0.5, Error: &WeatherError{Code: 503, ErrorMessage: "unavailable"}},
+			},
+		},
+	}
+	bytes, err := proto.Marshal(request.Proto())
+	assert.Nil(t, err)
+
+	var result WeatherMessage
+	assert.Nil(t, proto.Unmarshal(bytes, &result))
+	assert.True(t, proto.Equal(request.Proto(), &result), "%s", result.String())
+	assert.EqualValues(t, EnsembleMedian, result.Ensemble.Method)
+	assert.EqualValues(t, 11, result.Ensemble.Spread.Temperature.Max)
+	assert.Len(t, result.Ensemble.Providers, 2)
+	assert.EqualValues(t, "Clear", result.Ensemble.Providers[0].Currently.Summary)
+	assert.Nil(t, result.Ensemble.Providers[0].Error)
+	assert.EqualValues(t, 503, result.Ensemble.Providers[1].Error.Code)
+	assert.Nil(t, result.Ensemble.Providers[1].Currently)
+
+	bytes, err = proto.Marshal((&Weather{}).Proto())
+	assert.Nil(t, err)
+	result = WeatherMessage{}
+	assert.Nil(t, proto.Unmarshal(bytes, &result))
+	assert.Nil(t, result.Ensemble)
+}
+
+func TestWeatherProtoHourly(t *testing.T) {
+	request := Weather{
+		Currently: CurrentlyInfo{Temperature: 10.5, Summary: "Clear"},
+		Hourly: []HourlyInfo{
+			{Time: 1583064000, Temperature: 4.5, Summary: "Overcast", DewPoint: 1.4, Pressure: 1012.3, Humidity: 0.81, WindSpeed: 3.2, WindGust: 7.9, PrecipProbability: 0.1},
+			{Time: 1583067600, Temperature: 5, Summary: "Light Rain", PrecipProbability: 0.6},
+		},
+	}
+	bytes, err := proto.Marshal(request.Proto())
+	assert.Nil(t, err)
+
+	var result WeatherMessage
+	assert.Nil(t, proto.Unmarshal(bytes, &result))
+	assert.True(t, proto.Equal(request.Proto(), &result), "%s", result.String())
+	assert.Len(t, result.Hourly, 2)
+	assert.EqualValues(t, 1583064000, result.Hourly[0].Time)
+	assert.EqualValues(t, 7.9, result.Hourly[0].WindGust)
+	assert.EqualValues(t, "Light Rain", result.Hourly[1].Summary)
+	assert.EqualValues(t, 0.6, result.Hourly[1].PrecipProbability)
+}
+
+func TestWeatherProtoAirQuality(t *testing.T) {
+	pm25, no2, zero, birch := 12.0, 41.5, 0.0, 80.0
+	pollutants := PollutantsInfo{PM25: &pm25, NO2: &no2, SO2: &zero}
+	request := Weather{
+		Currently: CurrentlyInfo{Temperature: 10.5, Summary: "Clear"},
+		AirQuality: &AirQualityInfo{
+			Time:       1583064000,
+			Pollutants: pollutants,
+			Pollen:     &PollenInfo{Birch: &birch},
+			Aqi:        NewAqiInfo(pollutants),
+			Provider:   "openmeteo_air",
+		},
+	}
+	bytes, err := proto.Marshal(request.Proto())
+	assert.Nil(t, err)
+
+	var result WeatherMessage
+	assert.Nil(t, proto.Unmarshal(bytes, &result))
+	assert.True(t, proto.Equal(request.Proto(), &result), "%s", result.String())
+	assert.EqualValues(t, 1583064000, result.AirQuality.Time)
+	assert.EqualValues(t, "openmeteo_air", result.AirQuality.Provider)
+	assert.EqualValues(t, 12, *result.AirQuality.Pollutants.PM25)
+	assert.EqualValues(t, 41.5, *result.AirQuality.Pollutants.NO2)
+	//An unknown value stays apart from a zero one
+	assert.NotNil(t, result.AirQuality.Pollutants.SO2)
+	assert.EqualValues(t, 0, *result.AirQuality.Pollutants.SO2)
+	assert.Nil(t, result.AirQuality.Pollutants.O3)
+	assert.EqualValues(t, 80, *result.AirQuality.Pollen.Birch)
+	assert.Nil(t, result.AirQuality.Pollen.Grass)
+	assert.EqualValues(t, request.AirQuality.Aqi.Epa.Value, result.AirQuality.Aqi.Epa.Value)
+	assert.EqualValues(t, request.AirQuality.Aqi.Epa.Category, result.AirQuality.Aqi.Epa.Category)
+	assert.EqualValues(t, request.AirQuality.Aqi.Eu.Dominant, result.AirQuality.Aqi.Eu.Dominant)
+
+	request.AirQuality = &AirQualityInfo{Time: 1583064000}
+	bytes, err = proto.Marshal(request.Proto())
+	assert.Nil(t, err)
+	result = WeatherMessage{}
+	assert.Nil(t, proto.Unmarshal(bytes, &result))
+	assert.Nil(t, result.AirQuality.Pollen)
+	assert.Nil(t, result.AirQuality.Aqi.Epa)
+	assert.Nil(t, result.AirQuality.Aqi.Eu)
+}
+
+func TestWeatherProtoAstronomy(t *testing.T) {
+	//London at midsummer has no astronomical twilight, the night never gets fully dark
+	astronomy := NewAstronomyInfo(51.5074, -0.1278, time.Date(2024, 6, 21, 12, 0, 0, 0, time.UTC))
+	request := Weather{Currently: CurrentlyInfo{Temperature: 20, Summary: "Clear"}, Astronomy: &astronomy}
+	bytes, err := proto.Marshal(request.Proto())
+	assert.Nil(t, err)
+
+	var result WeatherMessage
+	assert.Nil(t, proto.Unmarshal(bytes, &result))
+	assert.True(t, proto.Equal(request.Proto(), &result), "%s", result.String())
+	assert.EqualValues(t, astronomy.Sunrise, result.Astronomy.Sunrise)
+	assert.EqualValues(t, astronomy.Sunset, result.Astronomy.Sunset)
+	assert.EqualValues(t, astronomy.SolarNoon, result.Astronomy.SolarNoon)
+	assert.EqualValues(t, astronomy.DayLength, result.Astronomy.DayLength)
+	assert.EqualValues(t, astronomy.CivilTwilight.Dawn, result.Astronomy.CivilTwilight.Dawn)
+	assert.EqualValues(t, astronomy.NauticalTwilight.Dusk, result.Astronomy.NauticalTwilight.Dusk)
+	assert.Nil(t, result.Astronomy.AstronomicalTwilight)
+	assert.EqualValues(t, astronomy.Moon.Phase, result.Astronomy.Moon.Phase)
+	assert.EqualValues(t, astronomy.Moon.PhaseName, result.Astronomy.Moon.PhaseName)
+	assert.EqualValues(t, astronomy.Moon.Illumination, result.Astronomy.Moon.Illumination)
+}
+
+func TestWeatherProtoLocalTime(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	assert.Nil(t, err)
+	localTime := NewLocalTimeInfo(time.Date(2024, 7, 1, 16, 0, 0, 0, time.UTC), newYork)
+	localTime.TimeZoneDerived = true
+	request := Weather{TimeZone: "America/New_York", Currently: CurrentlyInfo{Temperature: 80}, LocalTime: &localTime}
+	bytes, err := proto.Marshal(request.Proto())
+	assert.Nil(t, err)
+
+	var result WeatherMessage
+	assert.Nil(t, proto.Unmarshal(bytes, &result))
+	assert.True(t, proto.Equal(request.Proto(), &result), "%s", result.String())
+	assert.EqualValues(t, 1719849600, result.LocalTime.ObservedUtc)
+	assert.EqualValues(t, "2024-07-01T12:00:00-04:00", result.LocalTime.ObservedLocal)
+	observedLocal, err := time.Parse(time.RFC3339, result.LocalTime.ObservedLocal)
+	assert.Nil(t, err)
+	assert.True(t, localTime.ObservedUtc.Equal(observedLocal))
+	assert.EqualValues(t, -4*60*60, result.LocalTime.UtcOffset)
+	assert.EqualValues(t, "EDT", result.LocalTime.Abbreviation)
+	assert.True(t, result.LocalTime.Dst)
+	assert.True(t, result.LocalTime.TimeZoneDerived)
+
+	bytes, err = proto.Marshal((&Weather{}).Proto())
+	assert.Nil(t, err)
+	result = WeatherMessage{}
+	assert.Nil(t, proto.Unmarshal(bytes, &result))
+	assert.Nil(t, result.LocalTime)
+}
@@ -0,0 +1,135 @@
+package weather_domain
+
+import "sort"
+
+//How the ensemble combines the values of the providers
+const (
+	EnsembleMedian = "median"
+	EnsembleMean   = "mean"
+)
+
+//EnsembleInfo is added to an ensemble response: the per-provider values and how far apart they are
+type EnsembleInfo struct {
+	Method    string           `json:"method" xml:"method"`
+	Spread    SpreadInfo       `json:"spread" xml:"spread"`
+	Providers []EnsembleMember `json:"providers" xml:"providers>provider"`
+}
+
+//EnsembleMember is the answer of one provider, either Currently or Error is set
+type EnsembleMember struct {
+	Provider  string         `json:"provider" xml:"name"`
+	Weight    float64        `json:"weight" xml:"weight"`
+	TimeZone  string         `json:"timezone,omitempty" xml:"timezone,omitempty"`
+	Currently *CurrentlyInfo `json:"currently,omitempty" xml:"currently,omitempty"`
+	Error     *WeatherError  `json:"error,omitempty" xml:"error,omitempty"`
+}
+
+//SpreadInfo holds the lowest and highest value the providers gave for each field
+type SpreadInfo struct {
+	Temperature RangeInfo `json:"temperature" xml:"temperature"`
+	DewPoint    RangeInfo `json:"dewPoint" xml:"dewPoint"`
+	Pressure    RangeInfo `json:"pressure" xml:"pressure"`
+	Humidity    RangeInfo `json:"humidity" xml:"humidity"`
+	WindSpeed   RangeInfo `json:"windSpeed" xml:"windSpeed"`
+	WindGust    RangeInfo `json:"windGust" xml:"windGust"`
+}
+
+type RangeInfo struct {
+	Min float64 `json:"min" xml:"min"`
+	Max float64 `json:"max" xml:"max"`
+}
+
+//ensembleFields pairs every numeric CurrentlyInfo field, by its json name, with its spread
+var ensembleFields = []struct {
+	name   string
+	value  func(c *CurrentlyInfo) *float64
+	spread func(s *SpreadInfo) *RangeInfo
+}{
+	{"temperature", func(c *CurrentlyInfo) *float64 { return &c.Temperature }, func(s *SpreadInfo) *RangeInfo { return &s.Temperature }},
+	{"dewPoint", func(c *CurrentlyInfo) *float64 { return &c.DewPoint }, func(s *SpreadInfo) *RangeInfo { return &s.DewPoint }},
+	{"pressure", func(c *CurrentlyInfo) *float64 { return &c.Pressure }, func(s *SpreadInfo) *RangeInfo { return &s.Pressure }},
+	{"humidity", func(c *CurrentlyInfo) *float64 { return &c.Humidity }, func(s *SpreadInfo) *RangeInfo { return &s.Humidity }},
+	{"windSpeed", func(c *CurrentlyInfo) *float64 { return &c.WindSpeed }, func(s *SpreadInfo) *RangeInfo { return &s.WindSpeed }},
+	{"windGust", func(c *CurrentlyInfo) *float64 { return &c.WindGust }, func(s *SpreadInfo) *RangeInfo { return &s.WindGust }},
+}
+
+//MergeEnsemble combines the members that answered into one CurrentlyInfo, by median or weighted mean.
+//The summary is the one most (weighted) providers agree on. A field a member could not give is left out of
+//that field, and is unavailable in the merge when no member gave it. ok is false when no member answered
+func MergeEnsemble(members []EnsembleMember, method string) (merged CurrentlyInfo, spread SpreadInfo, ok bool) {
+	var answered []EnsembleMember
+	for _, member := range members {
+		if member.Currently != nil {
+			if member.Weight <= 0 {
+				member.Weight = 1
+			}
+			answered = append(answered, member)
+		}
+	}
+	if len(answered) == 0 {
+		return CurrentlyInfo{}, SpreadInfo{}, false
+	}
+
+	for _, field := range ensembleFields {
+		var values, weights []float64
+		for _, member := range answered {
+			if !member.Currently.IsUnavailable(field.name) {
+				values = append(values, *field.value(member.Currently))
+				weights = append(weights, member.Weight)
+			}
+		}
+		if len(values) == 0 {
+			merged.Unavailable = append(merged.Unavailable, field.name)
+			continue
+		}
+		if method == EnsembleMean {
+			*field.value(&merged) = weightedMean(values, weights)
+		} else {
+			*field.value(&merged) = median(values)
+		}
+		sort.Float64s(values)
+		*field.spread(&spread) = RangeInfo{Min: values[0], Max: values[len(values)-1]}
+	}
+
+	//The merged values are as recent as the latest observation that went into them
+	for _, member := range answered {
+		if member.Currently.Time > merged.Time {
+			merged.Time = member.Currently.Time
+		}
+	}
+
+	votes := map[string]float64{}
+	for _, member := range answered {
+		if !member.Currently.IsUnavailable("summary") {
+			votes[member.Currently.Summary] += member.Weight
+		}
+	}
+	if len(votes) == 0 {
+		merged.Unavailable = append(merged.Unavailable, "summary")
+	}
+	for _, member := range answered {
+		if summary := member.Currently.Summary; !member.Currently.IsUnavailable("summary") && (merged.Summary == "" || votes[summary] > votes[merged.Summary]) {
+			merged.Summary = summary
+		}
+	}
+	return merged, spread, true
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
+
+func weightedMean(values []float64, weights []float64) float64 {
+	var sum, total float64
+	for i, value := range values {
+		sum += value * weights[i]
+		total += weights[i]
+	}
+	return sum / total
+}
@@ -0,0 +1,75 @@
+package weather_domain
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var ensembleMembers = []EnsembleMember{
+	{Provider: "a", Weight: 1, Currently: &CurrentlyInfo{Temperature: 10, Summary: "Clear", Humidity: 0.5, Pressure: 1010, Time: 1583064000}},
+	{Provider: "b", Weight: 1, Currently: &CurrentlyInfo{Temperature: 12, Summary: "Overcast", Humidity: 0.6, Pressure: 1012, Time: 1583064900}},
+	{Provider: "c", Weight: 2, Currently: &CurrentlyInfo{Temperature: 20, Summary: "Overcast", Humidity: 0.7, Pressure: 1020}},
+	{Provider: "d", Weight: 5, Error: &WeatherError{Code: http.StatusBadGateway, ErrorMessage: "unreachable"}},
+}
+
+func TestMergeEnsembleMedian(t *testing.T) {
+	merged, spread, ok := MergeEnsemble(ensembleMembers, EnsembleMedian)
+	assert.True(t, ok)
+	assert.EqualValues(t, 12, merged.Temperature)
+	assert.EqualValues(t, 0.6, merged.Humidity)
+	assert.EqualValues(t, 1012, merged.Pressure)
+	assert.EqualValues(t, "Overcast", merged.Summary)
+	assert.EqualValues(t, 1583064900, merged.Time)
+	assert.EqualValues(t, RangeInfo{Min: 10, Max: 20}, spread.Temperature)
+	assert.EqualValues(t, RangeInfo{Min: 1010, Max: 1020}, spread.Pressure)
+}
+
+func TestMergeEnsembleMedianEvenCount(t *testing.T) {
+	merged, _, ok := MergeEnsemble(ensembleMembers[:2], EnsembleMedian)
+	assert.True(t, ok)
+	assert.EqualValues(t, 11, merged.Temperature)
+}
+
+func TestMergeEnsembleWeightedMean(t *testing.T) {
+	//The failed member's weight does not count
+	merged, _, ok := MergeEnsemble(ensembleMembers, EnsembleMean)
+	assert.True(t, ok)
+	assert.EqualValues(t, 15.5, merged.Temperature)
+	assert.InDelta(t, 0.625, merged.Humidity, 1e-9)
+}
+
+func TestMergeEnsembleSummaryVote(t *testing.T) {
+	members := []EnsembleMember{
+		{Provider: "a", Weight: 3, Currently: &CurrentlyInfo{Summary: "Clear"}},
+		{Provider: "b", Currently: &CurrentlyInfo{Summary: "Overcast"}},
+		{Provider: "c", Currently: &CurrentlyInfo{Summary: "Overcast"}},
+	}
+	merged, _, _ := MergeEnsemble(members, EnsembleMedian)
+	assert.EqualValues(t, "Clear", merged.Summary)
+}
+
+func TestMergeEnsembleNoAnswer(t *testing.T) {
+	_, _, ok := MergeEnsemble(ensembleMembers[3:], EnsembleMedian)
+	assert.False(t, ok)
+	_, _, ok = MergeEnsemble(nil, EnsembleMean)
+	assert.False(t, ok)
+}
+
+func TestMergeEnsembleUnavailable(t *testing.T) {
+	members := []EnsembleMember{
+		{Provider: "a", Currently: &CurrentlyInfo{Temperature: 10, Summary: "Clear", Unavailable: []string{"windGust"}}},
+		{Provider: "b", Currently: &CurrentlyInfo{Summary: "Overcast", WindSpeed: 4, Unavailable: []string{"temperature", "summary", "windGust"}}},
+	}
+	merged, spread, ok := MergeEnsemble(members, EnsembleMean)
+	assert.True(t, ok)
+	//The member without a temperature does not pull the mean to zero
+	assert.EqualValues(t, 10, merged.Temperature)
+	assert.EqualValues(t, RangeInfo{Min: 10, Max: 10}, spread.Temperature)
+	assert.EqualValues(t, 2, merged.WindSpeed)
+	assert.EqualValues(t, "Clear", merged.Summary)
+	assert.EqualValues(t, []string{"windGust"}, merged.Unavailable)
+	assert.True(t, merged.IsUnavailable("windGust"))
+	assert.False(t, merged.IsUnavailable("temperature"))
+}
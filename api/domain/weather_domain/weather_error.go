@@ -10,8 +10,8 @@ type WeatherErrorInterface interface {
 	Message() string
 }
 type WeatherError struct {
-	Code      int           `json:"code"`
-	ErrorMessage     string        `json:"error"`
+	Code      int           `json:"code" xml:"code"`
+	ErrorMessage     string        `json:"error" xml:"error"`
 }
 
 func (w *WeatherError) Status() int {
@@ -21,6 +21,12 @@ func (w *WeatherError) Message() string {
 	return w.ErrorMessage
 }
 
+//Retryable tells whether another provider may succeed where this one failed: upstream outages,
+//timeouts and rate limits are, while a bad key or an invalid location would fail everywhere
+func (w *WeatherError) Retryable() bool {
+	return w.Code >= http.StatusInternalServerError || w.Code == http.StatusTooManyRequests || w.Code == http.StatusRequestTimeout
+}
+
 func NewWeatherError(statusCode int, message string) WeatherErrorInterface {
 	return &WeatherError{
 		Code:         statusCode,
@@ -34,6 +40,13 @@ func NewBadRequestError(message string) WeatherErrorInterface {
 	}
 }
 
+func NewUnauthorizedError(message string) WeatherErrorInterface {
+	return &WeatherError{
+		Code: http.StatusUnauthorized,
+		ErrorMessage: message,
+	}
+}
+
 func NewForbiddenError(message string) WeatherErrorInterface {
 	return &WeatherError{
 		Code: http.StatusForbidden,
@@ -41,6 +54,20 @@ func NewForbiddenError(message string) WeatherErrorInterface {
 	}
 }
 
+func NewNotFoundError(message string) WeatherErrorInterface {
+	return &WeatherError{
+		Code: http.StatusNotFound,
+		ErrorMessage: message,
+	}
+}
+
+func NewNotAcceptableError(message string) WeatherErrorInterface {
+	return &WeatherError{
+		Code: http.StatusNotAcceptable,
+		ErrorMessage: message,
+	}
+}
+
 func NewApiErrFromBytes(body []byte) (WeatherErrorInterface, error) {
 	var result WeatherError
 	if err := json.Unmarshal(body, &result); err != nil {
@@ -0,0 +1,89 @@
+package weather_domain
+
+import (
+	"interface-testing/api/domain/client_domain"
+	"math"
+)
+
+//gridEpsilon absorbs the floating point error of stepping by the resolution, so a bound that falls on the grid
+//is not missed by a hair
+const gridEpsilon = 1e-9
+
+//GridRequest asks for the weather over a bounding box, sampled every Resolution degrees.
+//Request carries the api key, units, language and ensemble method, its location is ignored
+type GridRequest struct {
+	Request    WeatherRequest
+	South      float64
+	West       float64
+	North      float64
+	East       float64
+	Resolution float64
+	//Client is charged a request for every point of the grid, nil when client keys are off
+	Client *client_domain.Client
+}
+
+//Grid is the weather over a bounding box. Cells are listed row by row, from the south west corner going east then north
+type Grid struct {
+	South      float64    `json:"south" xml:"south"`
+	West       float64    `json:"west" xml:"west"`
+	North      float64    `json:"north" xml:"north"`
+	East       float64    `json:"east" xml:"east"`
+	Resolution float64    `json:"resolution" xml:"resolution"`
+	Rows       int        `json:"rows" xml:"rows"`
+	Columns    int        `json:"columns" xml:"columns"`
+	Cells      []GridCell `json:"cells" xml:"cells>cell"`
+}
+
+//GridCell is one sample of the grid, either Weather or Error is set
+type GridCell struct {
+	Row       int           `json:"row" xml:"row"`
+	Column    int           `json:"column" xml:"column"`
+	Latitude  float64       `json:"latitude" xml:"latitude"`
+	Longitude float64       `json:"longitude" xml:"longitude"`
+	Weather   *Weather      `json:"weather,omitempty" xml:"weather,omitempty"`
+	Error     *WeatherError `json:"error,omitempty" xml:"error,omitempty"`
+}
+
+//GridSize is how many rows and columns the request samples, without building them.
+//They are counted in floats so that an absurdly fine resolution cannot overflow
+func (r GridRequest) GridSize() (float64, float64) {
+	return gridSteps(r.South, r.North, r.Resolution), gridSteps(r.West, r.East, r.Resolution)
+}
+
+//NewGrid lays out the cells of the request, with no weather yet
+func NewGrid(r GridRequest) *Grid {
+	rowSteps, columnSteps := r.GridSize()
+	rows, columns := int(rowSteps), int(columnSteps)
+	grid := &Grid{
+		South:      r.South,
+		West:       r.West,
+		North:      r.North,
+		East:       r.East,
+		Resolution: r.Resolution,
+		Rows:       rows,
+		Columns:    columns,
+		Cells:      make([]GridCell, 0, rows*columns),
+	}
+	for row := 0; row < rows; row++ {
+		for column := 0; column < columns; column++ {
+			grid.Cells = append(grid.Cells, GridCell{
+				Row:       row,
+				Column:    column,
+				Latitude:  gridCoordinate(r.South, row, r.Resolution),
+				Longitude: gridCoordinate(r.West, column, r.Resolution),
+			})
+		}
+	}
+	return grid
+}
+
+//gridSteps is how many points fit from from to to, both included when to falls on the grid
+func gridSteps(from, to, resolution float64) float64 {
+	return math.Floor((to-from)/resolution+gridEpsilon) + 1
+}
+
+//gridCoordinate is rounded to a micro degree so that the same point of two overlapping grids is the same
+//cache entry, rather than two that differ in the last bits
+func gridCoordinate(origin float64, step int, resolution float64) float64 {
+	return math.Round((origin+float64(step)*resolution)*1e6) / 1e6
+}
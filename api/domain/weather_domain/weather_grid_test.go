@@ -0,0 +1,24 @@
+package weather_domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewGrid(t *testing.T) {
+	grid := NewGrid(GridRequest{South: 0.7, West: 10, North: 1, East: 10.25, Resolution: 0.1})
+	//The north edge falls on the grid despite 0.7 + 3 * 0.1 not being exactly 1, the east one does not
+	assert.EqualValues(t, 4, grid.Rows)
+	assert.EqualValues(t, 3, grid.Columns)
+	assert.Len(t, grid.Cells, 12)
+	assert.EqualValues(t, GridCell{Row: 0, Column: 0, Latitude: 0.7, Longitude: 10}, grid.Cells[0])
+	assert.EqualValues(t, GridCell{Row: 0, Column: 2, Latitude: 0.7, Longitude: 10.2}, grid.Cells[2])
+	assert.EqualValues(t, GridCell{Row: 3, Column: 2, Latitude: 1, Longitude: 10.2}, grid.Cells[11])
+}
+
+func TestNewGridSinglePoint(t *testing.T) {
+	grid := NewGrid(GridRequest{South: 44.36, West: -71.05, North: 44.36, East: -71.05, Resolution: 1})
+	assert.EqualValues(t, 1, grid.Rows*grid.Columns)
+	assert.EqualValues(t, 44.36, grid.Cells[0].Latitude)
+}
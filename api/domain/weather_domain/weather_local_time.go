@@ -0,0 +1,29 @@
+package weather_domain
+
+import "time"
+
+//LocalTimeInfo is when the current conditions were observed, in UTC and in the time zone of the location
+type LocalTimeInfo struct {
+	ObservedUtc   time.Time `json:"observedUtc" xml:"observedUtc"`
+	ObservedLocal time.Time `json:"observedLocal" xml:"observedLocal"`
+	//UtcOffset is the offset of the local time in seconds east of UTC, daylight saving time included
+	UtcOffset    int    `json:"utcOffset" xml:"utcOffset"`
+	Abbreviation string `json:"abbreviation" xml:"abbreviation"`
+	Dst          bool   `json:"dst" xml:"dst"`
+	//TimeZoneDerived tells that the provider sent no valid time zone, the one of the response was looked up from
+	//the coordinates
+	TimeZoneDerived bool `json:"timeZoneDerived,omitempty" xml:"timeZoneDerived,omitempty"`
+}
+
+//NewLocalTimeInfo places observed in location
+func NewLocalTimeInfo(observed time.Time, location *time.Location) LocalTimeInfo {
+	local := observed.In(location)
+	abbreviation, offset := local.Zone()
+	return LocalTimeInfo{
+		ObservedUtc:   observed.UTC(),
+		ObservedLocal: local,
+		UtcOffset:     offset,
+		Abbreviation:  abbreviation,
+		Dst:           local.IsDST(),
+	}
+}
@@ -0,0 +1,27 @@
+package weather_domain
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLocalTimeInfo(t *testing.T) {
+	location, err := time.LoadLocation("Europe/London")
+	assert.Nil(t, err)
+
+	//The clocks of London went forward at 01:00 UTC on 2024-03-31
+	winter := NewLocalTimeInfo(time.Date(2024, 3, 31, 0, 59, 0, 0, time.UTC), location)
+	assert.EqualValues(t, 0, winter.UtcOffset)
+	assert.EqualValues(t, "GMT", winter.Abbreviation)
+	assert.False(t, winter.Dst)
+
+	summer := NewLocalTimeInfo(time.Date(2024, 3, 31, 1, 0, 0, 0, time.UTC), location)
+	assert.EqualValues(t, 3600, summer.UtcOffset)
+	assert.EqualValues(t, "BST", summer.Abbreviation)
+	assert.True(t, summer.Dst)
+	assert.EqualValues(t, "2024-03-31T01:00:00Z", summer.ObservedUtc.Format(time.RFC3339))
+	assert.EqualValues(t, "2024-03-31T02:00:00+01:00", summer.ObservedLocal.Format(time.RFC3339))
+}
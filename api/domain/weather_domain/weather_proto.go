@@ -0,0 +1,526 @@
+package weather_domain
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/proto"
+)
+
+//The messages below mirror weather.proto. They are kept apart from Weather and WeatherError
+//because protobuf needs pointer sub-messages, which would change the JSON and XML shapes.
+
+type WeatherMessage struct {
+	Latitude   float64                `protobuf:"fixed64,1,opt,name=latitude,proto3"`
+	Longitude  float64                `protobuf:"fixed64,2,opt,name=longitude,proto3"`
+	TimeZone   string                 `protobuf:"bytes,3,opt,name=timezone,proto3"`
+	Currently  *CurrentlyInfoMessage  `protobuf:"bytes,4,opt,name=currently,proto3"`
+	Comfort    *ComfortInfoMessage    `protobuf:"bytes,5,opt,name=comfort,proto3"`
+	Provider   string                 `protobuf:"bytes,6,opt,name=provider,proto3"`
+	Ensemble   *EnsembleInfoMessage   `protobuf:"bytes,7,opt,name=ensemble,proto3"`
+	Hourly     []*HourlyInfoMessage   `protobuf:"bytes,8,rep,name=hourly,proto3"`
+	AirQuality *AirQualityInfoMessage `protobuf:"bytes,9,opt,name=air_quality,json=airQuality,proto3"`
+	Astronomy  *AstronomyInfoMessage  `protobuf:"bytes,10,opt,name=astronomy,proto3"`
+	LocalTime  *LocalTimeInfoMessage  `protobuf:"bytes,11,opt,name=local_time,json=localTime,proto3"`
+	Flags      *FlagsInfoMessage      `protobuf:"bytes,12,opt,name=flags,proto3"`
+}
+
+type CurrentlyInfoMessage struct {
+	Temperature float64  `protobuf:"fixed64,1,opt,name=temperature,proto3"`
+	Summary     string   `protobuf:"bytes,2,opt,name=summary,proto3"`
+	DewPoint    float64  `protobuf:"fixed64,3,opt,name=dew_point,json=dewPoint,proto3"`
+	Pressure    float64  `protobuf:"fixed64,4,opt,name=pressure,proto3"`
+	Humidity    float64  `protobuf:"fixed64,5,opt,name=humidity,proto3"`
+	WindSpeed   float64  `protobuf:"fixed64,6,opt,name=wind_speed,json=windSpeed,proto3"`
+	WindGust    float64  `protobuf:"fixed64,7,opt,name=wind_gust,json=windGust,proto3"`
+	Time        int64    `protobuf:"varint,8,opt,name=time,proto3"`
+	Unavailable []string `protobuf:"bytes,9,rep,name=unavailable,proto3"`
+}
+
+type HourlyInfoMessage struct {
+	Time              int64   `protobuf:"varint,1,opt,name=time,proto3"`
+	Temperature       float64 `protobuf:"fixed64,2,opt,name=temperature,proto3"`
+	Summary           string  `protobuf:"bytes,3,opt,name=summary,proto3"`
+	DewPoint          float64 `protobuf:"fixed64,4,opt,name=dew_point,json=dewPoint,proto3"`
+	Pressure          float64 `protobuf:"fixed64,5,opt,name=pressure,proto3"`
+	Humidity          float64 `protobuf:"fixed64,6,opt,name=humidity,proto3"`
+	WindSpeed         float64 `protobuf:"fixed64,7,opt,name=wind_speed,json=windSpeed,proto3"`
+	WindGust          float64 `protobuf:"fixed64,8,opt,name=wind_gust,json=windGust,proto3"`
+	PrecipProbability float64 `protobuf:"fixed64,9,opt,name=precip_probability,json=precipProbability,proto3"`
+}
+
+type FlagsInfoMessage struct {
+	Units string `protobuf:"bytes,1,opt,name=units,proto3"`
+}
+
+type ComfortInfoMessage struct {
+	ApparentTemperature float64 `protobuf:"fixed64,1,opt,name=apparent_temperature,json=apparentTemperature,proto3"`
+	HeatIndex           float64 `protobuf:"fixed64,2,opt,name=heat_index,json=heatIndex,proto3"`
+	WindChill           float64 `protobuf:"fixed64,3,opt,name=wind_chill,json=windChill,proto3"`
+	Humidex             float64 `protobuf:"fixed64,4,opt,name=humidex,proto3"`
+	Category            string  `protobuf:"bytes,5,opt,name=category,proto3"`
+}
+
+type LocalTimeInfoMessage struct {
+	ObservedUtc     int64  `protobuf:"varint,1,opt,name=observed_utc,json=observedUtc,proto3"`
+	ObservedLocal   string `protobuf:"bytes,2,opt,name=observed_local,json=observedLocal,proto3"`
+	UtcOffset       int32  `protobuf:"varint,3,opt,name=utc_offset,json=utcOffset,proto3"`
+	Abbreviation    string `protobuf:"bytes,4,opt,name=abbreviation,proto3"`
+	Dst             bool   `protobuf:"varint,5,opt,name=dst,proto3"`
+	TimeZoneDerived bool   `protobuf:"varint,6,opt,name=time_zone_derived,json=timeZoneDerived,proto3"`
+}
+
+type AstronomyInfoMessage struct {
+	Sunrise              int64                `protobuf:"varint,1,opt,name=sunrise,proto3"`
+	Sunset               int64                `protobuf:"varint,2,opt,name=sunset,proto3"`
+	SolarNoon            int64                `protobuf:"varint,3,opt,name=solar_noon,json=solarNoon,proto3"`
+	DayLength            int64                `protobuf:"varint,4,opt,name=day_length,json=dayLength,proto3"`
+	CivilTwilight        *TwilightInfoMessage `protobuf:"bytes,5,opt,name=civil_twilight,json=civilTwilight,proto3"`
+	NauticalTwilight     *TwilightInfoMessage `protobuf:"bytes,6,opt,name=nautical_twilight,json=nauticalTwilight,proto3"`
+	AstronomicalTwilight *TwilightInfoMessage `protobuf:"bytes,7,opt,name=astronomical_twilight,json=astronomicalTwilight,proto3"`
+	Moon                 *MoonInfoMessage     `protobuf:"bytes,8,opt,name=moon,proto3"`
+}
+
+type TwilightInfoMessage struct {
+	Dawn int64 `protobuf:"varint,1,opt,name=dawn,proto3"`
+	Dusk int64 `protobuf:"varint,2,opt,name=dusk,proto3"`
+}
+
+type MoonInfoMessage struct {
+	Phase        float64 `protobuf:"fixed64,1,opt,name=phase,proto3"`
+	PhaseName    string  `protobuf:"bytes,2,opt,name=phase_name,json=phaseName,proto3"`
+	Illumination float64 `protobuf:"fixed64,3,opt,name=illumination,proto3"`
+}
+
+type AirQualityInfoMessage struct {
+	Time       int64                  `protobuf:"varint,1,opt,name=time,proto3"`
+	Pollutants *PollutantsInfoMessage `protobuf:"bytes,2,opt,name=pollutants,proto3"`
+	Pollen     *PollenInfoMessage     `protobuf:"bytes,3,opt,name=pollen,proto3"`
+	Aqi        *AqiInfoMessage        `protobuf:"bytes,4,opt,name=aqi,proto3"`
+	Provider   string                 `protobuf:"bytes,5,opt,name=provider,proto3"`
+}
+
+//The pollutants and pollen counts are optional fields in weather.proto, so that a value the provider does not have
+//stays apart from a zero. The proto3 tag is left out of them: the legacy struct messages only take pointer scalars
+//with the explicit presence of proto2, which goes on the wire the same way
+type PollutantsInfoMessage struct {
+	PM25 *float64 `protobuf:"fixed64,1,opt,name=pm2_5,json=pm25"`
+	PM10 *float64 `protobuf:"fixed64,2,opt,name=pm10"`
+	O3   *float64 `protobuf:"fixed64,3,opt,name=o3"`
+	NO2  *float64 `protobuf:"fixed64,4,opt,name=no2"`
+	SO2  *float64 `protobuf:"fixed64,5,opt,name=so2"`
+	CO   *float64 `protobuf:"fixed64,6,opt,name=co"`
+}
+
+type PollenInfoMessage struct {
+	Alder   *float64 `protobuf:"fixed64,1,opt,name=alder"`
+	Birch   *float64 `protobuf:"fixed64,2,opt,name=birch"`
+	Grass   *float64 `protobuf:"fixed64,3,opt,name=grass"`
+	Mugwort *float64 `protobuf:"fixed64,4,opt,name=mugwort"`
+	Olive   *float64 `protobuf:"fixed64,5,opt,name=olive"`
+	Ragweed *float64 `protobuf:"fixed64,6,opt,name=ragweed"`
+}
+
+type AqiInfoMessage struct {
+	Epa *IndexInfoMessage `protobuf:"bytes,1,opt,name=epa,proto3"`
+	Eu  *IndexInfoMessage `protobuf:"bytes,2,opt,name=eu,proto3"`
+}
+
+type IndexInfoMessage struct {
+	Value    int32  `protobuf:"varint,1,opt,name=value,proto3"`
+	Category string `protobuf:"bytes,2,opt,name=category,proto3"`
+	Dominant string `protobuf:"bytes,3,opt,name=dominant,proto3"`
+}
+
+type EnsembleInfoMessage struct {
+	Method    string                   `protobuf:"bytes,1,opt,name=method,proto3"`
+	Spread    *SpreadInfoMessage       `protobuf:"bytes,2,opt,name=spread,proto3"`
+	Providers []*EnsembleMemberMessage `protobuf:"bytes,3,rep,name=providers,proto3"`
+}
+
+type SpreadInfoMessage struct {
+	Temperature *RangeInfoMessage `protobuf:"bytes,1,opt,name=temperature,proto3"`
+	DewPoint    *RangeInfoMessage `protobuf:"bytes,2,opt,name=dew_point,json=dewPoint,proto3"`
+	Pressure    *RangeInfoMessage `protobuf:"bytes,3,opt,name=pressure,proto3"`
+	Humidity    *RangeInfoMessage `protobuf:"bytes,4,opt,name=humidity,proto3"`
+	WindSpeed   *RangeInfoMessage `protobuf:"bytes,5,opt,name=wind_speed,json=windSpeed,proto3"`
+	WindGust    *RangeInfoMessage `protobuf:"bytes,6,opt,name=wind_gust,json=windGust,proto3"`
+}
+
+type RangeInfoMessage struct {
+	Min float64 `protobuf:"fixed64,1,opt,name=min,proto3"`
+	Max float64 `protobuf:"fixed64,2,opt,name=max,proto3"`
+}
+
+type EnsembleMemberMessage struct {
+	Provider  string                `protobuf:"bytes,1,opt,name=provider,proto3"`
+	Weight    float64               `protobuf:"fixed64,2,opt,name=weight,proto3"`
+	TimeZone  string                `protobuf:"bytes,3,opt,name=timezone,proto3"`
+	Currently *CurrentlyInfoMessage `protobuf:"bytes,4,opt,name=currently,proto3"`
+	Error     *WeatherErrorMessage  `protobuf:"bytes,5,opt,name=error,proto3"`
+}
+
+type WeatherErrorMessage struct {
+	Code  int32  `protobuf:"varint,1,opt,name=code,proto3"`
+	Error string `protobuf:"bytes,2,opt,name=error,proto3"`
+}
+
+type GridMessage struct {
+	South      float64            `protobuf:"fixed64,1,opt,name=south,proto3"`
+	West       float64            `protobuf:"fixed64,2,opt,name=west,proto3"`
+	North      float64            `protobuf:"fixed64,3,opt,name=north,proto3"`
+	East       float64            `protobuf:"fixed64,4,opt,name=east,proto3"`
+	Resolution float64            `protobuf:"fixed64,5,opt,name=resolution,proto3"`
+	Rows       int32              `protobuf:"varint,6,opt,name=rows,proto3"`
+	Columns    int32              `protobuf:"varint,7,opt,name=columns,proto3"`
+	Cells      []*GridCellMessage `protobuf:"bytes,8,rep,name=cells,proto3"`
+}
+
+type GridCellMessage struct {
+	Row       int32                `protobuf:"varint,1,opt,name=row,proto3"`
+	Column    int32                `protobuf:"varint,2,opt,name=column,proto3"`
+	Latitude  float64              `protobuf:"fixed64,3,opt,name=latitude,proto3"`
+	Longitude float64              `protobuf:"fixed64,4,opt,name=longitude,proto3"`
+	Weather   *WeatherMessage      `protobuf:"bytes,5,opt,name=weather,proto3"`
+	Error     *WeatherErrorMessage `protobuf:"bytes,6,opt,name=error,proto3"`
+}
+
+type RouteMessage struct {
+	Departure int64                `protobuf:"varint,1,opt,name=departure,proto3"`
+	Arrival   int64                `protobuf:"varint,2,opt,name=arrival,proto3"`
+	Distance  float64              `protobuf:"fixed64,3,opt,name=distance,proto3"`
+	Speed     float64              `protobuf:"fixed64,4,opt,name=speed,proto3"`
+	Points    []*RoutePointMessage `protobuf:"bytes,5,rep,name=points,proto3"`
+}
+
+type RoutePointMessage struct {
+	Latitude  float64              `protobuf:"fixed64,1,opt,name=latitude,proto3"`
+	Longitude float64              `protobuf:"fixed64,2,opt,name=longitude,proto3"`
+	Distance  float64              `protobuf:"fixed64,3,opt,name=distance,proto3"`
+	Eta       int64                `protobuf:"varint,4,opt,name=eta,proto3"`
+	TimeZone  string               `protobuf:"bytes,5,opt,name=timezone,proto3"`
+	Forecast  *HourlyInfoMessage   `protobuf:"bytes,6,opt,name=forecast,proto3"`
+	Error     *WeatherErrorMessage `protobuf:"bytes,7,opt,name=error,proto3"`
+}
+
+func (m *WeatherMessage) Reset()         { *m = WeatherMessage{} }
+func (m *WeatherMessage) String() string { return proto.CompactTextString(m) }
+func (*WeatherMessage) ProtoMessage()    {}
+
+func (m *CurrentlyInfoMessage) Reset()         { *m = CurrentlyInfoMessage{} }
+func (m *CurrentlyInfoMessage) String() string { return proto.CompactTextString(m) }
+func (*CurrentlyInfoMessage) ProtoMessage()    {}
+
+func (m *HourlyInfoMessage) Reset()         { *m = HourlyInfoMessage{} }
+func (m *HourlyInfoMessage) String() string { return proto.CompactTextString(m) }
+func (*HourlyInfoMessage) ProtoMessage()    {}
+
+func (m *FlagsInfoMessage) Reset()         { *m = FlagsInfoMessage{} }
+func (m *FlagsInfoMessage) String() string { return proto.CompactTextString(m) }
+func (*FlagsInfoMessage) ProtoMessage()    {}
+
+func (m *ComfortInfoMessage) Reset()         { *m = ComfortInfoMessage{} }
+func (m *ComfortInfoMessage) String() string { return proto.CompactTextString(m) }
+func (*ComfortInfoMessage) ProtoMessage()    {}
+
+func (m *LocalTimeInfoMessage) Reset()         { *m = LocalTimeInfoMessage{} }
+func (m *LocalTimeInfoMessage) String() string { return proto.CompactTextString(m) }
+func (*LocalTimeInfoMessage) ProtoMessage()    {}
+
+func (m *AstronomyInfoMessage) Reset()         { *m = AstronomyInfoMessage{} }
+func (m *AstronomyInfoMessage) String() string { return proto.CompactTextString(m) }
+func (*AstronomyInfoMessage) ProtoMessage()    {}
+
+func (m *TwilightInfoMessage) Reset()         { *m = TwilightInfoMessage{} }
+func (m *TwilightInfoMessage) String() string { return proto.CompactTextString(m) }
+func (*TwilightInfoMessage) ProtoMessage()    {}
+
+func (m *MoonInfoMessage) Reset()         { *m = MoonInfoMessage{} }
+func (m *MoonInfoMessage) String() string { return proto.CompactTextString(m) }
+func (*MoonInfoMessage) ProtoMessage()    {}
+
+func (m *AirQualityInfoMessage) Reset()         { *m = AirQualityInfoMessage{} }
+func (m *AirQualityInfoMessage) String() string { return proto.CompactTextString(m) }
+func (*AirQualityInfoMessage) ProtoMessage()    {}
+
+func (m *PollutantsInfoMessage) Reset()         { *m = PollutantsInfoMessage{} }
+func (m *PollutantsInfoMessage) String() string { return proto.CompactTextString(m) }
+func (*PollutantsInfoMessage) ProtoMessage()    {}
+
+func (m *PollenInfoMessage) Reset()         { *m = PollenInfoMessage{} }
+func (m *PollenInfoMessage) String() string { return proto.CompactTextString(m) }
+func (*PollenInfoMessage) ProtoMessage()    {}
+
+func (m *AqiInfoMessage) Reset()         { *m = AqiInfoMessage{} }
+func (m *AqiInfoMessage) String() string { return proto.CompactTextString(m) }
+func (*AqiInfoMessage) ProtoMessage()    {}
+
+func (m *IndexInfoMessage) Reset()         { *m = IndexInfoMessage{} }
+func (m *IndexInfoMessage) String() string { return proto.CompactTextString(m) }
+func (*IndexInfoMessage) ProtoMessage()    {}
+
+func (m *EnsembleInfoMessage) Reset()         { *m = EnsembleInfoMessage{} }
+func (m *EnsembleInfoMessage) String() string { return proto.CompactTextString(m) }
+func (*EnsembleInfoMessage) ProtoMessage()    {}
+
+func (m *SpreadInfoMessage) Reset()         { *m = SpreadInfoMessage{} }
+func (m *SpreadInfoMessage) String() string { return proto.CompactTextString(m) }
+func (*SpreadInfoMessage) ProtoMessage()    {}
+
+func (m *RangeInfoMessage) Reset()         { *m = RangeInfoMessage{} }
+func (m *RangeInfoMessage) String() string { return proto.CompactTextString(m) }
+func (*RangeInfoMessage) ProtoMessage()    {}
+
+func (m *EnsembleMemberMessage) Reset()         { *m = EnsembleMemberMessage{} }
+func (m *EnsembleMemberMessage) String() string { return proto.CompactTextString(m) }
+func (*EnsembleMemberMessage) ProtoMessage()    {}
+
+func (m *WeatherErrorMessage) Reset()         { *m = WeatherErrorMessage{} }
+func (m *WeatherErrorMessage) String() string { return proto.CompactTextString(m) }
+func (*WeatherErrorMessage) ProtoMessage()    {}
+
+func (m *GridMessage) Reset()         { *m = GridMessage{} }
+func (m *GridMessage) String() string { return proto.CompactTextString(m) }
+func (*GridMessage) ProtoMessage()    {}
+
+func (m *GridCellMessage) Reset()         { *m = GridCellMessage{} }
+func (m *GridCellMessage) String() string { return proto.CompactTextString(m) }
+func (*GridCellMessage) ProtoMessage()    {}
+
+func (m *RouteMessage) Reset()         { *m = RouteMessage{} }
+func (m *RouteMessage) String() string { return proto.CompactTextString(m) }
+func (*RouteMessage) ProtoMessage()    {}
+
+func (m *RoutePointMessage) Reset()         { *m = RoutePointMessage{} }
+func (m *RoutePointMessage) String() string { return proto.CompactTextString(m) }
+func (*RoutePointMessage) ProtoMessage()    {}
+
+func (w *Weather) Proto() *WeatherMessage {
+	message := &WeatherMessage{
+		Latitude:  w.Latitude,
+		Longitude: w.Longitude,
+		TimeZone:  w.TimeZone,
+		Provider:  w.Provider,
+		Currently: w.Currently.Proto(),
+	}
+	if w.Comfort != nil {
+		message.Comfort = &ComfortInfoMessage{
+			ApparentTemperature: w.Comfort.ApparentTemperature,
+			HeatIndex:           w.Comfort.HeatIndex,
+			WindChill:           w.Comfort.WindChill,
+			Humidex:             w.Comfort.Humidex,
+			Category:            w.Comfort.Category,
+		}
+	}
+	if w.Flags != nil {
+		message.Flags = &FlagsInfoMessage{Units: w.Flags.Units}
+	}
+	if w.Ensemble != nil {
+		message.Ensemble = w.Ensemble.Proto()
+	}
+	if w.AirQuality != nil {
+		message.AirQuality = w.AirQuality.Proto()
+	}
+	if w.LocalTime != nil {
+		message.LocalTime = w.LocalTime.Proto()
+	}
+	if w.Astronomy != nil {
+		message.Astronomy = w.Astronomy.Proto()
+	}
+	for i := range w.Hourly {
+		message.Hourly = append(message.Hourly, w.Hourly[i].Proto())
+	}
+	return message
+}
+
+func (h *HourlyInfo) Proto() *HourlyInfoMessage {
+	return &HourlyInfoMessage{
+		Time:              h.Time,
+		Temperature:       h.Temperature,
+		Summary:           h.Summary,
+		DewPoint:          h.DewPoint,
+		Pressure:          h.Pressure,
+		Humidity:          h.Humidity,
+		WindSpeed:         h.WindSpeed,
+		WindGust:          h.WindGust,
+		PrecipProbability: h.PrecipProbability,
+	}
+}
+
+func (c *CurrentlyInfo) Proto() *CurrentlyInfoMessage {
+	return &CurrentlyInfoMessage{
+		Temperature: c.Temperature,
+		Summary:     c.Summary,
+		DewPoint:    c.DewPoint,
+		Pressure:    c.Pressure,
+		Humidity:    c.Humidity,
+		WindSpeed:   c.WindSpeed,
+		WindGust:    c.WindGust,
+		Time:        c.Time,
+		Unavailable: c.Unavailable,
+	}
+}
+
+//Proto sends the local time as RFC 3339, which keeps its offset, and the UTC one in unix seconds like the other times
+func (l *LocalTimeInfo) Proto() *LocalTimeInfoMessage {
+	return &LocalTimeInfoMessage{
+		ObservedUtc:     l.ObservedUtc.Unix(),
+		ObservedLocal:   l.ObservedLocal.Format(time.RFC3339),
+		UtcOffset:       int32(l.UtcOffset),
+		Abbreviation:    l.Abbreviation,
+		Dst:             l.Dst,
+		TimeZoneDerived: l.TimeZoneDerived,
+	}
+}
+
+func (a *AstronomyInfo) Proto() *AstronomyInfoMessage {
+	return &AstronomyInfoMessage{
+		Sunrise:              a.Sunrise,
+		Sunset:               a.Sunset,
+		SolarNoon:            a.SolarNoon,
+		DayLength:            a.DayLength,
+		CivilTwilight:        a.CivilTwilight.Proto(),
+		NauticalTwilight:     a.NauticalTwilight.Proto(),
+		AstronomicalTwilight: a.AstronomicalTwilight.Proto(),
+		Moon: &MoonInfoMessage{
+			Phase:        a.Moon.Phase,
+			PhaseName:    a.Moon.PhaseName,
+			Illumination: a.Moon.Illumination,
+		},
+	}
+}
+
+//Proto is nil for a nil twilight, one the sun does not go through that day
+func (t *TwilightInfo) Proto() *TwilightInfoMessage {
+	if t == nil {
+		return nil
+	}
+	return &TwilightInfoMessage{Dawn: t.Dawn, Dusk: t.Dusk}
+}
+
+func (a *AirQualityInfo) Proto() *AirQualityInfoMessage {
+	message := &AirQualityInfoMessage{
+		Time: a.Time,
+		Pollutants: &PollutantsInfoMessage{
+			PM25: a.Pollutants.PM25,
+			PM10: a.Pollutants.PM10,
+			O3:   a.Pollutants.O3,
+			NO2:  a.Pollutants.NO2,
+			SO2:  a.Pollutants.SO2,
+			CO:   a.Pollutants.CO,
+		},
+		Aqi: &AqiInfoMessage{
+			Epa: a.Aqi.Epa.Proto(),
+			Eu:  a.Aqi.Eu.Proto(),
+		},
+		Provider: a.Provider,
+	}
+	if a.Pollen != nil {
+		message.Pollen = &PollenInfoMessage{
+			Alder:   a.Pollen.Alder,
+			Birch:   a.Pollen.Birch,
+			Grass:   a.Pollen.Grass,
+			Mugwort: a.Pollen.Mugwort,
+			Olive:   a.Pollen.Olive,
+			Ragweed: a.Pollen.Ragweed,
+		}
+	}
+	return message
+}
+
+//Proto is nil for a nil index, an index no pollutant it uses was measured for
+func (i *IndexInfo) Proto() *IndexInfoMessage {
+	if i == nil {
+		return nil
+	}
+	return &IndexInfoMessage{
+		Value:    int32(i.Value),
+		Category: i.Category,
+		Dominant: i.Dominant,
+	}
+}
+
+func (e *EnsembleInfo) Proto() *EnsembleInfoMessage {
+	message := &EnsembleInfoMessage{
+		Method: e.Method,
+		Spread: &SpreadInfoMessage{
+			Temperature: &RangeInfoMessage{Min: e.Spread.Temperature.Min, Max: e.Spread.Temperature.Max},
+			DewPoint:    &RangeInfoMessage{Min: e.Spread.DewPoint.Min, Max: e.Spread.DewPoint.Max},
+			Pressure:    &RangeInfoMessage{Min: e.Spread.Pressure.Min, Max: e.Spread.Pressure.Max},
+			Humidity:    &RangeInfoMessage{Min: e.Spread.Humidity.Min, Max: e.Spread.Humidity.Max},
+			WindSpeed:   &RangeInfoMessage{Min: e.Spread.WindSpeed.Min, Max: e.Spread.WindSpeed.Max},
+			WindGust:    &RangeInfoMessage{Min: e.Spread.WindGust.Min, Max: e.Spread.WindGust.Max},
+		},
+	}
+	for _, member := range e.Providers {
+		memberMessage := &EnsembleMemberMessage{Provider: member.Provider, Weight: member.Weight, TimeZone: member.TimeZone}
+		if member.Currently != nil {
+			memberMessage.Currently = member.Currently.Proto()
+		}
+		if member.Error != nil {
+			memberMessage.Error = member.Error.Proto()
+		}
+		message.Providers = append(message.Providers, memberMessage)
+	}
+	return message
+}
+
+func (w *WeatherError) Proto() *WeatherErrorMessage {
+	return &WeatherErrorMessage{
+		Code:  int32(w.Code),
+		Error: w.ErrorMessage,
+	}
+}
+
+func (g *Grid) Proto() *GridMessage {
+	message := &GridMessage{
+		South:      g.South,
+		West:       g.West,
+		North:      g.North,
+		East:       g.East,
+		Resolution: g.Resolution,
+		Rows:       int32(g.Rows),
+		Columns:    int32(g.Columns),
+	}
+	for _, cell := range g.Cells {
+		cellMessage := &GridCellMessage{
+			Row:       int32(cell.Row),
+			Column:    int32(cell.Column),
+			Latitude:  cell.Latitude,
+			Longitude: cell.Longitude,
+		}
+		if cell.Weather != nil {
+			cellMessage.Weather = cell.Weather.Proto()
+		}
+		if cell.Error != nil {
+			cellMessage.Error = cell.Error.Proto()
+		}
+		message.Cells = append(message.Cells, cellMessage)
+	}
+	return message
+}
+
+func (r *Route) Proto() *RouteMessage {
+	message := &RouteMessage{
+		Departure: r.Departure.Unix(),
+		Arrival:   r.Arrival.Unix(),
+		Distance:  r.Distance,
+		Speed:     r.Speed,
+	}
+	for _, point := range r.Points {
+		pointMessage := &RoutePointMessage{
+			Latitude:  point.Latitude,
+			Longitude: point.Longitude,
+			Distance:  point.Distance,
+			Eta:       point.Eta.Unix(),
+			TimeZone:  point.TimeZone,
+		}
+		if point.Forecast != nil {
+			pointMessage.Forecast = point.Forecast.Proto()
+		}
+		if point.Error != nil {
+			pointMessage.Error = point.Error.Proto()
+		}
+		message.Points = append(message.Points, pointMessage)
+	}
+	return message
+}
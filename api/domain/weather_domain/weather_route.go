@@ -0,0 +1,56 @@
+package weather_domain
+
+import (
+	"interface-testing/api/domain/client_domain"
+	"interface-testing/api/geo"
+	"time"
+)
+
+//RouteRequest asks for the weather along Path for a vehicle leaving at Departure and driving at Speed km/h,
+//sampled every Interval kilometers. Request carries the api key, units and language, its location is ignored
+type RouteRequest struct {
+	Request   WeatherRequest
+	Path      []geo.Point
+	Departure time.Time
+	Speed     float64
+	Interval  float64
+	//Client is charged a request for every sample of the route, nil when client keys are off
+	Client *client_domain.Client
+}
+
+//Route is the forecast along a route, at the time the vehicle is expected at each point. Distances are in kilometers
+type Route struct {
+	Departure time.Time    `json:"departure" xml:"departure"`
+	Arrival   time.Time    `json:"arrival" xml:"arrival"`
+	Distance  float64      `json:"distance" xml:"distance"`
+	Speed     float64      `json:"speed" xml:"speed"`
+	Points    []RoutePoint `json:"points" xml:"points>point"`
+}
+
+//RoutePoint is one sample of the route, either Forecast or Error is set
+type RoutePoint struct {
+	Latitude  float64       `json:"latitude" xml:"latitude"`
+	Longitude float64       `json:"longitude" xml:"longitude"`
+	Distance  float64       `json:"distance" xml:"distance"`
+	Eta       time.Time     `json:"eta" xml:"eta"`
+	TimeZone  string        `json:"timezone,omitempty" xml:"timezone,omitempty"`
+	Forecast  *HourlyInfo   `json:"forecast,omitempty" xml:"forecast,omitempty"`
+	Error     *WeatherError `json:"error,omitempty" xml:"error,omitempty"`
+	//Cache is how the weather of the point was served, it is not sent
+	Cache *CacheInfo `json:"-" xml:"-"`
+}
+
+//ForecastAt returns a copy of the hour of hours that at falls in, false when at is before or after the forecast
+func ForecastAt(hours []HourlyInfo, at time.Time) (*HourlyInfo, bool) {
+	unix := at.Unix()
+	for i := len(hours) - 1; i >= 0; i-- {
+		if hours[i].Time <= unix {
+			if unix-hours[i].Time >= int64(time.Hour/time.Second) {
+				return nil, false
+			}
+			hour := hours[i]
+			return &hour, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,29 @@
+package weather_domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForecastAt(t *testing.T) {
+	hours := []HourlyInfo{{Time: 1583064000, Temperature: 1}, {Time: 1583067600, Temperature: 2}}
+
+	forecast, ok := ForecastAt(hours, time.Unix(1583064000, 0))
+	assert.True(t, ok)
+	assert.EqualValues(t, 1, forecast.Temperature)
+	forecast, ok = ForecastAt(hours, time.Unix(1583067600+3599, 0))
+	assert.True(t, ok)
+	assert.EqualValues(t, 2, forecast.Temperature)
+	//The forecast is a copy, the hours may be shared with the cache
+	forecast.Temperature = 3
+	assert.EqualValues(t, 2, hours[1].Temperature)
+
+	_, ok = ForecastAt(hours, time.Unix(1583064000-1, 0))
+	assert.False(t, ok)
+	_, ok = ForecastAt(hours, time.Unix(1583067600+3600, 0))
+	assert.False(t, ok)
+	_, ok = ForecastAt(nil, time.Unix(1583064000, 0))
+	assert.False(t, ok)
+}
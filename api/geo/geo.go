@@ -0,0 +1,169 @@
+package geo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+//earthRadius is the mean radius of the earth in kilometers
+const earthRadius = 6371.0088
+
+//Point is a location in degrees
+type Point struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+//Sample is a point along a path, with the distance travelled to reach it in kilometers
+type Sample struct {
+	Point
+	Distance float64
+}
+
+//DecodePolyline decodes an encoded polyline, as produced by the Google and OSRM routing apis.
+//precision is the number of decimals it was encoded with, 5 for most apis and 6 for OSRM and Valhalla
+func DecodePolyline(encoded string, precision int) ([]Point, error) {
+	factor := math.Pow10(precision)
+	var points []Point
+	var latitude, longitude int64
+	for i := 0; i < len(encoded); {
+		var deltas [2]int64
+		for j := range deltas {
+			var result int64
+			var shift uint
+			for {
+				if i >= len(encoded) {
+					return nil, errors.New("polyline ends in the middle of a point")
+				}
+				b := int64(encoded[i]) - 63
+				i++
+				if b < 0 || b > 63 {
+					return nil, fmt.Errorf("invalid polyline character %q", encoded[i-1])
+				}
+				result |= (b & 0x1f) << shift
+				shift += 5
+				if b < 0x20 {
+					break
+				}
+				if shift > 60 {
+					return nil, errors.New("polyline value overflows")
+				}
+			}
+			if result&1 != 0 {
+				deltas[j] = ^(result >> 1)
+			} else {
+				deltas[j] = result >> 1
+			}
+		}
+		latitude += deltas[0]
+		longitude += deltas[1]
+		points = append(points, Point{Latitude: float64(latitude) / factor, Longitude: float64(longitude) / factor})
+	}
+	return points, nil
+}
+
+//EncodePolyline is the reverse of DecodePolyline
+func EncodePolyline(points []Point, precision int) string {
+	factor := math.Pow10(precision)
+	var encoded []byte
+	var latitude, longitude int64
+	for _, point := range points {
+		nextLatitude, nextLongitude := int64(math.Round(point.Latitude*factor)), int64(math.Round(point.Longitude*factor))
+		for _, delta := range []int64{nextLatitude - latitude, nextLongitude - longitude} {
+			value := delta << 1
+			if delta < 0 {
+				value = ^value
+			}
+			for value >= 0x20 {
+				encoded = append(encoded, byte((0x20|(value&0x1f))+63))
+				value >>= 5
+			}
+			encoded = append(encoded, byte(value+63))
+		}
+		latitude, longitude = nextLatitude, nextLongitude
+	}
+	return string(encoded)
+}
+
+//DecodeLineString reads a GeoJSON LineString geometry, or a Feature holding one. GeoJSON puts the longitude first
+func DecodeLineString(geometry json.RawMessage) ([]Point, error) {
+	var object struct {
+		Type        string          `json:"type"`
+		Coordinates [][]float64     `json:"coordinates"`
+		Geometry    json.RawMessage `json:"geometry"`
+	}
+	if err := json.Unmarshal(geometry, &object); err != nil {
+		return nil, errors.New("invalid GeoJSON geometry")
+	}
+	if object.Type == "Feature" && object.Geometry != nil {
+		return DecodeLineString(object.Geometry)
+	}
+	if object.Type != "LineString" {
+		return nil, fmt.Errorf("expected a GeoJSON LineString, got %q", object.Type)
+	}
+	points := make([]Point, 0, len(object.Coordinates))
+	for _, coordinates := range object.Coordinates {
+		if len(coordinates) < 2 {
+			return nil, errors.New("every GeoJSON position needs a longitude and a latitude")
+		}
+		points = append(points, Point{Latitude: coordinates[1], Longitude: coordinates[0]})
+	}
+	return points, nil
+}
+
+//Distance is the great circle distance between a and b in kilometers
+func Distance(a, b Point) float64 {
+	latitudeA, latitudeB := a.Latitude*math.Pi/180, b.Latitude*math.Pi/180
+	dLatitude, dLongitude := latitudeB-latitudeA, (b.Longitude-a.Longitude)*math.Pi/180
+	h := math.Pow(math.Sin(dLatitude/2), 2) + math.Cos(latitudeA)*math.Cos(latitudeB)*math.Pow(math.Sin(dLongitude/2), 2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+//Length is the length of path in kilometers
+func Length(path []Point) float64 {
+	length := 0.0
+	for i := 1; i < len(path); i++ {
+		length += Distance(path[i-1], path[i])
+	}
+	return length
+}
+
+//SamplePath returns a point every interval kilometers along path, starting with its first point and always ending
+//with its last one. Points between two vertices are interpolated linearly, which is close enough at road scale
+func SamplePath(path []Point, interval float64) []Sample {
+	if len(path) == 0 {
+		return nil
+	}
+	samples := []Sample{{Point: path[0]}}
+	travelled, next := 0.0, interval
+	for i := 1; i < len(path); i++ {
+		segment := Distance(path[i-1], path[i])
+		for segment > 0 && next < travelled+segment {
+			fraction := (next - travelled) / segment
+			samples = append(samples, Sample{Point: interpolate(path[i-1], path[i], fraction), Distance: next})
+			next += interval
+		}
+		travelled += segment
+	}
+	if last := samples[len(samples)-1]; len(path) > 1 && travelled-last.Distance > 1e-9 {
+		samples = append(samples, Sample{Point: path[len(path)-1], Distance: travelled})
+	}
+	return samples
+}
+
+//interpolate goes the short way in longitude, like Distance, so a segment across the antimeridian
+//stays in the Pacific instead of sweeping around the globe
+func interpolate(a, b Point, fraction float64) Point {
+	dLongitude := math.Mod(b.Longitude-a.Longitude+540, 360) - 180
+	return Point{
+		Latitude:  a.Latitude + (b.Latitude-a.Latitude)*fraction,
+		Longitude: normalizeLongitude(a.Longitude + dLongitude*fraction),
+	}
+}
+
+//normalizeLongitude brings longitude back into [-180, 180)
+func normalizeLongitude(longitude float64) float64 {
+	return math.Mod(math.Mod(longitude+180, 360)+360, 360) - 180
+}
@@ -0,0 +1,85 @@
+package geo
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//The example of the polyline algorithm documentation
+var documented = []Point{{38.5, -120.2}, {40.7, -120.95}, {43.252, -126.453}}
+
+func TestDecodePolyline(t *testing.T) {
+	points, err := DecodePolyline("_p~iF~ps|U_ulLnnqC_mqNvxq`@", 5)
+	assert.Nil(t, err)
+	assert.EqualValues(t, documented, points)
+	assert.EqualValues(t, "_p~iF~ps|U_ulLnnqC_mqNvxq`@", EncodePolyline(points, 5))
+
+	//Six decimals round trip as well
+	points, err = DecodePolyline(EncodePolyline([]Point{{44.360123, -71.058901}}, 6), 6)
+	assert.Nil(t, err)
+	assert.EqualValues(t, []Point{{44.360123, -71.058901}}, points)
+}
+
+func TestDecodePolylineInvalid(t *testing.T) {
+	_, err := DecodePolyline("_p~iF~ps|U_ulL", 5)
+	assert.EqualValues(t, "polyline ends in the middle of a point", err.Error())
+	_, err = DecodePolyline("_p~iF ps|U", 5)
+	assert.EqualValues(t, `invalid polyline character ' '`, err.Error())
+}
+
+func TestDecodeLineString(t *testing.T) {
+	points, err := DecodeLineString(json.RawMessage(`{"type": "LineString", "coordinates": [[-120.2, 38.5], [-120.95, 40.7, 120]]}`))
+	assert.Nil(t, err)
+	assert.EqualValues(t, documented[:2], points)
+
+	points, err = DecodeLineString(json.RawMessage(`{"type": "Feature", "properties": {}, "geometry": {"type": "LineString", "coordinates": [[-120.2, 38.5]]}}`))
+	assert.Nil(t, err)
+	assert.EqualValues(t, documented[:1], points)
+
+	_, err = DecodeLineString(json.RawMessage(`{"type": "Point", "coordinates": [-120.2, 38.5]}`))
+	assert.NotNil(t, err)
+	_, err = DecodeLineString(json.RawMessage(`{"type": "LineString", "coordinates": [[-120.2]]}`))
+	assert.EqualValues(t, "every GeoJSON position needs a longitude and a latitude", err.Error())
+}
+
+func TestDistance(t *testing.T) {
+	//Paris to London is about 344 km
+	assert.InDelta(t, 343.9, Distance(Point{48.8566, 2.3522}, Point{51.5074, -0.1278}), 1)
+	assert.EqualValues(t, 0, Distance(documented[0], documented[0]))
+}
+
+func TestSamplePath(t *testing.T) {
+	//One degree of longitude on the equator is about 111.2 km
+	path := []Point{{0, 0}, {0, 1}, {0, 2.5}}
+	samples := SamplePath(path, 100)
+	assert.Len(t, samples, 4)
+	assert.EqualValues(t, Sample{Point: Point{0, 0}}, samples[0])
+	assert.EqualValues(t, 100, samples[1].Distance)
+	assert.InDelta(t, 0.8993, samples[1].Longitude, 1e-3)
+	assert.EqualValues(t, 200, samples[2].Distance)
+	assert.InDelta(t, 278, samples[3].Distance, 0.5)
+	//The last point of the path is always sampled
+	samples = SamplePath(path, 250)
+	assert.Len(t, samples, 3)
+	assert.EqualValues(t, Point{0, 2.5}, samples[2].Point)
+	assert.InDelta(t, Length(path), samples[2].Distance, 1e-9)
+
+	assert.Len(t, SamplePath(path[:1], 100), 1)
+	assert.Nil(t, SamplePath(nil, 100))
+}
+
+func TestSamplePathAcrossAntimeridian(t *testing.T) {
+	//Two degrees of longitude apart the short way, 358 the long way
+	path := []Point{{0, 179}, {0, -179}}
+	assert.InDelta(t, 222.4, Length(path), 0.5)
+	samples := SamplePath(path, 100)
+	assert.Len(t, samples, 4)
+	assert.InDelta(t, 179.8993, samples[1].Longitude, 1e-3)
+	assert.InDelta(t, -179.2014, samples[2].Longitude, 1e-3)
+	assert.EqualValues(t, Point{0, -179}, samples[3].Point)
+
+	samples = SamplePath(path, 150)
+	assert.InDelta(t, -179.6511, samples[1].Longitude, 1e-3)
+}
@@ -0,0 +1,246 @@
+package i18n
+
+//catalogs holds the translations keyed by language and then by the lower case English text.
+//Summaries come from the upstream, the messages from our own WeatherErrors.
+var catalogs = map[string]map[string]string{
+	"es": {
+		"clear":                         "Despejado",
+		"mostly clear":                  "Mayormente despejado",
+		"partly cloudy":                 "Parcialmente nublado",
+		"mostly cloudy":                 "Mayormente nublado",
+		"cloudy":                        "Nublado",
+		"overcast":                      "Cubierto",
+		"foggy":                         "Niebla",
+		"drizzle":                       "Llovizna",
+		"freezing drizzle":              "Llovizna helada",
+		"light rain":                    "Lluvia ligera",
+		"possible light rain":           "Posible lluvia ligera",
+		"rain":                          "Lluvia",
+		"heavy rain":                    "Lluvia fuerte",
+		"freezing rain":                 "Lluvia helada",
+		"light snow":                    "Nevada ligera",
+		"snow":                          "Nieve",
+		"heavy snow":                    "Nevada fuerte",
+		"sleet":                         "Aguanieve",
+		"breezy":                        "Brisa",
+		"windy":                         "Ventoso",
+		"humid":                         "Húmedo",
+		"dry":                           "Seco",
+		"thunderstorm":                  "Tormenta",
+		"permission denied":             "Permiso denegado",
+		"the given location is invalid": "La ubicación indicada no es válida",
+		"poorly formatted request":      "Solicitud mal formada",
+		"invalid json response body":    "Cuerpo de respuesta JSON no válido",
+		"error unmarshaling weather fetch response":              "Error al leer la respuesta del tiempo",
+		"apikey is required":                                     "Se requiere apiKey",
+		"at least one location is required":                      "Se requiere al menos una ubicación",
+		"invalid subscription message":                           "Mensaje de suscripción no válido",
+		"invalid day %s, expected %s":                            "Día %s no válido, se esperaba %s",
+		"format must be json or csv":                             "El formato debe ser json o csv",
+		"invalid json body":                                      "Cuerpo JSON no válido",
+		"bbox must be west,south,east,north":                     "bbox debe ser west,south,east,north",
+		"invalid bbox %q":                                        "bbox %q no válido",
+		"resolution must be a positive number of degrees":        "La resolución debe ser un número positivo de grados",
+		"invalid location %q":                                    "Ubicación %q no válida",
+		"send either a polyline or a geometry, not both":         "Envíe una polilínea o una geometría, no ambas",
+		"precision must be between 1 and 9":                      "La precisión debe estar entre 1 y 9",
+		"a polyline or a geometry is required":                   "Se requiere una polilínea o una geometría",
+		"invalid ensemble method":                                "Método de conjunto no válido",
+		"%s response body exceeds the maximum size of %d bytes":  "El cuerpo de la respuesta de %s supera el tamaño máximo de %d bytes",
+		"weather provider %s timed out":                          "El proveedor del tiempo %s no respondió a tiempo",
+		"%s response does not match its schema: %s":              "La respuesta de %s no coincide con su esquema: %s",
+		"no weather provider is configured":                      "No hay ningún proveedor del tiempo configurado",
+		"invalid admin token":                                    "Token de administración no válido",
+		"owner is required":                                      "Se requiere el propietario",
+		"unknown tier %s":                                        "Nivel %s desconocido",
+		"expiresat must be in the future":                        "expiresAt debe estar en el futuro",
+		"client %s not found":                                    "Cliente %s no encontrado",
+		"client %s is revoked":                                   "El cliente %s está revocado",
+		"api key is required":                                    "Se requiere una clave de API",
+		"invalid api key":                                        "Clave de API no válida",
+		"api key revoked":                                        "Clave de API revocada",
+		"api key expired":                                        "Clave de API caducada",
+		"client keys are unavailable":                            "Las claves de cliente no están disponibles",
+		"monthly quota of %d requests exceeded, it resets on %s": "Cuota mensual de %d solicitudes superada, se restablece el %s",
+		"%d requests exceed the %d left of the monthly quota of %d requests, it resets on %s": "%d solicitudes superan las %d que quedan de la cuota mensual de %d solicitudes, se restablece el %s",
+		"bounding box is out of range":     "El área delimitada está fuera de rango",
+		"south must not be north of north": "El sur no debe estar al norte del norte",
+		"west must not be east of east, boxes across the antimeridian are not supported":                       "El oeste no debe estar al este del este, no se admiten áreas que crucen el antimeridiano",
+		"grid of %.0f x %.0f points exceeds the limit of %d points, use a coarser resolution or a smaller box": "Una cuadrícula de %.0f x %.0f puntos supera el límite de %d puntos, use una resolución más gruesa o un área más pequeña",
+		"the route needs at least one point":                                                        "La ruta necesita al menos un punto",
+		"route point %g,%g is out of range":                                                         "El punto de ruta %g,%g está fuera de rango",
+		"speed must be a positive number of km/h":                                                   "La velocidad debe ser un número positivo de km/h",
+		"interval must be a positive number of kilometers":                                          "El intervalo debe ser un número positivo de kilómetros",
+		"a %.0f km route sampled every %g km exceeds the limit of %d points, use a longer interval": "Una ruta de %.0f km muestreada cada %g km supera el límite de %d puntos, use un intervalo más largo",
+		"at %g km/h the route takes longer than %s":                                                 "A %g km/h la ruta tarda más de %s",
+		"no hourly forecast for %s":                                                                 "No hay previsión horaria para %s",
+		"polyline ends in the middle of a point":                                                    "La polilínea termina en medio de un punto",
+		"invalid polyline character %q":                                                             "Carácter de polilínea %q no válido",
+		"polyline value overflows":                                                                  "Un valor de la polilínea se desborda",
+		"invalid geojson geometry":                                                                  "Geometría GeoJSON no válida",
+		"expected a geojson linestring, got %q":                                                     "Se esperaba un LineString de GeoJSON, se recibió %q",
+		"every geojson position needs a longitude and a latitude":                                   "Cada posición GeoJSON necesita una longitud y una latitud",
+		"response cannot be rendered as protobuf":                                                   "La respuesta no se puede generar como protobuf",
+		"supported formats are %s":                                                                  "Los formatos admitidos son %s",
+	},
+	"fr": {
+		"clear":                         "Dégagé",
+		"mostly clear":                  "Plutôt dégagé",
+		"partly cloudy":                 "Partiellement nuageux",
+		"mostly cloudy":                 "Plutôt nuageux",
+		"cloudy":                        "Nuageux",
+		"overcast":                      "Couvert",
+		"foggy":                         "Brouillard",
+		"drizzle":                       "Bruine",
+		"freezing drizzle":              "Bruine verglaçante",
+		"light rain":                    "Pluie légère",
+		"possible light rain":           "Pluie légère possible",
+		"rain":                          "Pluie",
+		"heavy rain":                    "Forte pluie",
+		"freezing rain":                 "Pluie verglaçante",
+		"light snow":                    "Neige légère",
+		"snow":                          "Neige",
+		"heavy snow":                    "Fortes chutes de neige",
+		"sleet":                         "Neige fondue",
+		"breezy":                        "Venteux",
+		"windy":                         "Vent fort",
+		"humid":                         "Humide",
+		"dry":                           "Sec",
+		"thunderstorm":                  "Orage",
+		"permission denied":             "Permission refusée",
+		"the given location is invalid": "La position indiquée est invalide",
+		"poorly formatted request":      "Requête mal formée",
+		"invalid json response body":    "Corps de réponse JSON invalide",
+		"error unmarshaling weather fetch response":              "Erreur de lecture de la réponse météo",
+		"apikey is required":                                     "apiKey est obligatoire",
+		"at least one location is required":                      "Au moins une position est obligatoire",
+		"invalid subscription message":                           "Message d'abonnement invalide",
+		"invalid day %s, expected %s":                            "Jour %s invalide, format attendu %s",
+		"format must be json or csv":                             "Le format doit être json ou csv",
+		"invalid json body":                                      "Corps JSON invalide",
+		"bbox must be west,south,east,north":                     "bbox doit être west,south,east,north",
+		"invalid bbox %q":                                        "bbox %q invalide",
+		"resolution must be a positive number of degrees":        "La résolution doit être un nombre positif de degrés",
+		"invalid location %q":                                    "Position %q invalide",
+		"send either a polyline or a geometry, not both":         "Envoyez une polyligne ou une géométrie, pas les deux",
+		"precision must be between 1 and 9":                      "La précision doit être comprise entre 1 et 9",
+		"a polyline or a geometry is required":                   "Une polyligne ou une géométrie est obligatoire",
+		"invalid ensemble method":                                "Méthode d'ensemble invalide",
+		"%s response body exceeds the maximum size of %d bytes":  "Le corps de la réponse de %s dépasse la taille maximale de %d octets",
+		"weather provider %s timed out":                          "Le fournisseur météo %s n'a pas répondu à temps",
+		"%s response does not match its schema: %s":              "La réponse de %s ne correspond pas à son schéma : %s",
+		"no weather provider is configured":                      "Aucun fournisseur météo n'est configuré",
+		"invalid admin token":                                    "Jeton d'administration invalide",
+		"owner is required":                                      "Le propriétaire est obligatoire",
+		"unknown tier %s":                                        "Niveau %s inconnu",
+		"expiresat must be in the future":                        "expiresAt doit être dans le futur",
+		"client %s not found":                                    "Client %s introuvable",
+		"client %s is revoked":                                   "Le client %s est révoqué",
+		"api key is required":                                    "La clé d'API est obligatoire",
+		"invalid api key":                                        "Clé d'API invalide",
+		"api key revoked":                                        "Clé d'API révoquée",
+		"api key expired":                                        "Clé d'API expirée",
+		"client keys are unavailable":                            "Les clés client sont indisponibles",
+		"monthly quota of %d requests exceeded, it resets on %s": "Quota mensuel de %d requêtes dépassé, il est réinitialisé le %s",
+		"%d requests exceed the %d left of the monthly quota of %d requests, it resets on %s": "%d requêtes dépassent les %d restantes du quota mensuel de %d requêtes, il est réinitialisé le %s",
+		"bounding box is out of range":     "La zone est hors limites",
+		"south must not be north of north": "Le sud ne doit pas être au nord du nord",
+		"west must not be east of east, boxes across the antimeridian are not supported":                       "L'ouest ne doit pas être à l'est de l'est, les zones traversant l'antiméridien ne sont pas prises en charge",
+		"grid of %.0f x %.0f points exceeds the limit of %d points, use a coarser resolution or a smaller box": "Une grille de %.0f x %.0f points dépasse la limite de %d points, utilisez une résolution plus grossière ou une zone plus petite",
+		"the route needs at least one point":                                                        "L'itinéraire doit comporter au moins un point",
+		"route point %g,%g is out of range":                                                         "Le point d'itinéraire %g,%g est hors limites",
+		"speed must be a positive number of km/h":                                                   "La vitesse doit être un nombre positif de km/h",
+		"interval must be a positive number of kilometers":                                          "L'intervalle doit être un nombre positif de kilomètres",
+		"a %.0f km route sampled every %g km exceeds the limit of %d points, use a longer interval": "Un itinéraire de %.0f km échantillonné tous les %g km dépasse la limite de %d points, utilisez un intervalle plus long",
+		"at %g km/h the route takes longer than %s":                                                 "À %g km/h l'itinéraire dure plus de %s",
+		"no hourly forecast for %s":                                                                 "Aucune prévision horaire pour %s",
+		"polyline ends in the middle of a point":                                                    "La polyligne se termine au milieu d'un point",
+		"invalid polyline character %q":                                                             "Caractère de polyligne %q invalide",
+		"polyline value overflows":                                                                  "Une valeur de la polyligne déborde",
+		"invalid geojson geometry":                                                                  "Géométrie GeoJSON invalide",
+		"expected a geojson linestring, got %q":                                                     "LineString GeoJSON attendu, %q reçu",
+		"every geojson position needs a longitude and a latitude":                                   "Chaque position GeoJSON doit avoir une longitude et une latitude",
+		"response cannot be rendered as protobuf":                                                   "La réponse ne peut pas être rendue en protobuf",
+		"supported formats are %s":                                                                  "Les formats pris en charge sont %s",
+	},
+	"de": {
+		"clear":                         "Klar",
+		"mostly clear":                  "Überwiegend klar",
+		"partly cloudy":                 "Teilweise bewölkt",
+		"mostly cloudy":                 "Überwiegend bewölkt",
+		"cloudy":                        "Bewölkt",
+		"overcast":                      "Bedeckt",
+		"foggy":                         "Neblig",
+		"drizzle":                       "Nieselregen",
+		"freezing drizzle":              "Gefrierender Nieselregen",
+		"light rain":                    "Leichter Regen",
+		"possible light rain":           "Möglicherweise leichter Regen",
+		"rain":                          "Regen",
+		"heavy rain":                    "Starker Regen",
+		"freezing rain":                 "Gefrierender Regen",
+		"light snow":                    "Leichter Schneefall",
+		"snow":                          "Schnee",
+		"heavy snow":                    "Starker Schneefall",
+		"sleet":                         "Schneeregen",
+		"breezy":                        "Frisch",
+		"windy":                         "Windig",
+		"humid":                         "Schwül",
+		"dry":                           "Trocken",
+		"thunderstorm":                  "Gewitter",
+		"permission denied":             "Zugriff verweigert",
+		"the given location is invalid": "Der angegebene Ort ist ungültig",
+		"poorly formatted request":      "Fehlerhaft formatierte Anfrage",
+		"invalid json response body":    "Ungültiger JSON-Antworttext",
+		"error unmarshaling weather fetch response":              "Fehler beim Lesen der Wetterantwort",
+		"apikey is required":                                     "apiKey ist erforderlich",
+		"at least one location is required":                      "Mindestens ein Ort ist erforderlich",
+		"invalid subscription message":                           "Ungültige Abonnementnachricht",
+		"invalid day %s, expected %s":                            "Ungültiger Tag %s, erwartet wird %s",
+		"format must be json or csv":                             "Das Format muss json oder csv sein",
+		"invalid json body":                                      "Ungültiger JSON-Text",
+		"bbox must be west,south,east,north":                     "bbox muss west,south,east,north sein",
+		"invalid bbox %q":                                        "Ungültige bbox %q",
+		"resolution must be a positive number of degrees":        "Die Auflösung muss eine positive Anzahl von Grad sein",
+		"invalid location %q":                                    "Ungültiger Ort %q",
+		"send either a polyline or a geometry, not both":         "Senden Sie entweder eine Polylinie oder eine Geometrie, nicht beides",
+		"precision must be between 1 and 9":                      "Die Genauigkeit muss zwischen 1 und 9 liegen",
+		"a polyline or a geometry is required":                   "Eine Polylinie oder eine Geometrie ist erforderlich",
+		"invalid ensemble method":                                "Ungültige Ensemble-Methode",
+		"%s response body exceeds the maximum size of %d bytes":  "Der Antworttext von %s überschreitet die maximale Größe von %d Bytes",
+		"weather provider %s timed out":                          "Zeitüberschreitung beim Wetteranbieter %s",
+		"%s response does not match its schema: %s":              "Die Antwort von %s entspricht nicht ihrem Schema: %s",
+		"no weather provider is configured":                      "Es ist kein Wetteranbieter konfiguriert",
+		"invalid admin token":                                    "Ungültiges Admin-Token",
+		"owner is required":                                      "Der Eigentümer ist erforderlich",
+		"unknown tier %s":                                        "Unbekannte Stufe %s",
+		"expiresat must be in the future":                        "expiresAt muss in der Zukunft liegen",
+		"client %s not found":                                    "Client %s nicht gefunden",
+		"client %s is revoked":                                   "Client %s ist widerrufen",
+		"api key is required":                                    "Ein API-Schlüssel ist erforderlich",
+		"invalid api key":                                        "Ungültiger API-Schlüssel",
+		"api key revoked":                                        "API-Schlüssel widerrufen",
+		"api key expired":                                        "API-Schlüssel abgelaufen",
+		"client keys are unavailable":                            "Client-Schlüssel sind nicht verfügbar",
+		"monthly quota of %d requests exceeded, it resets on %s": "Monatliches Kontingent von %d Anfragen überschritten, es wird am %s zurückgesetzt",
+		"%d requests exceed the %d left of the monthly quota of %d requests, it resets on %s": "%d Anfragen überschreiten die verbleibenden %d des monatlichen Kontingents von %d Anfragen, es wird am %s zurückgesetzt",
+		"bounding box is out of range":     "Das Begrenzungsrechteck liegt außerhalb des gültigen Bereichs",
+		"south must not be north of north": "Süd darf nicht nördlich von Nord liegen",
+		"west must not be east of east, boxes across the antimeridian are not supported":                       "West darf nicht östlich von Ost liegen, Bereiche über den Antimeridian werden nicht unterstützt",
+		"grid of %.0f x %.0f points exceeds the limit of %d points, use a coarser resolution or a smaller box": "Ein Raster von %.0f x %.0f Punkten überschreitet die Grenze von %d Punkten, verwenden Sie eine gröbere Auflösung oder einen kleineren Bereich",
+		"the route needs at least one point":                                                        "Die Route braucht mindestens einen Punkt",
+		"route point %g,%g is out of range":                                                         "Der Routenpunkt %g,%g liegt außerhalb des gültigen Bereichs",
+		"speed must be a positive number of km/h":                                                   "Die Geschwindigkeit muss eine positive Anzahl von km/h sein",
+		"interval must be a positive number of kilometers":                                          "Das Intervall muss eine positive Anzahl von Kilometern sein",
+		"a %.0f km route sampled every %g km exceeds the limit of %d points, use a longer interval": "Eine Route von %.0f km, alle %g km abgetastet, überschreitet die Grenze von %d Punkten, verwenden Sie ein längeres Intervall",
+		"at %g km/h the route takes longer than %s":                                                 "Bei %g km/h dauert die Route länger als %s",
+		"no hourly forecast for %s":                                                                 "Keine stündliche Vorhersage für %s",
+		"polyline ends in the middle of a point":                                                    "Die Polylinie endet mitten in einem Punkt",
+		"invalid polyline character %q":                                                             "Ungültiges Polylinienzeichen %q",
+		"polyline value overflows":                                                                  "Ein Wert der Polylinie läuft über",
+		"invalid geojson geometry":                                                                  "Ungültige GeoJSON-Geometrie",
+		"expected a geojson linestring, got %q":                                                     "GeoJSON-LineString erwartet, %q erhalten",
+		"every geojson position needs a longitude and a latitude":                                   "Jede GeoJSON-Position braucht einen Längen- und einen Breitengrad",
+		"response cannot be rendered as protobuf":                                                   "Die Antwort kann nicht als protobuf ausgegeben werden",
+		"supported formats are %s":                                                                  "Unterstützte Formate sind %s",
+	},
+}
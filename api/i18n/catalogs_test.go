@@ -0,0 +1,95 @@
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//messages collects the error messages written as literals, or as fmt.Sprintf formats, across the api:
+//the WeatherErrors and the errors of geo, which are passed on as they are
+func messages(t *testing.T) map[string]string {
+	found := make(map[string]string)
+	literal := func(expr ast.Expr) (string, bool) {
+		if call, ok := expr.(*ast.CallExpr); ok && isSelector(call.Fun, "fmt", "Sprintf") && len(call.Args) > 0 {
+			expr = call.Args[0]
+		}
+		if lit, ok := expr.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			text, err := strconv.Unquote(lit.Value)
+			return text, err == nil
+		}
+		return "", false
+	}
+	err := filepath.Walk("..", func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+		if err != nil {
+			return err
+		}
+		inGeo := filepath.Base(filepath.Dir(path)) == "geo"
+		ast.Inspect(file, func(node ast.Node) bool {
+			var message ast.Expr
+			switch node := node.(type) {
+			case *ast.CallExpr:
+				switch {
+				case len(node.Args) == 0:
+				case isSelector(node.Fun, "weather_domain", "NewWeatherError", "NewBadRequestError", "NewUnauthorizedError",
+					"NewForbiddenError", "NewNotFoundError", "NewNotAcceptableError"):
+					message = node.Args[len(node.Args)-1]
+				case inGeo && (isSelector(node.Fun, "errors", "New") || isSelector(node.Fun, "fmt", "Errorf")):
+					message = node.Args[0]
+				}
+			case *ast.KeyValueExpr:
+				if key, ok := node.Key.(*ast.Ident); ok && key.Name == "ErrorMessage" {
+					message = node.Value
+				}
+			}
+			if text, ok := literal(message); ok {
+				found[text] = path
+			}
+			return true
+		})
+		return nil
+	})
+	assert.Nil(t, err)
+	return found
+}
+
+func isSelector(expr ast.Expr, pkg string, names ...string) bool {
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	if ident, ok := selector.X.(*ast.Ident); !ok || ident.Name != pkg {
+		return false
+	}
+	for _, name := range names {
+		if selector.Sel.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestCatalogsCoverErrorMessages(t *testing.T) {
+	found := messages(t)
+	assert.Contains(t, found, "invalid ensemble method")
+	assert.Contains(t, found, "%d requests exceed the %d left of the monthly quota of %d requests, it resets on %s")
+	for message, path := range found {
+		for lang, catalog := range catalogs {
+			translated, ok := catalog[strings.ToLower(message)]
+			if assert.True(t, ok, "%s: %q has no %s translation", path, message, lang) {
+				assert.EqualValues(t, len(verb.FindAllString(message, -1)), len(verb.FindAllString(translated, -1)), "%s translation of %q", lang, message)
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package i18n
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	DefaultLanguage = "en"
+)
+
+var (
+	//verb finds the printf verbs in the catalog keys of the messages built with fmt.Sprintf, such as "invalid bbox %q"
+	verb         = regexp.MustCompile(`%(\[\d+\])?[-+# 0-9.]*[a-zA-Z]`)
+	patterns     map[string]*regexp.Regexp
+	patternsOnce sync.Once
+)
+
+//Normalize reduces a language tag such as "fr-CA" or "PT_br" to its lower case primary subtag
+func Normalize(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
+
+//Supported reports whether summaries and messages can be translated to lang
+func Supported(lang string) bool {
+	lang = Normalize(lang)
+	if lang == DefaultLanguage {
+		return true
+	}
+	_, ok := catalogs[lang]
+	return ok
+}
+
+//Resolve picks the response language: an explicit lang parameter wins, otherwise the
+//Accept-Language entry with the highest quality that we have a catalog for, otherwise English
+func Resolve(lang string, acceptLanguage string) string {
+	if lang = Normalize(lang); lang != "" {
+		return lang
+	}
+	type weighted struct {
+		lang    string
+		quality float64
+	}
+	var candidates []weighted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		candidate := weighted{lang: Normalize(fields[0]), quality: 1}
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					candidate.quality = q
+				}
+			}
+		}
+		if candidate.lang != "" && candidate.quality > 0 {
+			candidates = append(candidates, candidate)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].quality > candidates[j].quality })
+	for _, candidate := range candidates {
+		if Supported(candidate.lang) {
+			return candidate.lang
+		}
+	}
+	return DefaultLanguage
+}
+
+//Translate looks message up in the catalog of lang and returns it unchanged when there is no translation
+func Translate(lang string, message string) string {
+	catalog, ok := catalogs[Normalize(lang)]
+	if !ok {
+		return message
+	}
+	if translated, ok := catalog[strings.ToLower(message)]; ok {
+		return translated
+	}
+	//A message with values filled in is translated through its format, the values are carried over as they were
+	for key, pattern := range templates() {
+		translated, ok := catalog[key]
+		if !ok {
+			continue
+		}
+		if values := pattern.FindStringSubmatch(message); values != nil {
+			args := make([]interface{}, len(values)-1)
+			for i, value := range values[1:] {
+				args[i] = value
+			}
+			return fmt.Sprintf(verb.ReplaceAllString(translated, "%${1}s"), args...)
+		}
+	}
+	return message
+}
+
+//templates compiles, once, the catalog keys with printf verbs into patterns capturing the values of the verbs
+func templates() map[string]*regexp.Regexp {
+	patternsOnce.Do(func() {
+		patterns = make(map[string]*regexp.Regexp)
+		for _, catalog := range catalogs {
+			for key := range catalog {
+				if _, ok := patterns[key]; ok || !verb.MatchString(key) {
+					continue
+				}
+				var pattern strings.Builder
+				pattern.WriteString("(?i)^")
+				last := 0
+				for _, match := range verb.FindAllStringIndex(key, -1) {
+					pattern.WriteString(regexp.QuoteMeta(key[last:match[0]]) + "(.+?)")
+					last = match[1]
+				}
+				pattern.WriteString(regexp.QuoteMeta(key[last:]) + "$")
+				patterns[key] = regexp.MustCompile(pattern.String())
+			}
+		}
+	})
+	return patterns
+}
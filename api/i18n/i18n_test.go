@@ -0,0 +1,48 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	assert.EqualValues(t, "fr", Normalize("fr-CA"))
+	assert.EqualValues(t, "pt", Normalize(" PT_br "))
+	assert.EqualValues(t, "", Normalize(""))
+}
+
+func TestResolveExplicitLang(t *testing.T) {
+	assert.EqualValues(t, "es", Resolve("es-MX", "de"))
+	//An explicit language is passed on even without a catalog, the provider may still support it
+	assert.EqualValues(t, "ja", Resolve("ja", "de"))
+}
+
+func TestResolveAcceptLanguage(t *testing.T) {
+	assert.EqualValues(t, "de", Resolve("", "de-CH, fr;q=0.8"))
+	assert.EqualValues(t, "fr", Resolve("", "de;q=0.5, fr;q=0.8, en;q=0.1"))
+	assert.EqualValues(t, "es", Resolve("", "xx, es;q=0.9"))
+	assert.EqualValues(t, "en", Resolve("", "xx, zz;q=0.9"))
+	assert.EqualValues(t, "en", Resolve("", "fr;q=0"))
+	assert.EqualValues(t, "en", Resolve("", ""))
+}
+
+func TestTranslate(t *testing.T) {
+	assert.EqualValues(t, "Couvert", Translate("fr", "Overcast"))
+	assert.EqualValues(t, "Leichter Regen", Translate("de-AT", "light rain"))
+	assert.EqualValues(t, "Permiso denegado", Translate("es", "permission denied"))
+}
+
+func TestTranslateFallsBackToEnglish(t *testing.T) {
+	assert.EqualValues(t, "Overcast", Translate("en", "Overcast"))
+	assert.EqualValues(t, "Overcast", Translate("ja", "Overcast"))
+	assert.EqualValues(t, "Hurricane", Translate("fr", "Hurricane"))
+}
+
+func TestTranslateFormatted(t *testing.T) {
+	assert.EqualValues(t, `bbox "1,2" invalide`, Translate("fr", `invalid bbox "1,2"`))
+	assert.EqualValues(t, "Client Ab12 nicht gefunden", Translate("de", "client Ab12 not found"))
+	assert.EqualValues(t, "5 solicitudes superan las 2 que quedan de la cuota mensual de 100 solicitudes, se restablece el 2026-11-01",
+		Translate("es", "5 requests exceed the 2 left of the monthly quota of 100 requests, it resets on 2026-11-01"))
+	assert.EqualValues(t, "client Ab12 was deleted", Translate("de", "client Ab12 was deleted"))
+}
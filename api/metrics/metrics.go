@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+//Counter is a monotonically increasing value, exposed in the Prometheus text format by WriteText
+type Counter struct {
+	value int64
+}
+
+func (c *Counter) Inc() {
+	atomic.AddInt64(&c.value, 1)
+}
+
+func (c *Counter) Add(n int64) {
+	atomic.AddInt64(&c.value, n)
+}
+
+func (c *Counter) Value() int64 {
+	return atomic.LoadInt64(&c.value)
+}
+
+//CounterVec is a family of counters told apart by the values of their labels
+type CounterVec struct {
+	name     string
+	help     string
+	labels   []string
+	mu       sync.Mutex
+	counters map[string]*Counter
+}
+
+//With returns the counter for labelValues, given in the order of the labels of the family
+func (v *CounterVec) With(labelValues ...string) *Counter {
+	key := strings.Join(labelValues, labelSeparator)
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	counter, ok := v.counters[key]
+	if !ok {
+		counter = &Counter{}
+		v.counters[key] = counter
+	}
+	return counter
+}
+
+//labelSeparator joins the label values of a counter into its key, it cannot appear in valid UTF-8
+const labelSeparator = "\xff"
+
+var (
+	mu       sync.Mutex
+	families = map[string]*CounterVec{}
+)
+
+//NewCounter registers a counter without labels. Registering a name twice returns the same counter
+func NewCounter(name string, help string) *Counter {
+	return NewCounterVec(name, help).With()
+}
+
+//NewCounterVec registers a family of counters labelled with labels
+func NewCounterVec(name string, help string, labels ...string) *CounterVec {
+	mu.Lock()
+	defer mu.Unlock()
+	if family, ok := families[name]; ok {
+		return family
+	}
+	family := &CounterVec{name: name, help: help, labels: labels, counters: map[string]*Counter{}}
+	families[name] = family
+	return family
+}
+
+//WriteText writes every registered counter in the Prometheus text exposition format
+func WriteText(w io.Writer) error {
+	mu.Lock()
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	mu.Unlock()
+	sort.Strings(names)
+
+	for _, name := range names {
+		mu.Lock()
+		family := families[name]
+		mu.Unlock()
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, family.help, name); err != nil {
+			return err
+		}
+		family.mu.Lock()
+		keys := make([]string, 0, len(family.counters))
+		for key := range family.counters {
+			keys = append(keys, key)
+		}
+		family.mu.Unlock()
+		sort.Strings(keys)
+		for _, key := range keys {
+			labelValues := strings.Split(key, labelSeparator)
+			value := family.With(labelValues...).Value()
+			var err error
+			if len(family.labels) == 0 {
+				_, err = fmt.Fprintf(w, "%s %d\n", name, value)
+			} else {
+				pairs := make([]string, len(family.labels))
+				for i, label := range family.labels {
+					labelValue := ""
+					if i < len(labelValues) {
+						labelValue = labelValues[i]
+					}
+					pairs[i] = fmt.Sprintf("%s=\"%s\"", label, escape(labelValue))
+				}
+				_, err = fmt.Fprintf(w, "%s{%s} %d\n", name, strings.Join(pairs, ","), value)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func escape(labelValue string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(labelValue)
+}
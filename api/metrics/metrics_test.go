@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounter(t *testing.T) {
+	counter := NewCounter("test_counter_total", "A test counter.")
+	counter.Inc()
+	counter.Add(2)
+	assert.EqualValues(t, 3, counter.Value())
+	assert.True(t, counter == NewCounter("test_counter_total", "A test counter."))
+}
+
+func TestWriteText(t *testing.T) {
+	NewCounter("test_plain_total", "Plain counter.").Add(4)
+	vec := NewCounterVec("test_labelled_total", "Labelled counter.", "path")
+	vec.With("currently.temperature").Inc()
+	vec.With(`say "hi"`).Add(2)
+
+	var out bytes.Buffer
+	assert.Nil(t, WriteText(&out))
+	assert.Contains(t, out.String(), "# HELP test_plain_total Plain counter.\n# TYPE test_plain_total counter\ntest_plain_total 4\n")
+	assert.Contains(t, out.String(), "# TYPE test_labelled_total counter\n"+
+		"test_labelled_total{path=\"currently.temperature\"} 1\n"+
+		"test_labelled_total{path=\"say \\\"hi\\\"\"} 2\n")
+}
+
+func TestWriteTextSeveralLabels(t *testing.T) {
+	vec := NewCounterVec("test_multi_total", "Counter with two labels.", "provider", "kind")
+	vec.With("darksky", "unknown_field").Add(3)
+	vec.With("darksky", "missing_field").Inc()
+	vec.With("openmeteo", "unknown_field").Inc()
+	assert.EqualValues(t, 3, vec.With("darksky", "unknown_field").Value())
+
+	var out bytes.Buffer
+	assert.Nil(t, WriteText(&out))
+	assert.Contains(t, out.String(), "# TYPE test_multi_total counter\n"+
+		"test_multi_total{provider=\"darksky\",kind=\"missing_field\"} 1\n"+
+		"test_multi_total{provider=\"darksky\",kind=\"unknown_field\"} 3\n"+
+		"test_multi_total{provider=\"openmeteo\",kind=\"unknown_field\"} 1\n")
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"interface-testing/api/domain/client_domain"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/render"
+	"interface-testing/api/services"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	//ClientContextKey holds the authenticated *client_domain.Client in the gin context
+	ClientContextKey = "client"
+
+	ClientKeyHeader = "X-Api-Key"
+	//ClientKeyQuery is for browsers, which cannot set headers on EventSource and WebSocket requests
+	ClientKeyQuery = "client_key"
+
+	bearerScheme = "Bearer "
+)
+
+//AdminToken lets through the requests bearing token in their Authorization header, as "Bearer <token>"
+func AdminToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		//The scheme is case insensitive, a bare token without it is refused
+		if token == "" || len(header) < len(bearerScheme) || !strings.EqualFold(header[:len(bearerScheme)], bearerScheme) ||
+			subtle.ConstantTimeCompare([]byte(header[len(bearerScheme):]), []byte(token)) != 1 {
+			apiErr := weather_domain.NewUnauthorizedError("invalid admin token")
+			render.Abort(c, apiErr)
+			return
+		}
+		c.Next()
+	}
+}
+
+//ClientKey lets through the requests bearing a valid client key, as long as the client has quota left,
+//and records whose key it is
+func ClientKey() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(ClientKeyHeader)
+		if key == "" {
+			key = c.Query(ClientKeyQuery)
+		}
+		client, apiErr := services.ClientService.Authenticate(key)
+		if apiErr != nil {
+			render.Abort(c, apiErr)
+			return
+		}
+		quota, used, apiErr := services.UsageService.Allow(client)
+		if quota > 0 {
+			remaining := quota - used
+			if remaining < 0 {
+				remaining = 0
+			}
+			c.Header("X-Quota-Limit", strconv.FormatInt(quota, 10))
+			c.Header("X-Quota-Remaining", strconv.FormatInt(remaining, 10))
+		}
+		if apiErr != nil {
+			render.Abort(c, apiErr)
+			return
+		}
+		c.Set(ClientContextKey, client)
+		c.Next()
+	}
+}
+
+//Client is the client ClientKey authenticated, nil when authentication is off
+func Client(c *gin.Context) *client_domain.Client {
+	if client, ok := c.Get(ClientContextKey); ok {
+		return client.(*client_domain.Client)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"interface-testing/api/domain/client_domain"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/services"
+	"interface-testing/api/stores/usage_store"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type clientServiceMock struct{}
+
+func (m *clientServiceMock) Create(request client_domain.ClientRequest) (*client_domain.IssuedKey, weather_domain.WeatherErrorInterface) {
+	return nil, nil
+}
+func (m *clientServiceMock) List() []client_domain.Client {
+	return nil
+}
+func (m *clientServiceMock) Rotate(id string) (*client_domain.IssuedKey, weather_domain.WeatherErrorInterface) {
+	return nil, nil
+}
+func (m *clientServiceMock) Revoke(id string) (*client_domain.Client, weather_domain.WeatherErrorInterface) {
+	return nil, nil
+}
+
+//We are mocking the service method "Authenticate", only wk_valid is a valid key
+func (m *clientServiceMock) Authenticate(key string) (*client_domain.Client, weather_domain.WeatherErrorInterface) {
+	if key != "wk_valid" {
+		return nil, weather_domain.NewUnauthorizedError("invalid api key")
+	}
+	return &client_domain.Client{Id: "abc", Tier: client_domain.TierStandard}, nil
+}
+
+func serve(handler gin.HandlerFunc, request *http.Request) (*httptest.ResponseRecorder, *client_domain.Client) {
+	var client *client_domain.Client
+	router := gin.New()
+	router.GET("/", handler, func(c *gin.Context) {
+		client = Client(c)
+		c.Status(http.StatusOK)
+	})
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	return response, client
+}
+
+func TestClientKey(t *testing.T) {
+	services.ClientService = &clientServiceMock{}
+
+	request, _ := http.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("X-Api-Key", "wk_valid")
+	response, client := serve(ClientKey(), request)
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	assert.EqualValues(t, "abc", client.Id)
+
+	request, _ = http.NewRequest(http.MethodGet, "/?client_key=wk_valid", nil)
+	response, _ = serve(ClientKey(), request)
+	assert.EqualValues(t, http.StatusOK, response.Code)
+
+	request, _ = http.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("X-Api-Key", "wk_wrong")
+	response, client = serve(ClientKey(), request)
+	assert.EqualValues(t, http.StatusUnauthorized, response.Code)
+	assert.Nil(t, client)
+	apiErr, err := weather_domain.NewApiErrFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, "invalid api key", apiErr.Message())
+
+	//Refusals are negotiated like any other response
+	request, _ = http.NewRequest(http.MethodGet, "/?format=xml", nil)
+	request.Header.Set("X-Api-Key", "wk_wrong")
+	response, _ = serve(ClientKey(), request)
+	assert.EqualValues(t, http.StatusUnauthorized, response.Code)
+	assert.Contains(t, response.Header().Get("Content-Type"), "application/xml")
+	assert.EqualValues(t, "<WeatherError><code>401</code><error>invalid api key</error></WeatherError>", response.Body.String())
+
+	request.Header.Set("Accept-Language", "fr")
+	response, _ = serve(ClientKey(), request)
+	assert.EqualValues(t, "<WeatherError><code>401</code><error>Clé d&#39;API invalide</error></WeatherError>", response.Body.String())
+}
+
+func TestAdminToken(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Authorization", "Bearer s3cret")
+	response, _ := serve(AdminToken("s3cret"), request)
+	assert.EqualValues(t, http.StatusOK, response.Code)
+
+	request.Header.Set("Authorization", "bearer s3cret")
+	response, _ = serve(AdminToken("s3cret"), request)
+	assert.EqualValues(t, http.StatusOK, response.Code)
+
+	request.Header.Set("Authorization", "Bearer wrong")
+	response, _ = serve(AdminToken("s3cret"), request)
+	assert.EqualValues(t, http.StatusUnauthorized, response.Code)
+
+	//The token alone, without the scheme, is not enough
+	request.Header.Set("Authorization", "s3cret")
+	response, _ = serve(AdminToken("s3cret"), request)
+	assert.EqualValues(t, http.StatusUnauthorized, response.Code)
+
+	request.Header.Set("Authorization", "Basic s3cret")
+	response, _ = serve(AdminToken("s3cret"), request)
+	assert.EqualValues(t, http.StatusUnauthorized, response.Code)
+
+	request.Header.Del("Authorization")
+	response, _ = serve(AdminToken(""), request)
+	assert.EqualValues(t, http.StatusUnauthorized, response.Code)
+}
+
+func TestClientKeyQuota(t *testing.T) {
+	services.ClientService = &clientServiceMock{}
+	usage_store.UsageStore, _ = usage_store.NewFileStore("")
+	defer func(quota int64) { client_domain.MonthlyQuotas[client_domain.TierStandard] = quota }(client_domain.MonthlyQuotas[client_domain.TierStandard])
+	client_domain.MonthlyQuotas[client_domain.TierStandard] = 1
+
+	request, _ := http.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("X-Api-Key", "wk_valid")
+	response, _ := serve(ClientKey(), request)
+	assert.EqualValues(t, http.StatusOK, response.Code)
+	assert.EqualValues(t, "1", response.Header().Get("X-Quota-Limit"))
+	assert.EqualValues(t, "0", response.Header().Get("X-Quota-Remaining"))
+
+	response, client := serve(ClientKey(), request)
+	assert.EqualValues(t, http.StatusTooManyRequests, response.Code)
+	assert.Nil(t, client)
+	apiErr, err := weather_domain.NewApiErrFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.Contains(t, apiErr.Message(), "monthly quota of 1 requests exceeded")
+
+	request.Header.Set("Accept-Language", "de")
+	response, _ = serve(ClientKey(), request)
+	apiErr, err = weather_domain.NewApiErrFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.Contains(t, apiErr.Message(), "Monatliches Kontingent von 1 Anfragen überschritten")
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"fmt"
+	"interface-testing/api/redact"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+//secretsKey holds the path parameters that are secrets, for the access log to mask them
+const secretsKey = "redact.secrets"
+
+//Logger is gin's access log, with the secrets masked: the path parameters named like one, such as the apiKey
+//of the weather route, and whatever redact.Text recognizes in the query
+func Logger() gin.HandlerFunc {
+	logger := gin.LoggerWithConfig(gin.LoggerConfig{
+		Output: redact.Writer(gin.DefaultWriter),
+		Formatter: func(params gin.LogFormatterParams) string {
+			secrets, _ := params.Keys[secretsKey].([]string)
+			if params.Latency > time.Minute {
+				params.Latency = params.Latency.Truncate(time.Second)
+			}
+			return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v\n%s",
+				params.TimeStamp.Format("2006/01/02 - 15:04:05"),
+				params.StatusCode,
+				params.Latency,
+				params.ClientIP,
+				params.Method,
+				redact.Text(params.Path, secrets...),
+				params.ErrorMessage,
+			)
+		},
+	})
+	return func(c *gin.Context) {
+		c.Set(secretsKey, sensitiveParams(c))
+		logger(c)
+	}
+}
+
+//Recovery is gin's recovery, with the request it dumps on a panic masked like the access log
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		gin.RecoveryWithWriter(redact.Writer(gin.DefaultErrorWriter, sensitiveParams(c)...))(c)
+	}
+}
+
+//sensitiveParams are the values of the path parameters named like a secret. The route has matched by the time
+//the middlewares run, so its parameters are known
+func sensitiveParams(c *gin.Context) []string {
+	var secrets []string
+	for _, param := range c.Params {
+		if redact.IsSensitive(param.Key) {
+			secrets = append(secrets, param.Value)
+		}
+	}
+	return secrets
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"interface-testing/api/clients/restclient"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/providers/weather_provider"
+	"interface-testing/api/redact"
+	"interface-testing/api/services"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+//TestNoSecretInAnyOutput fails the upstream call so that its error, which quotes the url and so the api key,
+//goes everywhere: the response, the logs, the access log and the trace
+func TestNoSecretInAnyOutput(t *testing.T) {
+	const apiKey = "d4rk5ky_s3cr3t_k3y"
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+	var logged bytes.Buffer
+	log.SetOutput(redact.Writer(&logged))
+	defer log.SetOutput(os.Stderr)
+	defaultWriter := gin.DefaultWriter
+	gin.DefaultWriter = &logged
+	defer func() { gin.DefaultWriter = defaultWriter }()
+
+	refused := func(next http.RoundTripper) http.RoundTripper {
+		return restclient.RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+			return nil, fmt.Errorf("connection to %s refused", request.URL)
+		})
+	}
+	config := restclient.DefaultConfig
+	config.LogRequests = true
+	client, err := restclient.NewClient(config, refused)
+	assert.Nil(t, err)
+	restclient.Clients[weather_provider.DarkSky] = client
+	defer delete(restclient.Clients, weather_provider.DarkSky)
+
+	router := gin.New()
+	router.Use(Logger(), Tracing())
+	router.GET("/weather/:apiKey", func(c *gin.Context) {
+		request := weather_domain.WeatherRequest{ApiKey: c.Param("apiKey"), Latitude: 44.3601, Longitude: -71.0589}
+		_, apiErr := services.WeatherService.GetWeather(c.Request.Context(), request)
+		c.JSON(apiErr.Status(), apiErr)
+	})
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/weather/"+apiKey+"?client_key=wk_0123456789abcdefghijABCDEFGHIJ", nil)
+	router.ServeHTTP(response, request)
+
+	assert.EqualValues(t, http.StatusBadGateway, response.Code)
+	assert.Contains(t, response.Body.String(), "REDACTED")
+	assert.NotContains(t, response.Body.String(), apiKey)
+
+	assert.Contains(t, logged.String(), "error when trying to get weather from dark sky api")
+	assert.Contains(t, logged.String(), "[GIN]")
+	assert.NotContains(t, logged.String(), apiKey)
+	assert.NotContains(t, logged.String(), "wk_0123456789")
+
+	spans := recorder.Ended()
+	assert.NotEmpty(t, spans)
+	for _, span := range spans {
+		assert.NotContains(t, fmt.Sprint(span.Name(), span.Status(), span.Attributes(), span.Events()), apiKey, span.Name())
+	}
+}
+
+func TestRecoveryMasksSecrets(t *testing.T) {
+	const apiKey = "d4rk5ky_s3cr3t_k3y"
+	var logged bytes.Buffer
+	errorWriter := gin.DefaultErrorWriter
+	gin.DefaultErrorWriter = &logged
+	defer func() { gin.DefaultErrorWriter = errorWriter }()
+
+	router := gin.New()
+	router.Use(Recovery())
+	router.GET("/weather/:apiKey", func(c *gin.Context) {
+		panic("failed with " + c.Param("apiKey"))
+	})
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/weather/"+apiKey+"?client_key=wk_0123456789abcdefghijABCDEFGHIJ", nil)
+	request.Header.Set("X-Api-Key", "wk_0123456789abcdefghijABCDEFGHIJ")
+	router.ServeHTTP(response, request)
+
+	assert.EqualValues(t, http.StatusInternalServerError, response.Code)
+	assert.Contains(t, logged.String(), "panic recovered")
+	assert.Contains(t, logged.String(), "failed with REDACTED")
+	assert.NotContains(t, logged.String(), apiKey)
+	assert.NotContains(t, logged.String(), "wk_0123456789")
+}
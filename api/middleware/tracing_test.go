@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"interface-testing/api/clients/restclient"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/providers/weather_provider"
+	"interface-testing/api/services"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+//TestTracingAcrossLayers follows one request from gin down to the upstream call, which is answered by a
+//middleware of the dark sky client instead of the network
+func TestTracingAcrossLayers(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	var upstreamTraceparent string
+	upstream := func(next http.RoundTripper) http.RoundTripper {
+		return restclient.RoundTripperFunc(func(request *http.Request) (*http.Response, error) {
+			upstreamTraceparent = request.Header.Get("traceparent")
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(
+				`{"latitude": 12.34, "longitude": 56.78, "timezone": "Asia/Dubai", "currently": {"temperature": 30.1, "summary": "Clear"}}`))}, nil
+		})
+	}
+	client, err := restclient.NewClient(restclient.DefaultConfig, upstream)
+	assert.Nil(t, err)
+	restclient.Clients[weather_provider.DarkSky] = client
+	defer delete(restclient.Clients, weather_provider.DarkSky)
+
+	router := gin.New()
+	router.Use(Tracing())
+	router.GET("/weather/:apiKey", func(c *gin.Context) {
+		_, apiErr := services.WeatherService.GetWeather(c.Request.Context(), weather_domain.WeatherRequest{ApiKey: c.Param("apiKey"), Latitude: 12.34, Longitude: 56.78})
+		assert.Nil(t, apiErr)
+		c.Status(http.StatusOK)
+	})
+	request, _ := http.NewRequest(http.MethodGet, "/weather/tracing_key", nil)
+	request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.EqualValues(t, http.StatusOK, response.Code)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+		//Every span belongs to the trace of the caller
+		assert.EqualValues(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+	}
+	server, service := spans["GET /weather/:apiKey"], spans["WeatherService.GetWeather"]
+	provider, decode, call := spans["provider darksky"], spans["decode darksky"], spans["GET api.darksky.net"]
+	if assert.NotNil(t, server) && assert.NotNil(t, service) && assert.NotNil(t, decode) && assert.NotNil(t, call) {
+		assert.EqualValues(t, trace.SpanKindServer, server.SpanKind())
+		assert.EqualValues(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+		assert.EqualValues(t, server.SpanContext().SpanID(), service.Parent().SpanID())
+		assert.EqualValues(t, trace.SpanKindClient, call.SpanKind())
+		assert.EqualValues(t, decode.Parent().SpanID(), call.Parent().SpanID())
+		//The upstream is told about the client span, not the api key in the path
+		assert.EqualValues(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-"+call.SpanContext().SpanID().String()+"-01", upstreamTraceparent)
+		assert.NotContains(t, call.Name(), "tracing_key")
+	}
+	//Without a chain the provider is called directly, with no link span
+	assert.Nil(t, provider)
+}
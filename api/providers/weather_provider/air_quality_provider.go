@@ -0,0 +1,119 @@
+package weather_provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"interface-testing/api/clients/restclient"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/redact"
+	"log"
+	"net/http"
+	neturl "net/url"
+)
+
+const (
+	openMeteoAirQualityUrl     = "https://air-quality-api.open-meteo.com/v1/air-quality"
+	openMeteoAirQualityCurrent = "pm2_5,pm10,ozone,nitrogen_dioxide,sulphur_dioxide,carbon_monoxide," +
+		"alder_pollen,birch_pollen,grass_pollen,mugwort_pollen,olive_pollen,ragweed_pollen"
+
+	OpenMeteoAirQuality = "openmeteo_air"
+)
+
+//openMeteoAirQualityProvider reads the CAMS forecasts of open-meteo, which cover the whole world for the
+//pollutants and Europe for the pollen
+type openMeteoAirQualityProvider struct{}
+
+type airQualityProviderInterface interface {
+	GetAirQuality(ctx context.Context, request weather_domain.WeatherRequest) (*weather_domain.AirQualityInfo, *weather_domain.WeatherError)
+}
+
+//openMeteoAirQualityResponse has pointers, open-meteo sends null for what it has no value for at the location
+type openMeteoAirQualityResponse struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Current   struct {
+		Time    int64    `json:"time"`
+		PM25    *float64 `json:"pm2_5"`
+		PM10    *float64 `json:"pm10"`
+		O3      *float64 `json:"ozone"`
+		NO2     *float64 `json:"nitrogen_dioxide"`
+		SO2     *float64 `json:"sulphur_dioxide"`
+		CO      *float64 `json:"carbon_monoxide"`
+		Alder   *float64 `json:"alder_pollen"`
+		Birch   *float64 `json:"birch_pollen"`
+		Grass   *float64 `json:"grass_pollen"`
+		Mugwort *float64 `json:"mugwort_pollen"`
+		Olive   *float64 `json:"olive_pollen"`
+		Ragweed *float64 `json:"ragweed_pollen"`
+	} `json:"current"`
+}
+
+var (
+	AirQualityProvider airQualityProviderInterface = &openMeteoAirQualityProvider{}
+)
+
+//GetAirQuality gets the current pollutants and pollen at the location of request and rates them, units and
+//language do not apply
+func (p *openMeteoAirQualityProvider) GetAirQuality(ctx context.Context, request weather_domain.WeatherRequest) (*weather_domain.AirQualityInfo, *weather_domain.WeatherError) {
+	query := neturl.Values{}
+	query.Set("latitude", fmt.Sprint(request.Latitude))
+	query.Set("longitude", fmt.Sprint(request.Longitude))
+	query.Set("current", openMeteoAirQualityCurrent)
+	query.Set("timeformat", "unixtime")
+
+	upstream, err := http.NewRequestWithContext(ctx, http.MethodGet, openMeteoAirQualityUrl+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, &weather_domain.WeatherError{Code: http.StatusInternalServerError, ErrorMessage: redact.Context(ctx, err.Error())}
+	}
+	response, err := restclient.For(OpenMeteoAirQuality).Do(upstream)
+	if err != nil {
+		log.Println(fmt.Sprintf("error when trying to get air quality from open-meteo api %s", redact.Context(ctx, err.Error())))
+		return nil, &weather_domain.WeatherError{Code: http.StatusBadGateway, ErrorMessage: redact.Context(ctx, err.Error())}
+	}
+	bytes, err := readBody(response)
+	if err == errBodyTooLarge {
+		return nil, bodyTooLarge(OpenMeteoAirQuality)
+	}
+	if err != nil {
+		return nil, &weather_domain.WeatherError{Code: http.StatusBadGateway, ErrorMessage: redact.Context(ctx, err.Error())}
+	}
+
+	if response.StatusCode > 299 {
+		var errResponse openMeteoError
+		if err := json.Unmarshal(bytes, &errResponse); err != nil || errResponse.Reason == "" {
+			return nil, &weather_domain.WeatherError{Code: http.StatusInternalServerError, ErrorMessage: "invalid json response body"}
+		}
+		return nil, &weather_domain.WeatherError{Code: response.StatusCode, ErrorMessage: errResponse.Reason}
+	}
+	var result openMeteoAirQualityResponse
+	if _, decodeErr := decode(ctx, OpenMeteoAirQuality, bytes, &result); decodeErr != nil {
+		return nil, decodeErr
+	}
+	current := result.Current
+	airQuality := &weather_domain.AirQualityInfo{
+		Time: current.Time,
+		Pollutants: weather_domain.PollutantsInfo{
+			PM25: current.PM25,
+			PM10: current.PM10,
+			O3:   current.O3,
+			NO2:  current.NO2,
+			SO2:  current.SO2,
+			CO:   current.CO,
+		},
+		Pollen: &weather_domain.PollenInfo{
+			Alder:   current.Alder,
+			Birch:   current.Birch,
+			Grass:   current.Grass,
+			Mugwort: current.Mugwort,
+			Olive:   current.Olive,
+			Ragweed: current.Ragweed,
+		},
+		Provider: OpenMeteoAirQuality,
+	}
+	if airQuality.Pollen.Empty() {
+		airQuality.Pollen = nil
+	}
+	airQuality.Aqi = weather_domain.NewAqiInfo(airQuality.Pollutants)
+	return airQuality, nil
+}
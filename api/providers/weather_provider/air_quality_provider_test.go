@@ -0,0 +1,97 @@
+package weather_provider
+
+import (
+	"context"
+	"interface-testing/api/clients/restclient"
+	"interface-testing/api/domain/weather_domain"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAirQualityNoError(t *testing.T) {
+	var requestedUrl string
+	getRequestFunc = func(url string) (*http.Response, error) {
+		requestedUrl = url
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(`{"latitude": 52.5, "longitude": 13.4, "generationtime_ms": 0.2, "utc_offset_seconds": 0, ` +
+				`"timezone": "GMT", "timezone_abbreviation": "GMT", "elevation": 38, "current_units": {"pm2_5": "μg/m³"}, ` +
+				`"current": {"time": 1583064000, "interval": 3600, "pm2_5": 12, "pm10": 30, "ozone": 100, "nitrogen_dioxide": 200, ` +
+				`"sulphur_dioxide": 4, "carbon_monoxide": 10000, "alder_pollen": null, "birch_pollen": 12.5, "grass_pollen": 3, ` +
+				`"mugwort_pollen": null, "olive_pollen": null, "ragweed_pollen": null}}`)),
+		}, nil
+	}
+	restclient.ClientStruct = &getClientMock{} //without this line, the real api is fired
+
+	airQuality, err := AirQualityProvider.GetAirQuality(context.Background(), weather_domain.WeatherRequest{Latitude: 52.52, Longitude: 13.41})
+	assert.Nil(t, err)
+	assert.NotNil(t, airQuality)
+
+	parsed, _ := url.Parse(requestedUrl)
+	assert.EqualValues(t, "air-quality-api.open-meteo.com", parsed.Host)
+	assert.EqualValues(t, "52.52", parsed.Query().Get("latitude"))
+	assert.EqualValues(t, "13.41", parsed.Query().Get("longitude"))
+	assert.Contains(t, parsed.Query().Get("current"), "pm2_5")
+	assert.EqualValues(t, "unixtime", parsed.Query().Get("timeformat"))
+
+	assert.EqualValues(t, 1583064000, airQuality.Time)
+	assert.EqualValues(t, 12, *airQuality.Pollutants.PM25)
+	assert.EqualValues(t, 200, *airQuality.Pollutants.NO2)
+	assert.EqualValues(t, 10000, *airQuality.Pollutants.CO)
+	assert.NotNil(t, airQuality.Pollen)
+	assert.Nil(t, airQuality.Pollen.Alder)
+	assert.EqualValues(t, 12.5, *airQuality.Pollen.Birch)
+	assert.EqualValues(t, weather_domain.IndexInfo{Value: 102, Category: weather_domain.AqiUnhealthyForSensitiveGroups, Dominant: "no2"}, *airQuality.Aqi.Epa)
+	assert.EqualValues(t, weather_domain.IndexInfo{Value: 4, Category: weather_domain.EaqiPoor, Dominant: "no2"}, *airQuality.Aqi.Eu)
+	assert.EqualValues(t, OpenMeteoAirQuality, airQuality.Provider)
+}
+
+func TestGetAirQualityNoPollen(t *testing.T) {
+	getRequestFunc = func(url string) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(`{"latitude": 40.7, "longitude": -74, "current": {"time": 1583064000, "pm2_5": 5, ` +
+				`"alder_pollen": null, "birch_pollen": null, "grass_pollen": null, "mugwort_pollen": null, "olive_pollen": null, "ragweed_pollen": null}}`)),
+		}, nil
+	}
+	restclient.ClientStruct = &getClientMock{} //without this line, the real api is fired
+
+	airQuality, err := AirQualityProvider.GetAirQuality(context.Background(), weather_domain.WeatherRequest{Latitude: 40.71, Longitude: -74.01})
+	assert.Nil(t, err)
+	assert.Nil(t, airQuality.Pollen)
+	assert.Nil(t, airQuality.Pollutants.PM10)
+	assert.EqualValues(t, "pm2_5", airQuality.Aqi.Epa.Dominant)
+}
+
+func TestGetAirQualityError(t *testing.T) {
+	getRequestFunc = func(url string) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"error": true, "reason": "Latitude must be in range of -90 to 90°. Given: 91.0."}`)),
+		}, nil
+	}
+	restclient.ClientStruct = &getClientMock{} //without this line, the real api is fired
+
+	airQuality, err := AirQualityProvider.GetAirQuality(context.Background(), weather_domain.WeatherRequest{Latitude: 91})
+	assert.Nil(t, airQuality)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusBadRequest, err.Code)
+	assert.EqualValues(t, "Latitude must be in range of -90 to 90°. Given: 91.0.", err.ErrorMessage)
+}
+
+func TestGetAirQualityInvalidResponse(t *testing.T) {
+	getRequestFunc = func(url string) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(`{"current": {"pm2_5": "high"}}`))}, nil
+	}
+	restclient.ClientStruct = &getClientMock{} //without this line, the real api is fired
+
+	airQuality, err := AirQualityProvider.GetAirQuality(context.Background(), weather_domain.WeatherRequest{})
+	assert.Nil(t, airQuality)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.Code)
+}
@@ -0,0 +1,65 @@
+package weather_provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"interface-testing/api/domain/weather_domain"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+)
+
+var (
+	//MaxBodySize is the most bytes read from an upstream response, a larger one fails instead of filling the memory
+	MaxBodySize int64 = 1 << 20
+
+	//drainLimit is how much of an unread body is discarded to reuse its connection, past it the connection is closed
+	drainLimit int64 = 64 << 10
+
+	errBodyTooLarge = errors.New("response body too large")
+)
+
+//limitedReader fails with errBodyTooLarge, unlike io.LimitReader which ends quietly and makes the body look truncated
+type limitedReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.remaining <= 0 {
+		return 0, errBodyTooLarge
+	}
+	if int64(len(p)) > l.remaining {
+		p = p[:l.remaining]
+	}
+	n, err := l.reader.Read(p)
+	l.remaining -= int64(n)
+	return n, err
+}
+
+//readBody decodes the JSON value in the body of the response, as it streams in and up to MaxBodySize. A body
+//that is not JSON comes back empty, for the caller to report like any response it cannot decode. The body is
+//always drained and closed so that the keep-alive connection goes back to the pool
+func readBody(response *http.Response) (json.RawMessage, error) {
+	defer func() {
+		_, _ = io.Copy(ioutil.Discard, io.LimitReader(response.Body, drainLimit))
+		_ = response.Body.Close()
+	}()
+	var body json.RawMessage
+	err := json.NewDecoder(&limitedReader{reader: response.Body, remaining: MaxBodySize}).Decode(&body)
+	if _, syntax := err.(*json.SyntaxError); syntax || err == io.EOF || err == io.ErrUnexpectedEOF {
+		return nil, nil
+	}
+	return body, err
+}
+
+//bodyTooLarge is the error of provider when readBody returns errBodyTooLarge
+func bodyTooLarge(provider string) *weather_domain.WeatherError {
+	log.Println(fmt.Sprintf("%s response body is larger than %d bytes", provider, MaxBodySize))
+	return &weather_domain.WeatherError{
+		Code:         http.StatusBadGateway,
+		ErrorMessage: fmt.Sprintf("%s response body exceeds the maximum size of %d bytes", provider, MaxBodySize),
+	}
+}
@@ -0,0 +1,77 @@
+package weather_provider
+
+import (
+	"context"
+	"interface-testing/api/clients/restclient"
+	"interface-testing/api/domain/weather_domain"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//trackedBody records whether the provider read the body to its end and closed it
+type trackedBody struct {
+	*strings.Reader
+	closed bool
+}
+
+func (b *trackedBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestReadBodyDrainsAndCloses(t *testing.T) {
+	body := &trackedBody{Reader: strings.NewReader(`{"latitude": 44.3601, "longitude": -71.0589, "timezone": "America/New_York"}` + "\n\n")}
+	getRequestFunc = func(url string) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: body}, nil
+	}
+	restclient.ClientStruct = &getClientMock{}
+
+	response, err := WeatherProvider.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "anything", Latitude: 44.3601, Longitude: -71.0589})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 44.3601, response.Latitude)
+	assert.True(t, body.closed)
+	assert.EqualValues(t, 0, body.Len())
+}
+
+func TestReadBodyTooLarge(t *testing.T) {
+	defer func(size int64) { MaxBodySize = size }(MaxBodySize)
+	MaxBodySize = 32
+	var body *trackedBody
+	getRequestFunc = func(url string) (*http.Response, error) {
+		body = &trackedBody{Reader: strings.NewReader(`{"latitude": 44.3601, "longitude": -71.0589, "timezone": "America/New_York"}`)}
+		return &http.Response{StatusCode: http.StatusOK, Body: body}, nil
+	}
+	restclient.ClientStruct = &getClientMock{}
+
+	response, err := WeatherProvider.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "anything", Latitude: 44.3601, Longitude: -71.0589})
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusBadGateway, err.Code)
+	assert.EqualValues(t, "darksky response body exceeds the maximum size of 32 bytes", err.ErrorMessage)
+	assert.True(t, body.closed)
+
+	response, err = (&openMeteoProvider{}).GetWeather(context.Background(), weather_domain.WeatherRequest{Latitude: 44.3601, Longitude: -71.0589})
+	assert.Nil(t, response)
+	assert.EqualValues(t, "openmeteo response body exceeds the maximum size of 32 bytes", err.ErrorMessage)
+}
+
+func TestReadBodyStopsDraining(t *testing.T) {
+	defer func(limit int64) { drainLimit = limit }(drainLimit)
+	drainLimit = 4
+	body := &trackedBody{Reader: strings.NewReader(`{"code": 503, "error": "down"}` + strings.Repeat(" ", 4096))}
+	_, err := readBody(&http.Response{Body: body})
+	assert.Nil(t, err)
+	//Past drainLimit the connection is not worth keeping, it is closed with the rest unread
+	assert.True(t, body.closed)
+	assert.True(t, body.Len() > 0)
+}
+
+func TestReadBodyNotJson(t *testing.T) {
+	body := &trackedBody{Reader: strings.NewReader(`<html>down</html>`)}
+	bytes, err := readBody(&http.Response{Body: body})
+	assert.Nil(t, err)
+	assert.Nil(t, bytes)
+	assert.True(t, body.closed)
+}
@@ -0,0 +1,185 @@
+package weather_provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/metrics"
+	"interface-testing/api/schema"
+	"interface-testing/api/tracing"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//How the responses of a provider are decoded
+const (
+	//DecodeStandard is plain encoding/json: new fields go unnoticed and a changed type fails with a generic error
+	DecodeStandard = "standard"
+	//DecodeStrict also checks the response against the provider schema, reporting unknown fields, missing
+	//required fields and type mismatches with their path. It still only fails when the response cannot be decoded
+	DecodeStrict = "strict"
+	//DecodeLenient checks like DecodeStrict, then accepts numbers sent as strings and drops nulls and values of
+	//another type, so that the current conditions decode with those fields marked unavailable rather than failing
+	DecodeLenient = "lenient"
+)
+
+//currentBlock is the object a provider sends the current conditions in. fields maps its field names to the
+//json names of the CurrentlyInfo fields they fill, when they differ
+type currentBlock struct {
+	path   string
+	fields map[string]string
+}
+
+var (
+	//DecodeModes is the decode mode of each provider, the ones not listed use DecodeStrict
+	DecodeModes = map[string]string{}
+
+	//schemas describe what each provider sends: what we decode, what must be there and what we skip on purpose
+	schemas = map[string]*schema.Schema{
+		DarkSky: schema.New(darkSkyResponse{},
+			[]string{"$.latitude", "$.longitude", "$.timezone", "$.currently", "$.currently.temperature"},
+			[]string{"$.offset", "$.minutely", "$.daily", "$.alerts",
+				"$.flags.sources", "$.flags.nearest-station", "$.flags.meteoalarm-license", "$.flags.darksky-unavailable",
+				"$.currently.icon", "$.currently.nearestStormDistance", "$.currently.nearestStormBearing",
+				"$.currently.precipIntensity", "$.currently.precipIntensityError", "$.currently.precipProbability",
+				"$.currently.precipType", "$.currently.apparentTemperature", "$.currently.windBearing",
+				"$.currently.cloudCover", "$.currently.uvIndex", "$.currently.visibility", "$.currently.ozone",
+				"$.hourly.summary", "$.hourly.icon", "$.hourly.data[].icon", "$.hourly.data[].precipIntensity",
+				"$.hourly.data[].precipIntensityError", "$.hourly.data[].precipType", "$.hourly.data[].precipAccumulation",
+				"$.hourly.data[].apparentTemperature", "$.hourly.data[].windBearing", "$.hourly.data[].cloudCover",
+				"$.hourly.data[].uvIndex", "$.hourly.data[].visibility", "$.hourly.data[].ozone"}),
+		OpenMeteo: schema.New(openMeteoResponse{},
+			[]string{"$.latitude", "$.longitude", "$.timezone", "$.current", "$.current.temperature_2m"},
+			[]string{"$.generationtime_ms", "$.utc_offset_seconds", "$.timezone_abbreviation", "$.elevation",
+				"$.current_units", "$.current.interval", "$.hourly_units"}),
+		OpenMeteoAirQuality: schema.New(openMeteoAirQualityResponse{},
+			[]string{"$.latitude", "$.longitude", "$.current", "$.current.time"},
+			[]string{"$.generationtime_ms", "$.utc_offset_seconds", "$.timezone", "$.timezone_abbreviation", "$.elevation",
+				"$.current_units", "$.current.interval"}),
+	}
+
+	currentBlocks = map[string]currentBlock{
+		DarkSky: {path: "$.currently"},
+		OpenMeteo: {path: "$.current", fields: map[string]string{
+			"temperature_2m": "temperature", "relative_humidity_2m": "humidity", "dew_point_2m": "dewPoint",
+			"pressure_msl": "pressure", "wind_speed_10m": "windSpeed", "wind_gusts_10m": "windGust",
+			"weather_code": "summary",
+		}},
+	}
+
+	schemaDrift = metrics.NewCounterVec("weather_schema_drift_total", "Differences between provider responses and their expected schema.", "provider", "kind")
+
+	//reportedDrift keeps each drift from being logged more than once, the metric counts every occurrence
+	reportedDrift sync.Map
+)
+
+//SetDecodeModes configures DecodeModes from a spec such as "darksky=strict,openmeteo=standard"
+func SetDecodeModes(spec string) error {
+	modes := map[string]string{}
+	for _, entry := range strings.Split(spec, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid decode mode %q, expected provider=mode", entry)
+		}
+		if _, ok := Providers[parts[0]]; !ok {
+			return fmt.Errorf("unknown weather provider %q", parts[0])
+		}
+		if parts[1] != DecodeStandard && parts[1] != DecodeStrict && parts[1] != DecodeLenient {
+			return fmt.Errorf("unknown decode mode %q", parts[1])
+		}
+		modes[parts[0]] = parts[1]
+	}
+	DecodeModes = modes
+	return nil
+}
+
+//decode unmarshals the successful response of provider into target, as its decode mode says. unavailable names
+//the CurrentlyInfo fields a lenient decode had to leave out
+func decode(ctx context.Context, provider string, body []byte, target interface{}) (unavailable []string, decodeErr *weather_domain.WeatherError) {
+	mode := decodeMode(provider)
+	_, span := tracing.Tracer().Start(ctx, "decode "+provider, trace.WithAttributes(
+		attribute.String("weather.provider", provider), attribute.String("weather.decode_mode", mode), attribute.Int("weather.body_bytes", len(body))))
+	defer func() {
+		span.SetAttributes(attribute.StringSlice("weather.unavailable", unavailable))
+		if decodeErr != nil {
+			span.SetStatus(codes.Error, decodeErr.ErrorMessage)
+		}
+		span.End()
+	}()
+	var drifts []schema.Drift
+	providerSchema, ok := schemas[provider]
+	if ok && mode != DecodeStandard {
+		drifts, _ = providerSchema.Check(body)
+		reportDrift(provider, drifts)
+		span.SetAttributes(attribute.Int("weather.schema_drifts", len(drifts)))
+	}
+	if block, hasBlock := currentBlocks[provider]; ok && hasBlock && mode == DecodeLenient {
+		if coerced, dropped, err := providerSchema.Coerce(body, block.path); err == nil {
+			//Only the current conditions can do without a value, anything else still fails as in strict mode
+			if fields, inBlock := block.unavailable(dropped); inBlock {
+				body, unavailable = coerced, fields
+			}
+		}
+	}
+	if err := json.Unmarshal(body, target); err != nil {
+		log.Println(fmt.Sprintf("error when trying to unmarshal %s successful response: %s", provider, err.Error()))
+		var mismatches []string
+		for _, drift := range drifts {
+			if drift.Kind == schema.TypeMismatch {
+				mismatches = append(mismatches, drift.String())
+			}
+		}
+		if len(mismatches) > 0 {
+			return nil, &weather_domain.WeatherError{
+				Code:         http.StatusInternalServerError,
+				ErrorMessage: fmt.Sprintf("%s response does not match its schema: %s", provider, strings.Join(mismatches, "; ")),
+			}
+		}
+		return nil, &weather_domain.WeatherError{Code: http.StatusInternalServerError, ErrorMessage: "error unmarshaling weather fetch response"}
+	}
+	return unavailable, nil
+}
+
+//unavailable turns the paths dropped by a lenient decode into CurrentlyInfo field names. ok is false when a
+//path is outside of the block
+func (b currentBlock) unavailable(dropped []string) (fields []string, ok bool) {
+	for _, path := range dropped {
+		name := strings.TrimPrefix(path, b.path+".")
+		if name == path || strings.ContainsAny(name, ".[") {
+			if path != b.path {
+				return nil, false
+			}
+			continue
+		}
+		if field, renamed := b.fields[name]; renamed {
+			name = field
+		}
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return fields, true
+}
+
+func decodeMode(provider string) string {
+	if mode, ok := DecodeModes[provider]; ok {
+		return mode
+	}
+	return DecodeStrict
+}
+
+func reportDrift(provider string, drifts []schema.Drift) {
+	for _, drift := range drifts {
+		schemaDrift.With(provider, drift.Kind).Inc()
+		if _, seen := reportedDrift.LoadOrStore(provider+" "+drift.String(), true); !seen {
+			log.Println(fmt.Sprintf("schema drift in %s response: %s", provider, drift.String()))
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package weather_provider
+
+import (
+	"context"
+	"interface-testing/api/domain/weather_domain"
+	"sort"
+	"sync"
+)
+
+type ensembleProvider struct{}
+
+type ensembleProviderInterface interface {
+	GetWeathers(ctx context.Context, request weather_domain.WeatherRequest) []weather_domain.EnsembleMember
+}
+
+var (
+	EnsembleProvider ensembleProviderInterface = &ensembleProvider{}
+)
+
+//GetWeathers asks every configured provider at once: the links of the failover chain when
+//there is one, every registered provider otherwise. The members keep the order of the links
+func (e *ensembleProvider) GetWeathers(ctx context.Context, request weather_domain.WeatherRequest) []weather_domain.EnsembleMember {
+	links := ensembleLinks()
+	members := make([]weather_domain.EnsembleMember, len(links))
+	var wg sync.WaitGroup
+	for i, link := range links {
+		wg.Add(1)
+		go func(i int, link ChainLink) {
+			defer wg.Done()
+			member := weather_domain.EnsembleMember{Provider: link.Name, Weight: link.Weight}
+			if member.Weight <= 0 {
+				member.Weight = 1
+			}
+			result, err := callLink(ctx, link, request)
+			if err != nil {
+				member.Error = err
+			} else {
+				member.TimeZone = result.TimeZone
+				member.Currently = &result.Currently
+			}
+			members[i] = member
+		}(i, link)
+	}
+	wg.Wait()
+	return members
+}
+
+func ensembleLinks() []ChainLink {
+	if chain, ok := WeatherProvider.(*providerChain); ok {
+		return chain.links
+	}
+	var links []ChainLink
+	for name, provider := range Providers {
+		links = append(links, ChainLink{Name: name, Provider: provider})
+	}
+	sort.Slice(links, func(i, j int) bool { return links[i].Name < links[j].Name })
+	return links
+}
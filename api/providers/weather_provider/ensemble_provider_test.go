@@ -0,0 +1,55 @@
+package weather_provider
+
+import (
+	"context"
+	"interface-testing/api/domain/weather_domain"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnsembleProviderAsksEveryLink(t *testing.T) {
+	slow := &funcProvider{fn: func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		time.Sleep(50 * time.Millisecond)
+		return &weather_domain.Weather{TimeZone: "America/New_York", Currently: weather_domain.CurrentlyInfo{Temperature: 20}}, nil
+	}}
+	down := failing(http.StatusServiceUnavailable)
+	fast := succeeding("Clear")
+	original := WeatherProvider
+	defer func() { WeatherProvider = original }()
+	WeatherProvider = NewProviderChain(
+		ChainLink{Name: "slow", Provider: slow, Weight: 2},
+		ChainLink{Name: "down", Provider: down},
+		ChainLink{Name: "fast", Provider: fast},
+	)
+
+	members := EnsembleProvider.GetWeathers(context.Background(), chainRequest)
+	assert.EqualValues(t, 3, len(members))
+	assert.EqualValues(t, "slow", members[0].Provider)
+	assert.EqualValues(t, 2, members[0].Weight)
+	assert.EqualValues(t, "America/New_York", members[0].TimeZone)
+	assert.EqualValues(t, 20, members[0].Currently.Temperature)
+	assert.EqualValues(t, "down", members[1].Provider)
+	assert.Nil(t, members[1].Currently)
+	assert.EqualValues(t, http.StatusServiceUnavailable, members[1].Error.Code)
+	assert.EqualValues(t, "fast", members[2].Provider)
+	assert.EqualValues(t, 1, members[2].Weight)
+	assert.EqualValues(t, "Clear", members[2].Currently.Summary)
+	assert.EqualValues(t, 1, slow.calls)
+	assert.EqualValues(t, 1, down.calls)
+	assert.EqualValues(t, 1, fast.calls)
+}
+
+func TestEnsembleProviderWithoutChain(t *testing.T) {
+	original, originalProviders := WeatherProvider, Providers
+	defer func() { WeatherProvider, Providers = original, originalProviders }()
+	WeatherProvider = succeeding("Clear")
+	Providers = map[string]weatherServiceInterface{"b": succeeding("Overcast"), "a": succeeding("Clear")}
+
+	members := EnsembleProvider.GetWeathers(context.Background(), chainRequest)
+	assert.EqualValues(t, 2, len(members))
+	assert.EqualValues(t, "a", members[0].Provider)
+	assert.EqualValues(t, "b", members[1].Provider)
+}
@@ -0,0 +1,185 @@
+package weather_provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"interface-testing/api/clients/restclient"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/redact"
+	"log"
+	"net/http"
+	neturl "net/url"
+)
+
+const (
+	openMeteoUrl     = "https://api.open-meteo.com/v1/forecast"
+	openMeteoCurrent = "temperature_2m,relative_humidity_2m,dew_point_2m,pressure_msl,wind_speed_10m,wind_gusts_10m,weather_code"
+	openMeteoHourly  = openMeteoCurrent + ",precipitation_probability"
+
+	OpenMeteo = "openmeteo"
+)
+
+//openMeteoProvider needs no api key, which makes it a good fallback for dark sky
+type openMeteoProvider struct{}
+
+type openMeteoResponse struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	TimeZone  string  `json:"timezone"`
+	Current   struct {
+		Temperature      float64 `json:"temperature_2m"`
+		RelativeHumidity float64 `json:"relative_humidity_2m"`
+		DewPoint         float64 `json:"dew_point_2m"`
+		SeaLevelPressure float64 `json:"pressure_msl"`
+		WindSpeed        float64 `json:"wind_speed_10m"`
+		WindGust         float64 `json:"wind_gusts_10m"`
+		WeatherCode      int     `json:"weather_code"`
+		Time             int64   `json:"time"`
+	} `json:"current"`
+	//Hourly holds one array per variable, each indexed like Time
+	Hourly *struct {
+		Time                     []int64   `json:"time"`
+		Temperature              []float64 `json:"temperature_2m"`
+		RelativeHumidity         []float64 `json:"relative_humidity_2m"`
+		DewPoint                 []float64 `json:"dew_point_2m"`
+		SeaLevelPressure         []float64 `json:"pressure_msl"`
+		WindSpeed                []float64 `json:"wind_speed_10m"`
+		WindGust                 []float64 `json:"wind_gusts_10m"`
+		WeatherCode              []int     `json:"weather_code"`
+		PrecipitationProbability []float64 `json:"precipitation_probability"`
+	} `json:"hourly"`
+}
+
+type openMeteoError struct {
+	Error  bool   `json:"error"`
+	Reason string `json:"reason"`
+}
+
+var (
+	//openMeteoUnits maps our unit systems to open-meteo's temperature and wind speed units.
+	//open-meteo cannot pick units by location, so "auto" is served in si
+	openMeteoUnits = map[string][2]string{
+		weather_domain.UnitsUS:  {"fahrenheit", "mph"},
+		weather_domain.UnitsSI:  {"celsius", "ms"},
+		weather_domain.UnitsCA:  {"celsius", "kmh"},
+		weather_domain.UnitsUK2: {"celsius", "mph"},
+	}
+
+	//wmoSummaries describes the WMO weather interpretation codes open-meteo reports
+	wmoSummaries = map[int]string{
+		0: "Clear", 1: "Mostly Clear", 2: "Partly Cloudy", 3: "Overcast",
+		45: "Foggy", 48: "Foggy",
+		51: "Drizzle", 53: "Drizzle", 55: "Drizzle", 56: "Freezing Drizzle", 57: "Freezing Drizzle",
+		61: "Light Rain", 63: "Rain", 65: "Heavy Rain", 66: "Freezing Rain", 67: "Freezing Rain",
+		71: "Light Snow", 73: "Snow", 75: "Heavy Snow", 77: "Snow",
+		80: "Light Rain", 81: "Rain", 82: "Heavy Rain", 85: "Light Snow", 86: "Snow",
+		95: "Thunderstorm", 96: "Thunderstorm", 99: "Thunderstorm",
+	}
+)
+
+func (p *openMeteoProvider) GetWeather(ctx context.Context, request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+	units := request.Units
+	if _, ok := openMeteoUnits[units]; !ok {
+		units = weather_domain.UnitsUS
+		if request.Units == "auto" {
+			units = weather_domain.UnitsSI
+		}
+	}
+	query := neturl.Values{}
+	query.Set("latitude", fmt.Sprint(request.Latitude))
+	query.Set("longitude", fmt.Sprint(request.Longitude))
+	query.Set("current", openMeteoCurrent)
+	if request.Hourly {
+		query.Set("hourly", openMeteoHourly)
+	}
+	query.Set("timezone", "auto")
+	query.Set("timeformat", "unixtime")
+	query.Set("temperature_unit", openMeteoUnits[units][0])
+	query.Set("wind_speed_unit", openMeteoUnits[units][1])
+
+	//Open-Meteo takes no key, the secrets masked are those the caller put in ctx
+	upstream, err := http.NewRequestWithContext(ctx, http.MethodGet, openMeteoUrl+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, &weather_domain.WeatherError{Code: http.StatusInternalServerError, ErrorMessage: redact.Context(ctx, err.Error())}
+	}
+	response, err := restclient.For(OpenMeteo).Do(upstream)
+	if err != nil {
+		log.Println(fmt.Sprintf("error when trying to get weather from open-meteo api %s", redact.Context(ctx, err.Error())))
+		return nil, &weather_domain.WeatherError{Code: http.StatusBadGateway, ErrorMessage: redact.Context(ctx, err.Error())}
+	}
+	bytes, err := readBody(response)
+	if err == errBodyTooLarge {
+		return nil, bodyTooLarge(OpenMeteo)
+	}
+	if err != nil {
+		return nil, &weather_domain.WeatherError{Code: http.StatusBadGateway, ErrorMessage: redact.Context(ctx, err.Error())}
+	}
+
+	if response.StatusCode > 299 {
+		var errResponse openMeteoError
+		if err := json.Unmarshal(bytes, &errResponse); err != nil || errResponse.Reason == "" {
+			return nil, &weather_domain.WeatherError{Code: http.StatusInternalServerError, ErrorMessage: "invalid json response body"}
+		}
+		return nil, &weather_domain.WeatherError{Code: response.StatusCode, ErrorMessage: errResponse.Reason}
+	}
+	var result openMeteoResponse
+	unavailable, decodeErr := decode(ctx, OpenMeteo, bytes, &result)
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+	currently := weather_domain.CurrentlyInfo{
+		Temperature: result.Current.Temperature,
+		Summary:     wmoSummaries[result.Current.WeatherCode],
+		DewPoint:    result.Current.DewPoint,
+		Pressure:    result.Current.SeaLevelPressure,
+		Humidity:    result.Current.RelativeHumidity / 100,
+		WindSpeed:   result.Current.WindSpeed,
+		WindGust:    result.Current.WindGust,
+		Time:        result.Current.Time,
+		Unavailable: unavailable,
+	}
+	//Without a weather code the zero value would read as a clear sky
+	if currently.IsUnavailable("summary") {
+		currently.Summary = ""
+	}
+	return &weather_domain.Weather{
+		Latitude:  result.Latitude,
+		Longitude: result.Longitude,
+		TimeZone:  result.TimeZone,
+		Currently: currently,
+		Flags:     &weather_domain.FlagsInfo{Units: units},
+		Provider:  OpenMeteo,
+		Hourly:    result.hours(),
+	}, nil
+}
+
+//hours turns the hourly arrays into one HourlyInfo per hour, a value missing from its array is left at zero
+func (r *openMeteoResponse) hours() []weather_domain.HourlyInfo {
+	if r.Hourly == nil {
+		return nil
+	}
+	at := func(values []float64, i int) float64 {
+		if i < len(values) {
+			return values[i]
+		}
+		return 0
+	}
+	hours := make([]weather_domain.HourlyInfo, len(r.Hourly.Time))
+	for i, time := range r.Hourly.Time {
+		hours[i] = weather_domain.HourlyInfo{
+			Time:              time,
+			Temperature:       at(r.Hourly.Temperature, i),
+			DewPoint:          at(r.Hourly.DewPoint, i),
+			Pressure:          at(r.Hourly.SeaLevelPressure, i),
+			Humidity:          at(r.Hourly.RelativeHumidity, i) / 100,
+			WindSpeed:         at(r.Hourly.WindSpeed, i),
+			WindGust:          at(r.Hourly.WindGust, i),
+			PrecipProbability: at(r.Hourly.PrecipitationProbability, i) / 100,
+		}
+		if i < len(r.Hourly.WeatherCode) {
+			hours[i].Summary = wmoSummaries[r.Hourly.WeatherCode[i]]
+		}
+	}
+	return hours
+}
@@ -0,0 +1,184 @@
+package weather_provider
+
+import (
+	"context"
+	"interface-testing/api/clients/restclient"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/i18n"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenMeteoGetWeatherNoError(t *testing.T) {
+	var requestedUrl string
+	getRequestFunc = func(url string) (*http.Response, error) {
+		requestedUrl = url
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(`{"latitude": 44.36, "longitude": -71.06, "timezone": "America/New_York", "current": {"temperature_2m": 4.5, ` +
+				`"relative_humidity_2m": 81, "dew_point_2m": 1.4, "pressure_msl": 1012.3, "wind_speed_10m": 3.2, "wind_gusts_10m": 7.9, "weather_code": 3, "time": 1583064000}}`)),
+		}, nil
+	}
+	restclient.ClientStruct = &getClientMock{} //without this line, the real api is fired
+
+	response, err := (&openMeteoProvider{}).GetWeather(context.Background(), weather_domain.WeatherRequest{Latitude: 44.3601, Longitude: -71.0589, Units: "si"})
+	assert.Nil(t, err)
+	assert.NotNil(t, response)
+
+	parsed, _ := url.Parse(requestedUrl)
+	assert.EqualValues(t, "api.open-meteo.com", parsed.Host)
+	assert.EqualValues(t, "44.3601", parsed.Query().Get("latitude"))
+	assert.EqualValues(t, "-71.0589", parsed.Query().Get("longitude"))
+	assert.EqualValues(t, "celsius", parsed.Query().Get("temperature_unit"))
+	assert.EqualValues(t, "ms", parsed.Query().Get("wind_speed_unit"))
+	assert.EqualValues(t, "unixtime", parsed.Query().Get("timeformat"))
+	//Dark sky reports the pressure at sea level, so open-meteo is asked for it rather than the surface pressure
+	assert.Contains(t, strings.Split(parsed.Query().Get("current"), ","), "pressure_msl")
+	assert.NotContains(t, strings.Split(parsed.Query().Get("current"), ","), "surface_pressure")
+
+	assert.EqualValues(t, 44.36, response.Latitude)
+	assert.EqualValues(t, "America/New_York", response.TimeZone)
+	assert.EqualValues(t, "Overcast", response.Currently.Summary)
+	assert.EqualValues(t, 4.5, response.Currently.Temperature)
+	assert.EqualValues(t, 0.81, response.Currently.Humidity)
+	assert.EqualValues(t, 1.4, response.Currently.DewPoint)
+	assert.EqualValues(t, 1012.3, response.Currently.Pressure)
+	assert.EqualValues(t, 3.2, response.Currently.WindSpeed)
+	assert.EqualValues(t, 7.9, response.Currently.WindGust)
+	assert.EqualValues(t, 1583064000, response.Currently.Time)
+	assert.EqualValues(t, "si", response.Flags.Units)
+	assert.EqualValues(t, OpenMeteo, response.Provider)
+}
+
+func TestOpenMeteoGetWeatherDefaultUnits(t *testing.T) {
+	var requestedUrl string
+	getRequestFunc = func(url string) (*http.Response, error) {
+		requestedUrl = url
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(`{"current": {"weather_code": 0}}`))}, nil
+	}
+	restclient.ClientStruct = &getClientMock{} //without this line, the real api is fired
+
+	response, err := (&openMeteoProvider{}).GetWeather(context.Background(), weather_domain.WeatherRequest{Latitude: 44.3601, Longitude: -71.0589})
+	assert.Nil(t, err)
+	parsed, _ := url.Parse(requestedUrl)
+	assert.EqualValues(t, "fahrenheit", parsed.Query().Get("temperature_unit"))
+	assert.EqualValues(t, "mph", parsed.Query().Get("wind_speed_unit"))
+	assert.EqualValues(t, "us", response.Flags.Units)
+	assert.EqualValues(t, "Clear", response.Currently.Summary)
+}
+
+func TestOpenMeteoGetWeatherError(t *testing.T) {
+	getRequestFunc = func(url string) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"error": true, "reason": "Latitude must be in range of -90 to 90°. Given: 34223.3445."}`)),
+		}, nil
+	}
+	restclient.ClientStruct = &getClientMock{} //without this line, the real api is fired
+
+	response, err := (&openMeteoProvider{}).GetWeather(context.Background(), weather_domain.WeatherRequest{Latitude: 34223.3445, Longitude: -71.0589})
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusBadRequest, err.Code)
+	assert.EqualValues(t, "Latitude must be in range of -90 to 90°. Given: 34223.3445.", err.ErrorMessage)
+	assert.False(t, err.Retryable())
+}
+
+func TestOpenMeteoGetWeatherInvalidErrorBody(t *testing.T) {
+	getRequestFunc = func(url string) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: ioutil.NopCloser(strings.NewReader(`<html>down</html>`))}, nil
+	}
+	restclient.ClientStruct = &getClientMock{} //without this line, the real api is fired
+
+	response, err := (&openMeteoProvider{}).GetWeather(context.Background(), weather_domain.WeatherRequest{Latitude: 44.3601, Longitude: -71.0589})
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusInternalServerError, err.Code)
+	assert.EqualValues(t, "invalid json response body", err.ErrorMessage)
+}
+
+func TestOpenMeteoSchemaDrift(t *testing.T) {
+	getRequestFunc = func(url string) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(`{"latitude": 44.36, "longitude": -71.06, ` +
+			`"timezone": "America/New_York", "elevation": 12, "current": {"temperature_2m": 4.5, "weather_code": 3, "snow_depth": 0.1}}`))}, nil
+	}
+	restclient.ClientStruct = &getClientMock{}
+	unknown := schemaDrift.With(OpenMeteo, "unknown_field").Value()
+	missing := schemaDrift.With(OpenMeteo, "missing_field").Value()
+
+	response, err := (&openMeteoProvider{}).GetWeather(context.Background(), weather_domain.WeatherRequest{Latitude: 44.3601, Longitude: -71.0589})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 4.5, response.Currently.Temperature)
+	//elevation is known, snow_depth is new
+	assert.EqualValues(t, unknown+1, schemaDrift.With(OpenMeteo, "unknown_field").Value())
+	assert.EqualValues(t, missing, schemaDrift.With(OpenMeteo, "missing_field").Value())
+}
+
+func TestOpenMeteoLenient(t *testing.T) {
+	getRequestFunc = func(url string) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(`{"latitude": 44.36, "longitude": -71.06, ` +
+			`"timezone": "America/New_York", "current": {"time": 1583064000, "temperature_2m": "4.5", "relative_humidity_2m": 80, ` +
+			`"dew_point_2m": 1.2, "pressure_msl": 1012, "wind_speed_10m": null, "wind_gusts_10m": 9.4, "weather_code": null}}`))}, nil
+	}
+	restclient.ClientStruct = &getClientMock{}
+	DecodeModes = map[string]string{OpenMeteo: DecodeLenient}
+	defer func() { DecodeModes = map[string]string{} }()
+
+	response, err := (&openMeteoProvider{}).GetWeather(context.Background(), weather_domain.WeatherRequest{Latitude: 44.3601, Longitude: -71.0589})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 4.5, response.Currently.Temperature)
+	assert.EqualValues(t, 0.8, response.Currently.Humidity)
+	assert.EqualValues(t, "", response.Currently.Summary)
+	assert.EqualValues(t, []string{"summary", "windSpeed"}, response.Currently.Unavailable)
+}
+
+func TestSetDecodeModes(t *testing.T) {
+	defer func() { DecodeModes = map[string]string{} }()
+	assert.Nil(t, SetDecodeModes("darksky=standard, openmeteo=strict"))
+	assert.EqualValues(t, map[string]string{DarkSky: DecodeStandard, OpenMeteo: DecodeStrict}, DecodeModes)
+	assert.Nil(t, SetDecodeModes("openmeteo=lenient"))
+	assert.EqualValues(t, map[string]string{OpenMeteo: DecodeLenient}, DecodeModes)
+	assert.NotNil(t, SetDecodeModes("darksky"))
+	assert.NotNil(t, SetDecodeModes("weatherly=strict"))
+	assert.NotNil(t, SetDecodeModes("darksky=loose"))
+}
+
+func TestOpenMeteoGetWeatherHourly(t *testing.T) {
+	var requestedUrl string
+	getRequestFunc = func(url string) (*http.Response, error) {
+		requestedUrl = url
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(`{"current": {"temperature_2m": 4.5}, "hourly_units": {"time": "unixtime"}, "hourly": {"time": [1583064000, 1583067600], ` +
+				`"temperature_2m": [4.5, 3.1], "relative_humidity_2m": [81, 90], "pressure_msl": [1012.5, 1009.8], "weather_code": [3, 61], "precipitation_probability": [20, 75]}}`)),
+		}, nil
+	}
+	restclient.ClientStruct = &getClientMock{} //without this line, the real api is fired
+
+	response, err := (&openMeteoProvider{}).GetWeather(context.Background(), weather_domain.WeatherRequest{Latitude: 44.3601, Longitude: -71.0589, Units: "si", Hourly: true})
+	assert.Nil(t, err)
+	parsed, _ := url.Parse(requestedUrl)
+	assert.EqualValues(t, openMeteoHourly, parsed.Query().Get("hourly"))
+	//The variables missing from the response are left at zero
+	assert.EqualValues(t, []weather_domain.HourlyInfo{
+		{Time: 1583064000, Summary: "Overcast", Temperature: 4.5, Humidity: 0.81, Pressure: 1012.5, PrecipProbability: 0.2},
+		{Time: 1583067600, Summary: "Light Rain", Temperature: 3.1, Humidity: 0.9, Pressure: 1009.8, PrecipProbability: 0.75},
+	}, response.Hourly)
+
+	_, err = (&openMeteoProvider{}).GetWeather(context.Background(), weather_domain.WeatherRequest{Latitude: 44.3601, Longitude: -71.0589})
+	assert.Nil(t, err)
+	parsed, _ = url.Parse(requestedUrl)
+	assert.EqualValues(t, "", parsed.Query().Get("hourly"))
+}
+
+//Every summary open-meteo can report must be translated, the service passes the English one through otherwise
+func TestOpenMeteoSummariesTranslated(t *testing.T) {
+	for _, lang := range []string{"es", "fr", "de"} {
+		for code, summary := range wmoSummaries {
+			assert.NotEqual(t, summary, i18n.Translate(lang, summary), "%s has no %s translation, weather code %d", summary, lang, code)
+		}
+	}
+}
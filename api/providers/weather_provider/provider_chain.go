@@ -0,0 +1,200 @@
+package weather_provider
+
+import (
+	"context"
+	"fmt"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/metrics"
+	"interface-testing/api/tracing"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//ChainLink is one provider of a failover chain
+type ChainLink struct {
+	Name     string
+	Provider weatherServiceInterface
+	//Timeout bounds a single call, DefaultProviderTimeout applies when it is zero
+	Timeout time.Duration
+	//Weight counts in ensemble weighted means, zero counts as 1
+	Weight float64
+}
+
+type providerChain struct {
+	links  []ChainLink
+	health map[string]*providerHealth
+}
+
+//providerHealth keeps the outcome of the last EjectionWindow calls of a provider
+type providerHealth struct {
+	mu           sync.Mutex
+	outcomes     []bool
+	next         int
+	ejectedUntil time.Time
+}
+
+var (
+	//Providers are the providers a chain can be built from by name
+	Providers = map[string]weatherServiceInterface{
+		DarkSky:   &weatherProvider{},
+		OpenMeteo: &openMeteoProvider{},
+	}
+
+	DefaultProviderTimeout = 5 * time.Second
+
+	//A provider failing at least EjectionErrorRate of its last EjectionWindow calls (once it made
+	//EjectionMinRequests of them) is skipped for EjectionDuration, unless every provider is ejected
+	EjectionErrorRate   = 0.5
+	EjectionMinRequests = 5
+	EjectionWindow      = 20
+	EjectionDuration    = 30 * time.Second
+
+	providerFailures  = metrics.NewCounterVec("weather_provider_failures_total", "Retryable failures and timeouts per provider.", "provider")
+	providerEjections = metrics.NewCounterVec("weather_provider_ejections_total", "Times a provider was ejected for its error rate.", "provider")
+)
+
+//NewProviderChain tries the links in order: when one returns a retryable error or times out the next one is tried
+func NewProviderChain(links ...ChainLink) weatherServiceInterface {
+	chain := &providerChain{links: links, health: make(map[string]*providerHealth)}
+	for _, link := range links {
+		chain.health[link.Name] = &providerHealth{}
+	}
+	return chain
+}
+
+//NewProviderChainFromNames builds a chain from a comma separated list of Providers names, such as "darksky,openmeteo".
+//A name can carry its ensemble weight: "darksky:2,openmeteo:1"
+func NewProviderChainFromNames(names string) (weatherServiceInterface, error) {
+	var links []ChainLink
+	for _, name := range strings.Split(names, ",") {
+		link := ChainLink{Name: strings.TrimSpace(name)}
+		if i := strings.Index(link.Name, ":"); i >= 0 {
+			weight, err := strconv.ParseFloat(link.Name[i+1:], 64)
+			if err != nil || weight <= 0 {
+				return nil, fmt.Errorf("invalid weight for weather provider %q", link.Name)
+			}
+			link.Name, link.Weight = link.Name[:i], weight
+		}
+		provider, ok := Providers[link.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown weather provider %q", link.Name)
+		}
+		link.Provider = provider
+		links = append(links, link)
+	}
+	return NewProviderChain(links...), nil
+}
+
+func (c *providerChain) GetWeather(ctx context.Context, request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+	var lastErr *weather_domain.WeatherError
+	for _, link := range c.ordered() {
+		result, err := callLink(ctx, link, request)
+		health := c.health[link.Name]
+		if err == nil || !err.Retryable() {
+			health.record(true)
+		} else if health.record(false) {
+			providerEjections.With(link.Name).Inc()
+			log.Println(fmt.Sprintf("weather provider %s ejected for %s", link.Name, EjectionDuration))
+		}
+		if err == nil {
+			result.Provider = link.Name
+			return result, nil
+		}
+		if !err.Retryable() {
+			return nil, err
+		}
+		providerFailures.With(link.Name).Inc()
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+//ordered puts the healthy links first, ejected ones are only a last resort
+func (c *providerChain) ordered() []ChainLink {
+	var healthy, ejected []ChainLink
+	for _, link := range c.links {
+		if c.health[link.Name].ejected() {
+			ejected = append(ejected, link)
+		} else {
+			healthy = append(healthy, link)
+		}
+	}
+	return append(healthy, ejected...)
+}
+
+//callLink asks the provider of link, in a span of its own, giving up on it after the timeout of the link
+func callLink(ctx context.Context, link ChainLink, request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+	timeout := link.Timeout
+	if timeout == 0 {
+		timeout = DefaultProviderTimeout
+	}
+	ctx, span := tracing.Tracer().Start(ctx, "provider "+link.Name, trace.WithAttributes(attribute.String("weather.provider", link.Name)))
+	defer span.End()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	type outcome struct {
+		result *weather_domain.Weather
+		err    *weather_domain.WeatherError
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := link.Provider.GetWeather(ctx, request)
+		done <- outcome{result, err}
+	}()
+	select {
+	case o := <-done:
+		if o.err != nil {
+			span.SetStatus(codes.Error, o.err.ErrorMessage)
+		}
+		return o.result, o.err
+	case <-time.After(timeout):
+		span.SetStatus(codes.Error, "timed out")
+		return nil, &weather_domain.WeatherError{
+			Code:         http.StatusGatewayTimeout,
+			ErrorMessage: fmt.Sprintf("weather provider %s timed out", link.Name),
+		}
+	}
+}
+
+//record adds the outcome of a call and reports whether it got the provider ejected
+func (h *providerHealth) record(ok bool) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.outcomes) < EjectionWindow {
+		h.outcomes = append(h.outcomes, ok)
+	} else {
+		h.outcomes[h.next] = ok
+		h.next = (h.next + 1) % EjectionWindow
+	}
+	if ok || len(h.outcomes) < EjectionMinRequests {
+		return false
+	}
+	failures := 0
+	for _, outcome := range h.outcomes {
+		if !outcome {
+			failures++
+		}
+	}
+	if float64(failures)/float64(len(h.outcomes)) < EjectionErrorRate {
+		return false
+	}
+	//A provider coming back gets a clean slate instead of being ejected again on its first failure
+	h.ejectedUntil = time.Now().Add(EjectionDuration)
+	h.outcomes = nil
+	h.next = 0
+	return true
+}
+
+func (h *providerHealth) ejected() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return time.Now().Before(h.ejectedUntil)
+}
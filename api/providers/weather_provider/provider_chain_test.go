@@ -0,0 +1,187 @@
+package weather_provider
+
+import (
+	"context"
+	"errors"
+	"interface-testing/api/clients/restclient"
+	"interface-testing/api/domain/weather_domain"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//funcProvider is a chain link whose answer each test decides. The chain may call it from another goroutine,
+//so calls is counted atomically
+type funcProvider struct {
+	calls int32
+	fn    func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError)
+}
+
+func (p *funcProvider) GetWeather(ctx context.Context, request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+	atomic.AddInt32(&p.calls, 1)
+	return p.fn(request)
+}
+
+func failing(code int) *funcProvider {
+	return &funcProvider{fn: func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		return nil, &weather_domain.WeatherError{Code: code, ErrorMessage: http.StatusText(code)}
+	}}
+}
+
+func succeeding(summary string) *funcProvider {
+	return &funcProvider{fn: func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		return &weather_domain.Weather{Latitude: request.Latitude, Currently: weather_domain.CurrentlyInfo{Summary: summary}}, nil
+	}}
+}
+
+var chainRequest = weather_domain.WeatherRequest{ApiKey: "anything", Latitude: 44.3601, Longitude: -71.0589}
+
+func TestProviderChainPrimarySucceeds(t *testing.T) {
+	primary, secondary := succeeding("Clear"), succeeding("Overcast")
+	chain := NewProviderChain(ChainLink{Name: "primary", Provider: primary}, ChainLink{Name: "secondary", Provider: secondary})
+
+	response, err := chain.GetWeather(context.Background(), chainRequest)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "Clear", response.Currently.Summary)
+	assert.EqualValues(t, "primary", response.Provider)
+	assert.EqualValues(t, 0, atomic.LoadInt32(&secondary.calls))
+}
+
+func TestProviderChainFailsOverOnRetryableError(t *testing.T) {
+	primary, secondary := failing(http.StatusServiceUnavailable), succeeding("Overcast")
+	chain := NewProviderChain(ChainLink{Name: "primary", Provider: primary}, ChainLink{Name: "secondary", Provider: secondary})
+
+	response, err := chain.GetWeather(context.Background(), chainRequest)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "Overcast", response.Currently.Summary)
+	assert.EqualValues(t, "secondary", response.Provider)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&primary.calls))
+}
+
+func TestProviderChainStopsOnNonRetryableError(t *testing.T) {
+	primary, secondary := failing(http.StatusForbidden), succeeding("Overcast")
+	chain := NewProviderChain(ChainLink{Name: "primary", Provider: primary}, ChainLink{Name: "secondary", Provider: secondary})
+
+	response, err := chain.GetWeather(context.Background(), chainRequest)
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusForbidden, err.Code)
+	assert.EqualValues(t, 0, atomic.LoadInt32(&secondary.calls))
+}
+
+func TestProviderChainAllFail(t *testing.T) {
+	chain := NewProviderChain(ChainLink{Name: "primary", Provider: failing(http.StatusBadGateway)}, ChainLink{Name: "secondary", Provider: failing(http.StatusTooManyRequests)})
+
+	response, err := chain.GetWeather(context.Background(), chainRequest)
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusTooManyRequests, err.Code)
+}
+
+//sleeping answers after the chain has given up on it, finished lets the test wait for it before returning
+func sleeping(finished *sync.WaitGroup) *funcProvider {
+	finished.Add(1)
+	return &funcProvider{fn: func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		defer finished.Done()
+		time.Sleep(200 * time.Millisecond)
+		return &weather_domain.Weather{}, nil
+	}}
+}
+
+func TestProviderChainTimeout(t *testing.T) {
+	var finished sync.WaitGroup
+	defer finished.Wait()
+	chain := NewProviderChain(ChainLink{Name: "slow", Provider: sleeping(&finished), Timeout: 10 * time.Millisecond}, ChainLink{Name: "fast", Provider: succeeding("Clear")})
+
+	response, err := chain.GetWeather(context.Background(), chainRequest)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "fast", response.Provider)
+
+	chain = NewProviderChain(ChainLink{Name: "slow", Provider: sleeping(&finished), Timeout: 10 * time.Millisecond})
+	response, err = chain.GetWeather(context.Background(), chainRequest)
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusGatewayTimeout, err.Code)
+	assert.EqualValues(t, "weather provider slow timed out", err.ErrorMessage)
+}
+
+func TestProviderChainEjectsUnhealthyProvider(t *testing.T) {
+	primary, secondary := failing(http.StatusInternalServerError), succeeding("Overcast")
+	chain := NewProviderChain(ChainLink{Name: "unhealthy", Provider: primary}, ChainLink{Name: "secondary", Provider: secondary})
+	ejectionsBefore := providerEjections.With("unhealthy").Value()
+
+	for i := 0; i < EjectionMinRequests; i++ {
+		response, err := chain.GetWeather(context.Background(), chainRequest)
+		assert.Nil(t, err)
+		assert.EqualValues(t, "secondary", response.Provider)
+	}
+	assert.EqualValues(t, EjectionMinRequests, atomic.LoadInt32(&primary.calls))
+	assert.EqualValues(t, 1, providerEjections.With("unhealthy").Value()-ejectionsBefore)
+
+	//While ejected the primary is skipped
+	for i := 0; i < 3; i++ {
+		chain.GetWeather(context.Background(), chainRequest)
+	}
+	assert.EqualValues(t, EjectionMinRequests, atomic.LoadInt32(&primary.calls))
+
+	//Once the ejection is over it is tried first again
+	chain.(*providerChain).health["unhealthy"].ejectedUntil = time.Now().Add(-time.Second)
+	chain.GetWeather(context.Background(), chainRequest)
+	assert.EqualValues(t, EjectionMinRequests+1, atomic.LoadInt32(&primary.calls))
+}
+
+func TestProviderChainEjectedProvidersAreLastResort(t *testing.T) {
+	primary := failing(http.StatusInternalServerError)
+	chain := NewProviderChain(ChainLink{Name: "only", Provider: primary})
+	for i := 0; i < EjectionMinRequests; i++ {
+		chain.GetWeather(context.Background(), chainRequest)
+	}
+	assert.True(t, chain.(*providerChain).health["only"].ejected())
+
+	primary.fn = succeeding("Clear").fn
+	response, err := chain.GetWeather(context.Background(), chainRequest)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "only", response.Provider)
+}
+
+func TestNewProviderChainFromNames(t *testing.T) {
+	chain, err := NewProviderChainFromNames("darksky, openmeteo")
+	assert.Nil(t, err)
+	assert.Len(t, chain.(*providerChain).links, 2)
+	assert.EqualValues(t, OpenMeteo, chain.(*providerChain).links[1].Name)
+
+	chain, err = NewProviderChainFromNames("darksky,accuweather")
+	assert.Nil(t, chain)
+	assert.EqualValues(t, `unknown weather provider "accuweather"`, err.Error())
+}
+
+//brokenBody fails halfway, like a connection reset while the body is read
+type brokenBody struct{}
+
+func (brokenBody) Read(p []byte) (int, error) {
+	return 0, errors.New("connection reset by peer")
+}
+
+func (brokenBody) Close() error {
+	return nil
+}
+
+func TestProviderChainFailsOverOnUnreadableBody(t *testing.T) {
+	getRequestFunc = func(url string) (*http.Response, error) {
+		if strings.Contains(url, "open-meteo") {
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(`{"current": {"temperature_2m": 4.5, "weather_code": 3}}`))}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: brokenBody{}}, nil
+	}
+	restclient.ClientStruct = &getClientMock{}
+	chain, err := NewProviderChainFromNames("darksky,openmeteo")
+	assert.Nil(t, err)
+
+	response, apiErr := chain.GetWeather(context.Background(), chainRequest)
+	assert.Nil(t, apiErr)
+	assert.EqualValues(t, OpenMeteo, response.Provider)
+	assert.EqualValues(t, 4.5, response.Currently.Temperature)
+}
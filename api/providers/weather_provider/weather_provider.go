@@ -1,45 +1,100 @@
 package weather_provider
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"interface-testing/api/clients/restclient"
 	"interface-testing/api/domain/weather_domain"
-	"io/ioutil"
+	"interface-testing/api/redact"
 	"log"
 	"net/http"
+	neturl "net/url"
 )
 
 const (
 	weatherUrl = "https://api.darksky.net/forecast/%s/%v,%v"
+
+	DarkSky = "darksky"
 )
 type weatherProvider struct {}
 
+//darkSkyResponse is what dark sky sends, its hourly forecast comes wrapped in a data block
+type darkSkyResponse struct {
+	Latitude  float64                      `json:"latitude"`
+	Longitude float64                      `json:"longitude"`
+	TimeZone  string                       `json:"timezone"`
+	Currently weather_domain.CurrentlyInfo `json:"currently"`
+	Flags     *weather_domain.FlagsInfo    `json:"flags"`
+	Hourly    *struct {
+		Data []weather_domain.HourlyInfo `json:"data"`
+	} `json:"hourly"`
+}
+
 type weatherServiceInterface interface {
-	GetWeather(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError)
+	GetWeather(ctx context.Context, request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError)
 }
 var (
 	WeatherProvider weatherServiceInterface = &weatherProvider{}
+
+	//languages the dark sky api can write its summaries in
+	languages = map[string]bool{
+		"ar": true, "az": true, "be": true, "bg": true, "bn": true, "bs": true, "ca": true, "cs": true, "da": true,
+		"de": true, "el": true, "en": true, "eo": true, "es": true, "et": true, "fi": true, "fr": true, "he": true,
+		"hi": true, "hr": true, "hu": true, "id": true, "is": true, "it": true, "ja": true, "ka": true, "kn": true,
+		"ko": true, "kw": true, "lv": true, "ml": true, "mr": true, "nb": true, "nl": true, "no": true, "pa": true,
+		"pl": true, "pt": true, "ro": true, "ru": true, "sk": true, "sl": true, "sr": true, "sv": true, "ta": true,
+		"te": true, "tet": true, "tr": true, "uk": true, "ur": true, "zh": true,
+	}
 )
 
-func (p *weatherProvider) GetWeather(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
-	url := fmt.Sprintf(weatherUrl, request.ApiKey, request.Latitude, request.Longitude)
-	response, err := restclient.ClientStruct.Get(url)
+//SupportsLanguage tells the service whether the summaries already come back in lang
+func (p *weatherProvider) SupportsLanguage(lang string) bool {
+	return languages[lang]
+}
+
+func (p *weatherProvider) GetWeather(ctx context.Context, request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+	raw := fmt.Sprintf(weatherUrl, request.ApiKey, request.Latitude, request.Longitude)
+	query := neturl.Values{}
+	if request.Units != "" {
+		query.Set("units", request.Units)
+	}
+	if request.Lang != "" && p.SupportsLanguage(request.Lang) {
+		query.Set("lang", request.Lang)
+	}
+	//Dark sky sends 48 hours unless extended to a week
+	if request.Hourly {
+		query.Set("extend", "hourly")
+	}
+	if len(query) > 0 {
+		raw += "?" + query.Encode()
+	}
+	//The api key is in the path, every error quoting the url has to go through url.Error
+	url := redact.NewURL(raw, request.ApiKey)
+	ctx = redact.WithSecrets(ctx, request.ApiKey)
+	upstream, err := http.NewRequestWithContext(ctx, http.MethodGet, url.Reveal(), nil)
+	if err != nil {
+		return nil, &weather_domain.WeatherError{Code: http.StatusInternalServerError, ErrorMessage: url.Error(err)}
+	}
+	response, err := restclient.For(DarkSky).Do(upstream)
 	if err != nil {
-		log.Println(fmt.Sprintf("error when trying to get weather from dark sky api %s", err.Error()))
+		log.Println(fmt.Sprintf("error when trying to get weather from dark sky api %s", url.Error(err)))
 		return nil, &weather_domain.WeatherError{
-			Code:         http.StatusBadRequest,
-			ErrorMessage: err.Error(),
+			Code:         http.StatusBadGateway,
+			ErrorMessage: url.Error(err),
 		}
 	}
-	bytes, err := ioutil.ReadAll(response.Body)
+	bytes, err := readBody(response)
+	if err == errBodyTooLarge {
+		return nil, bodyTooLarge(DarkSky)
+	}
+	//A body cut short is the upstream's failure, not the request's, so the next provider gets a chance
 	if err != nil {
 		return nil, &weather_domain.WeatherError{
-			Code:         http.StatusBadRequest,
-			ErrorMessage: err.Error(),
+			Code:         http.StatusBadGateway,
+			ErrorMessage: url.Error(err),
 		}
 	}
-	defer response.Body.Close()
 
 	//The api owner can decide to change datatypes, etc. When this happen, it might affect the error format returned
 	if response.StatusCode > 299 {
@@ -51,12 +106,25 @@ func (p *weatherProvider) GetWeather(request weather_domain.WeatherRequest) (*we
 			}
 		}
 		errResponse.Code = response.StatusCode
+		errResponse.ErrorMessage = redact.Text(errResponse.ErrorMessage, request.ApiKey)
 		return nil, &errResponse
 	}
-	var result weather_domain.Weather
-	if err := json.Unmarshal(bytes, &result); err != nil {
-		log.Println(fmt.Sprintf("error when trying to unmarshal weather  successful response: %s", err.Error()))
-		return nil, &weather_domain.WeatherError{Code: http.StatusInternalServerError, ErrorMessage: "error unmarshaling weather fetch response"}
+	var forecast darkSkyResponse
+	unavailable, decodeErr := decode(ctx, DarkSky, bytes, &forecast)
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+	result := weather_domain.Weather{
+		Latitude:  forecast.Latitude,
+		Longitude: forecast.Longitude,
+		TimeZone:  forecast.TimeZone,
+		Currently: forecast.Currently,
+		Flags:     forecast.Flags,
+		Provider:  DarkSky,
+	}
+	result.Currently.Unavailable = unavailable
+	if request.Hourly && forecast.Hourly != nil {
+		result.Hourly = forecast.Hourly.Data
 	}
 	return &result, nil
 }
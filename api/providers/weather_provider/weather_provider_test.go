@@ -1,8 +1,11 @@
 package weather_provider
 
 import (
+	"context"
+	"errors"
 	"interface-testing/api/clients/restclient"
 	"interface-testing/api/domain/weather_domain"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
@@ -18,23 +21,31 @@ var (
 
 type getClientMock struct{}
 
-//We are mocking the client method "Get"
+//We are mocking the client methods, they all answer with getRequestFunc
 func (cm *getClientMock) Get(request string) (*http.Response, error) {
 	return getRequestFunc(request)
 }
 
+func (cm *getClientMock) Post(url string, contentType string, body io.Reader) (*http.Response, error) {
+	return getRequestFunc(url)
+}
+
+func (cm *getClientMock) Do(request *http.Request) (*http.Response, error) {
+	return getRequestFunc(request.URL.String())
+}
+
 //When the everything is good
 func TestGetWeatherNoError(t *testing.T) {
 	// The error we will get is from the "response" so we make the second parameter of the function is nil
 	getRequestFunc = func(url string) (*http.Response, error) {
 		return &http.Response{
 			StatusCode: http.StatusOK,
-			Body:       ioutil.NopCloser(strings.NewReader(`{"latitude": 44.3601, "longitude": -71.0589, "timezone": "America/New_York", "currently": {"summary": "Clear", "temperature": 40.22, "dewPoint": 50.22, "pressure": 12.90, "humidity": 16.54}}`)),
+			Body:       ioutil.NopCloser(strings.NewReader(`{"latitude": 44.3601, "longitude": -71.0589, "timezone": "America/New_York", "currently": {"time": 1583064000, "summary": "Clear", "temperature": 40.22, "dewPoint": 50.22, "pressure": 12.90, "humidity": 16.54}}`)),
 		}, nil
 	}
 	restclient.ClientStruct = &getClientMock{} //without this line, the real api is fired
 
-	response, err := WeatherProvider.GetWeather(weather_domain.WeatherRequest{"anything", 44.3601, -71.0589})
+	response, err := WeatherProvider.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "anything", Latitude: 44.3601, Longitude: -71.0589})
 	assert.NotNil(t, response)
 	assert.Nil(t, err)
 	assert.EqualValues(t, 44.3601, response.Latitude)
@@ -45,6 +56,8 @@ func TestGetWeatherNoError(t *testing.T) {
 	assert.EqualValues(t, 50.22, response.Currently.DewPoint)
 	assert.EqualValues(t, 16.54, response.Currently.Humidity)
 	assert.EqualValues(t, 12.90, response.Currently.Pressure)
+	assert.EqualValues(t, 1583064000, response.Currently.Time)
+	assert.EqualValues(t, DarkSky, response.Provider)
 }
 
 func TestGetWeatherInvalidApiKey(t *testing.T) {
@@ -56,7 +69,7 @@ func TestGetWeatherInvalidApiKey(t *testing.T) {
 	}
 	restclient.ClientStruct = &getClientMock{} //without this line, the real api is fired
 
-	response, err := WeatherProvider.GetWeather(weather_domain.WeatherRequest{"wrong_anything", 44.3601, -71.0589})
+	response, err := WeatherProvider.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "wrong_anything", Latitude: 44.3601, Longitude: -71.0589})
 	assert.NotNil(t, err)
 	assert.Nil(t, response)
 	assert.EqualValues(t, http.StatusForbidden, err.Code)
@@ -72,7 +85,7 @@ func TestGetWeatherInvalidLatitude(t *testing.T) {
 	}
 	restclient.ClientStruct = &getClientMock{} //without this line, the real api is fired
 
-	response, err := WeatherProvider.GetWeather(weather_domain.WeatherRequest{"anything", 34223.3445, -71.0589})
+	response, err := WeatherProvider.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "anything", Latitude: 34223.3445, Longitude: -71.0589})
 
 	assert.NotNil(t, err)
 	assert.Nil(t, response)
@@ -89,7 +102,7 @@ func TestGetWeatherInvalidLongitude(t *testing.T) {
 	}
 	restclient.ClientStruct = &getClientMock{} //without this line, the real api is fired
 
-	response, err := WeatherProvider.GetWeather(weather_domain.WeatherRequest{"anything", 44.3601, -74331.0589})
+	response, err := WeatherProvider.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "anything", Latitude: 44.3601, Longitude: -74331.0589})
 
 	assert.NotNil(t, err)
 	assert.Nil(t, response)
@@ -106,7 +119,7 @@ func TestGetWeatherInvalidFormat(t *testing.T) {
 	}
 	restclient.ClientStruct = &getClientMock{} //without this line, the real api is fired
 
-	response, err := WeatherProvider.GetWeather(weather_domain.WeatherRequest{"anything", 0, -74331.0589})
+	response, err := WeatherProvider.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "anything", Latitude: 0, Longitude: -74331.0589})
 
 	assert.NotNil(t, err)
 	assert.Nil(t, response)
@@ -124,7 +137,7 @@ func TestGetWeatherInvalidRestClient(t *testing.T) {
 	}
 	restclient.ClientStruct = &getClientMock{} //without this line, the real api is fired
 
-	response, err := WeatherProvider.GetWeather(weather_domain.WeatherRequest{"anything", 0, -74331.0589})
+	response, err := WeatherProvider.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "anything", Latitude: 0, Longitude: -74331.0589})
 	assert.NotNil(t, err)
 	assert.Nil(t, response)
 	assert.EqualValues(t, http.StatusBadRequest, err.Code)
@@ -140,7 +153,7 @@ func TestGetWeatherInvalidResponseBody(t *testing.T) {
 	}
 	restclient.ClientStruct = &getClientMock{} //without this line, the real api is fired
 
-	response, err := WeatherProvider.GetWeather(weather_domain.WeatherRequest{"wrong_anything", 44.3601, -71.0589})
+	response, err := WeatherProvider.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "wrong_anything", Latitude: 44.3601, Longitude: -71.0589})
 	assert.NotNil(t, err)
 	assert.Nil(t, response)
 	assert.EqualValues(t, http.StatusBadRequest, err.Code)
@@ -157,10 +170,11 @@ func TestGetWeatherInvalidRequest(t *testing.T) {
 	}
 	restclient.ClientStruct = &getClientMock{} //without this line, the real api is fired
 
-	response, err := WeatherProvider.GetWeather(weather_domain.WeatherRequest{"wrong_anything", 44.3601, -71.0589})
+	response, err := WeatherProvider.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "wrong_anything", Latitude: 44.3601, Longitude: -71.0589})
 	assert.Nil(t, response)
 	assert.NotNil(t, err)
-	assert.EqualValues(t, http.StatusBadRequest, err.Code)
+	assert.EqualValues(t, http.StatusBadGateway, err.Code)
+	assert.True(t, err.Retryable())
 	assert.EqualValues(t, "invalid argument", err.ErrorMessage)
 }
 
@@ -175,7 +189,7 @@ func TestGetWeatherInvalidErrorInterface(t *testing.T) {
 	}
 	restclient.ClientStruct = &getClientMock{} //without this line, the real api is fired
 
-	response, err := WeatherProvider.GetWeather(weather_domain.WeatherRequest{"anything", 44.3601, -71.0589})
+	response, err := WeatherProvider.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "anything", Latitude: 44.3601, Longitude: -71.0589})
 	assert.Nil(t, response)
 	assert.NotNil(t, err)
 	assert.EqualValues(t, http.StatusInternalServerError, err.Code)
@@ -192,9 +206,149 @@ func TestGetWeatherInvalidResponseInterface(t *testing.T) {
 	}
 	restclient.ClientStruct = &getClientMock{} //without this line, the real api is fired
 
-	response, err := WeatherProvider.GetWeather(weather_domain.WeatherRequest{"anything", 44.3601, -71.0589})
+	response, err := WeatherProvider.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "anything", Latitude: 44.3601, Longitude: -71.0589})
 	assert.Nil(t, response)
 	assert.NotNil(t, err)
 	assert.EqualValues(t, http.StatusInternalServerError, err.Code)
+	assert.EqualValues(t, "darksky response does not match its schema: $.latitude: expected number, got string", err.ErrorMessage)
+
+	//Without the schema check the error cannot tell what changed
+	DecodeModes = map[string]string{DarkSky: DecodeStandard}
+	defer func() { DecodeModes = map[string]string{} }()
+	_, err = WeatherProvider.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "anything", Latitude: 44.3601, Longitude: -71.0589})
 	assert.EqualValues(t, "error unmarshaling weather fetch response", err.ErrorMessage)
 }
+
+func TestGetWeatherLenient(t *testing.T) {
+	getRequestFunc = func(url string) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(`{"latitude": 44.3601, "longitude": -71.0589, "timezone": "America/New_York", ` +
+				`"currently": {"temperature": "41.5", "summary": "Clear", "dewPoint": 30.2, "pressure": "n/a", "humidity": null, ` +
+				`"windSpeed": 3.1, "time": 1583064000}}`)),
+		}, nil
+	}
+	restclient.ClientStruct = &getClientMock{}
+
+	//Strict mode cannot decode the numeric strings
+	_, err := WeatherProvider.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "anything", Latitude: 44.3601, Longitude: -71.0589})
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusInternalServerError, err.Code)
+
+	DecodeModes = map[string]string{DarkSky: DecodeLenient}
+	defer func() { DecodeModes = map[string]string{} }()
+	response, err := WeatherProvider.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "anything", Latitude: 44.3601, Longitude: -71.0589})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 41.5, response.Currently.Temperature)
+	assert.EqualValues(t, "Clear", response.Currently.Summary)
+	assert.EqualValues(t, []string{"humidity", "pressure", "windGust"}, response.Currently.Unavailable)
+}
+
+func TestGetWeatherLenientMissingBlock(t *testing.T) {
+	getRequestFunc = func(url string) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"latitude": 44.3601, "longitude": -71.0589, "timezone": "America/New_York", "currently": null}`)),
+		}, nil
+	}
+	restclient.ClientStruct = &getClientMock{}
+	DecodeModes = map[string]string{DarkSky: DecodeLenient}
+	defer func() { DecodeModes = map[string]string{} }()
+
+	response, err := WeatherProvider.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "anything", Latitude: 44.3601, Longitude: -71.0589})
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"dewPoint", "humidity", "pressure", "summary", "temperature", "time", "windGust", "windSpeed"}, response.Currently.Unavailable)
+
+	//Outside of the current conditions a value cannot be left out
+	getRequestFunc = func(url string) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"latitude": "north", "longitude": -71.0589, "timezone": "America/New_York"}`)),
+		}, nil
+	}
+	response, err = WeatherProvider.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "anything", Latitude: 44.3601, Longitude: -71.0589})
+	assert.Nil(t, response)
+	assert.EqualValues(t, "darksky response does not match its schema: $.latitude: expected number, got string", err.ErrorMessage)
+}
+
+func TestGetWeatherUnits(t *testing.T) {
+	var requestedUrl string
+	getRequestFunc = func(url string) (*http.Response, error) {
+		requestedUrl = url
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"latitude": 44.3601, "longitude": -71.0589, "timezone": "America/New_York", "currently": {"summary": "Clear", "temperature": 4.56}}`)),
+		}, nil
+	}
+	restclient.ClientStruct = &getClientMock{} //without this line, the real api is fired
+
+	response, err := WeatherProvider.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "anything", Latitude: 44.3601, Longitude: -71.0589, Units: "si"})
+	assert.Nil(t, err)
+	assert.NotNil(t, response)
+	assert.EqualValues(t, "https://api.darksky.net/forecast/anything/44.3601,-71.0589?units=si", requestedUrl)
+	assert.EqualValues(t, 4.56, response.Currently.Temperature)
+}
+
+func TestGetWeatherLanguage(t *testing.T) {
+	var requestedUrl string
+	getRequestFunc = func(url string) (*http.Response, error) {
+		requestedUrl = url
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"latitude": 44.3601, "longitude": -71.0589, "currently": {"summary": "Ciel couvert"}}`)),
+		}, nil
+	}
+	restclient.ClientStruct = &getClientMock{} //without this line, the real api is fired
+
+	response, err := WeatherProvider.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "anything", Latitude: 44.3601, Longitude: -71.0589, Units: "si", Lang: "fr"})
+	assert.Nil(t, err)
+	assert.EqualValues(t, "https://api.darksky.net/forecast/anything/44.3601,-71.0589?lang=fr&units=si", requestedUrl)
+	assert.EqualValues(t, "Ciel couvert", response.Currently.Summary)
+
+	_, err = WeatherProvider.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "anything", Latitude: 44.3601, Longitude: -71.0589, Lang: "xx"})
+	assert.Nil(t, err)
+	assert.EqualValues(t, "https://api.darksky.net/forecast/anything/44.3601,-71.0589", requestedUrl)
+}
+
+//When the upstream cannot be reached at all, another provider may still answer
+func TestGetWeatherUpstreamUnreachable(t *testing.T) {
+	getRequestFunc = func(url string) (*http.Response, error) {
+		return nil, errors.New("dial tcp: connection refused")
+	}
+	restclient.ClientStruct = &getClientMock{} //without this line, the real api is fired
+
+	response, err := WeatherProvider.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "anything", Latitude: 44.3601, Longitude: -71.0589})
+	assert.Nil(t, response)
+	assert.EqualValues(t, http.StatusBadGateway, err.Code)
+	assert.EqualValues(t, "dial tcp: connection refused", err.ErrorMessage)
+	assert.True(t, err.Retryable())
+}
+
+func TestGetWeatherHourly(t *testing.T) {
+	var requestedUrl string
+	getRequestFunc = func(url string) (*http.Response, error) {
+		requestedUrl = url
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: ioutil.NopCloser(strings.NewReader(`{"latitude": 44.3601, "longitude": -71.0589, "timezone": "America/New_York", "currently": {"temperature": 4.56}, ` +
+				`"hourly": {"summary": "Rain later", "icon": "rain", "data": [{"time": 1583064000, "summary": "Clear", "icon": "clear-day", "temperature": 4.56, "precipProbability": 0.1}, ` +
+				`{"time": 1583067600, "summary": "Rain", "icon": "rain", "temperature": 3.2, "precipProbability": 0.8, "precipType": "rain"}]}}`)),
+		}, nil
+	}
+	restclient.ClientStruct = &getClientMock{} //without this line, the real api is fired
+
+	request := weather_domain.WeatherRequest{ApiKey: "anything", Latitude: 44.3601, Longitude: -71.0589, Hourly: true}
+	response, err := WeatherProvider.GetWeather(context.Background(), request)
+	assert.Nil(t, err)
+	assert.EqualValues(t, "https://api.darksky.net/forecast/anything/44.3601,-71.0589?extend=hourly", requestedUrl)
+	assert.EqualValues(t, []weather_domain.HourlyInfo{
+		{Time: 1583064000, Summary: "Clear", Temperature: 4.56, PrecipProbability: 0.1},
+		{Time: 1583067600, Summary: "Rain", Temperature: 3.2, PrecipProbability: 0.8},
+	}, response.Hourly)
+
+	//The hours dark sky always sends are left out unless asked for
+	request.Hourly = false
+	response, err = WeatherProvider.GetWeather(context.Background(), request)
+	assert.Nil(t, err)
+	assert.Nil(t, response.Hourly)
+}
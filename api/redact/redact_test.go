@@ -0,0 +1,75 @@
+package redact
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestText(t *testing.T) {
+	assert.EqualValues(t, `Get "https://api.darksky.net/forecast/REDACTED/44.3,-71.1?units=si": dial tcp: i/o timeout`,
+		Text(`Get "https://api.darksky.net/forecast/secret_api_key/44.3,-71.1?units=si": dial tcp: i/o timeout`, "secret_api_key"))
+	//Escaped the way the url quotes it
+	assert.EqualValues(t, "/forecast/REDACTED/1,2", Text("/forecast/a%2Fb%20c/1,2", "a/b c"))
+	//Too short to tell apart from any other value
+	assert.EqualValues(t, "lat=1", Text("lat=1", "1"))
+}
+
+func TestTextByShape(t *testing.T) {
+	assert.EqualValues(t, "/weather/stream?lat=1&apiKey=REDACTED&client_key=REDACTED", Text("/weather/stream?lat=1&apiKey=abc123&client_key=wk_x"))
+	assert.EqualValues(t, "dial redis://:REDACTED@cache:6379/0 failed", Text("dial redis://:hunter22@cache:6379/0 failed"))
+	assert.EqualValues(t, "Authorization: Bearer REDACTED", Text("Authorization: Bearer s3cr3t-token"))
+	assert.EqualValues(t, "invalid key REDACTED", Text("invalid key wk_0123456789abcdefghijABCDEFGHIJ"))
+}
+
+func TestRegister(t *testing.T) {
+	defer func() { registered = nil }()
+	Register("admin-token-value", "")
+	assert.EqualValues(t, "token REDACTED refused", Text("token admin-token-value refused"))
+}
+
+func TestIsSensitive(t *testing.T) {
+	for _, name := range []string{"apiKey", "api_key", "client_key", "Token", "password"} {
+		assert.True(t, IsSensitive(name), name)
+	}
+	for _, name := range []string{"latitude", "units", "id"} {
+		assert.False(t, IsSensitive(name), name)
+	}
+}
+
+func TestContext(t *testing.T) {
+	ctx := WithSecrets(context.Background(), "first_secret")
+	ctx = WithSecrets(ctx, "second_secret")
+	assert.EqualValues(t, []string{"first_secret", "second_secret"}, Secrets(ctx))
+	assert.EqualValues(t, "REDACTED and REDACTED", Context(ctx, "first_secret and second_secret"))
+	assert.Empty(t, Secrets(context.Background()))
+}
+
+func TestURL(t *testing.T) {
+	url := NewURL("https://api.darksky.net/forecast/secret_api_key/1,2", "secret_api_key")
+	assert.EqualValues(t, "https://api.darksky.net/forecast/secret_api_key/1,2", url.Reveal())
+	//However it gets printed, the key stays hidden
+	for _, printed := range []string{url.String(), fmt.Sprint(url), fmt.Sprintf("%v %+v %#v %s", url, url, url, url)} {
+		assert.NotContains(t, printed, "secret_api_key")
+	}
+	_, err := http.NewRequest("GET\n", url.Reveal(), nil)
+	assert.NotContains(t, url.Error(err), "secret_api_key")
+	assert.EqualValues(t, "upstream REDACTED failed", url.Error(errors.New("upstream secret_api_key failed")))
+}
+
+func TestWriter(t *testing.T) {
+	var out bytes.Buffer
+	n, err := Writer(&out).Write([]byte("GET /weather?apiKey=abc123\n"))
+	assert.Nil(t, err)
+	assert.EqualValues(t, 27, n)
+	assert.EqualValues(t, "GET /weather?apiKey=REDACTED\n", out.String())
+
+	out.Reset()
+	Writer(&out, "abc123").Write([]byte("GET /weather/abc123/44.36/-71.05\n"))
+	assert.EqualValues(t, "GET /weather/REDACTED/44.36/-71.05\n", out.String())
+}
@@ -0,0 +1,54 @@
+package render
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"interface-testing/api/domain/weather_domain"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+//notModified sets the validators of a weather response and reports whether the copy the client already has
+//is still current, in which case a 304 was sent instead of the body.
+//The strong ETag hashes the serialized body, Last-Modified is when the weather was observed
+func notModified(c *gin.Context, weather *weather_domain.Weather, body []byte) bool {
+	sum := sha256.Sum256(body)
+	etag := `"` + base64.RawURLEncoding.EncodeToString(sum[:]) + `"`
+	c.Header("ETag", etag)
+	//The same url serializes differently per format and language
+	c.Header("Vary", "Accept, Accept-Language")
+	var lastModified time.Time
+	if weather.Currently.Time > 0 {
+		lastModified = time.Unix(weather.Currently.Time, 0).UTC()
+		c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+	}
+
+	//If-Modified-Since only counts when there is no If-None-Match
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		if !etagMatches(match, etag) {
+			return false
+		}
+	} else {
+		since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+		if err != nil || lastModified.IsZero() || lastModified.After(since) {
+			return false
+		}
+	}
+	c.Status(http.StatusNotModified)
+	c.Writer.WriteHeaderNow()
+	return true
+}
+
+//etagMatches compares the If-None-Match list weakly, as it should for GET
+func etagMatches(match string, etag string) bool {
+	for _, candidate := range strings.Split(match, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
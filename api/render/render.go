@@ -0,0 +1,219 @@
+package render
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/i18n"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/golang/protobuf/proto"
+)
+
+const (
+	mimeCSV = "text/csv"
+)
+
+var (
+	//formats maps the ?format= values to the mime type they stand for
+	formats = map[string]string{
+		"json":     binding.MIMEJSON,
+		"xml":      binding.MIMEXML,
+		"csv":      mimeCSV,
+		"protobuf": binding.MIMEPROTOBUF,
+	}
+	offered = []string{binding.MIMEJSON, binding.MIMEXML, binding.MIMEXML2, mimeCSV, binding.MIMEPROTOBUF}
+)
+
+//Render writes payload (a *weather_domain.Weather, *weather_domain.Grid, *weather_domain.Route or a weather_domain.WeatherErrorInterface) in the format
+//asked for with ?format= or the Accept header, falling back to JSON when the client has no preference
+func Render(c *gin.Context, status int, payload interface{}) {
+	lang := Language(c)
+	c.Header("Content-Language", lang)
+	if apiError, ok := payload.(weather_domain.WeatherErrorInterface); ok {
+		payload = weather_domain.NewWeatherError(apiError.Status(), i18n.Translate(lang, apiError.Message()))
+	}
+	mime := ""
+	if format := c.Query("format"); format != "" {
+		mime = formats[strings.ToLower(format)]
+	} else {
+		mime = c.NegotiateFormat(offered...)
+	}
+	contentType, body, apiError := encode(mime, payload)
+	if apiError != nil {
+		//The client cannot take any format we have, so it gets the complaint in JSON, still in its language
+		apiError = weather_domain.NewWeatherError(apiError.Status(), i18n.Translate(lang, apiError.Message()))
+		contentType, body, _ = encode(binding.MIMEJSON, apiError)
+		c.Data(apiError.Status(), contentType, body)
+		return
+	}
+	if weather, ok := payload.(*weather_domain.Weather); ok && status == http.StatusOK && notModified(c, weather, body) {
+		return
+	}
+	c.Data(status, contentType, body)
+}
+
+//encode serializes payload as mime, returning the content type to send it with
+func encode(mime string, payload interface{}) (string, []byte, weather_domain.WeatherErrorInterface) {
+	switch mime {
+	case binding.MIMEJSON:
+		body, err := json.Marshal(payload)
+		if err != nil {
+			return "", nil, weather_domain.NewWeatherError(http.StatusInternalServerError, err.Error())
+		}
+		return binding.MIMEJSON + "; charset=utf-8", body, nil
+	case binding.MIMEXML, binding.MIMEXML2:
+		body, err := xml.Marshal(payload)
+		if err != nil {
+			return "", nil, weather_domain.NewWeatherError(http.StatusInternalServerError, err.Error())
+		}
+		return binding.MIMEXML + "; charset=utf-8", body, nil
+	case mimeCSV:
+		var records [][]string
+		var err error
+		//A grid or a route is one row per cell or point rather than one row with all of them in a column
+		switch value := payload.(type) {
+		case *weather_domain.Grid:
+			records, err = flattenRows(value.Cells)
+		case *weather_domain.Route:
+			records, err = flattenRows(value.Points)
+		default:
+			records, err = flatten(payload)
+		}
+		if err != nil {
+			return "", nil, weather_domain.NewWeatherError(http.StatusInternalServerError, err.Error())
+		}
+		var body bytes.Buffer
+		writer := csv.NewWriter(&body)
+		writer.WriteAll(records)
+		return mimeCSV + "; charset=utf-8", body.Bytes(), nil
+	case binding.MIMEPROTOBUF:
+		var message proto.Message
+		switch value := payload.(type) {
+		case *weather_domain.Weather:
+			message = value.Proto()
+		case *weather_domain.Grid:
+			message = value.Proto()
+		case *weather_domain.Route:
+			message = value.Proto()
+		case *weather_domain.WeatherError:
+			message = value.Proto()
+		default:
+			return "", nil, weather_domain.NewNotAcceptableError("response cannot be rendered as protobuf")
+		}
+		body, err := proto.Marshal(message)
+		if err != nil {
+			return "", nil, weather_domain.NewWeatherError(http.StatusInternalServerError, err.Error())
+		}
+		return binding.MIMEPROTOBUF, body, nil
+	default:
+		return "", nil, weather_domain.NewNotAcceptableError(fmt.Sprintf("supported formats are %s", strings.Join(offered, ", ")))
+	}
+}
+
+//Abort renders apiError and stops the handler chain, for the middlewares refusing a request
+func Abort(c *gin.Context, apiError weather_domain.WeatherErrorInterface) {
+	c.Abort()
+	Render(c, apiError.Status(), apiError)
+}
+
+//Language is the ?lang= parameter or else the best Accept-Language match we have a catalog for
+func Language(c *gin.Context) string {
+	return i18n.Resolve(c.Query("lang"), c.GetHeader("Accept-Language"))
+}
+
+//flatten turns a struct into a CSV header and a single row, naming nested fields "parent.child" after their json tags.
+//Nil sub-structs still get their columns, left empty, so the header does not depend on the data
+func flatten(payload interface{}) ([][]string, error) {
+	var header, row []string
+	var walk func(prefix string, value reflect.Value, blank bool) error
+	walk = func(prefix string, value reflect.Value, blank bool) error {
+		if value.Kind() == reflect.Interface && !value.IsNil() {
+			value = value.Elem()
+		}
+		for value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				blank = true
+				value = reflect.Zero(value.Type().Elem())
+			} else {
+				value = value.Elem()
+			}
+		}
+		//Times and such are one column, in the text they marshal to
+		if marshaler, ok := value.Interface().(encoding.TextMarshaler); ok && value.Kind() == reflect.Struct {
+			header = append(header, prefix)
+			if blank {
+				row = append(row, "")
+				return nil
+			}
+			text, err := marshaler.MarshalText()
+			if err != nil {
+				return err
+			}
+			row = append(row, string(text))
+			return nil
+		}
+		if value.Kind() != reflect.Struct {
+			header = append(header, prefix)
+			switch {
+			case blank || ((value.Kind() == reflect.Interface || value.Kind() == reflect.Slice || value.Kind() == reflect.Map) && value.IsNil()):
+				row = append(row, "")
+			case value.Kind() == reflect.Slice || value.Kind() == reflect.Map || value.Kind() == reflect.Array:
+				encoded, err := json.Marshal(value.Interface())
+				if err != nil {
+					return err
+				}
+				row = append(row, string(encoded))
+			default:
+				row = append(row, fmt.Sprint(value.Interface()))
+			}
+			return nil
+		}
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if field.PkgPath != "" || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			if prefix != "" {
+				name = prefix + "." + name
+			}
+			if err := walk(name, value.Field(i), blank); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk("", reflect.ValueOf(payload), false); err != nil {
+		return nil, err
+	}
+	return [][]string{header, row}, nil
+}
+
+//flattenRows flattens every element of a slice under a single header
+func flattenRows(elements interface{}) ([][]string, error) {
+	var records [][]string
+	slice := reflect.ValueOf(elements)
+	for i := 0; i < slice.Len(); i++ {
+		flat, err := flatten(slice.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		if records == nil {
+			records = append(records, flat[0])
+		}
+		records = append(records, flat[1])
+	}
+	return records, nil
+}
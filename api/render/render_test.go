@@ -0,0 +1,46 @@
+package render
+
+import (
+	"interface-testing/api/domain/weather_domain"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAbortNegotiatesAndTranslates(t *testing.T) {
+	reached := false
+	router := gin.New()
+	router.GET("/", func(c *gin.Context) {
+		Abort(c, weather_domain.NewForbiddenError("permission denied"))
+	}, func(c *gin.Context) {
+		reached = true
+	})
+
+	response := httptest.NewRecorder()
+	request, _ := http.NewRequest(http.MethodGet, "/?format=xml&lang=es", nil)
+	router.ServeHTTP(response, request)
+	assert.False(t, reached)
+	assert.EqualValues(t, http.StatusForbidden, response.Code)
+	assert.EqualValues(t, "es", response.Header().Get("Content-Language"))
+	assert.EqualValues(t, "<WeatherError><code>403</code><error>Permiso denegado</error></WeatherError>", response.Body.String())
+}
+
+func TestRenderNotAcceptableFallsBackToJson(t *testing.T) {
+	response := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(response)
+	c.Request, _ = http.NewRequest(http.MethodGet, "/", nil)
+	c.Request.Header.Set("Accept", "application/yaml")
+	c.Request.Header.Set("Accept-Language", "de")
+	Render(c, http.StatusOK, &weather_domain.Weather{})
+	assert.EqualValues(t, http.StatusNotAcceptable, response.Code)
+	assert.Contains(t, response.Header().Get("Content-Type"), "application/json")
+	assert.EqualValues(t, "de", response.Header().Get("Content-Language"))
+	apiErr, err := weather_domain.NewApiErrFromBytes(response.Body.Bytes())
+	assert.Nil(t, err)
+	assert.EqualValues(t, http.StatusNotAcceptable, apiErr.Status())
+	assert.True(t, strings.HasPrefix(apiErr.Message(), "Unterstützte Formate sind application/json"))
+}
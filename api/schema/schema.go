@@ -0,0 +1,404 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//The kinds of drift Check reports
+const (
+	UnknownField = "unknown_field"
+	MissingField = "missing_field"
+	TypeMismatch = "type_mismatch"
+)
+
+//Drift is a difference between a response and the schema, Path is a JSON path such as "$.currently.temperature"
+type Drift struct {
+	Path     string
+	Kind     string
+	Expected string
+	Actual   string
+}
+
+//anyIndex matches the array indexes of a path
+var anyIndex = regexp.MustCompile(`\[\d+\]`)
+
+//Schema describes an upstream response by the Go type it is decoded into
+type Schema struct {
+	target reflect.Type
+	//required are the paths a response must have
+	required []string
+	//known are paths the upstream sends that target does not decode on purpose
+	known map[string]bool
+}
+
+func (d Drift) String() string {
+	switch d.Kind {
+	case UnknownField:
+		return fmt.Sprintf("%s: unknown field", d.Path)
+	case MissingField:
+		return fmt.Sprintf("%s: missing field", d.Path)
+	default:
+		return fmt.Sprintf("%s: expected %s, got %s", d.Path, d.Expected, d.Actual)
+	}
+}
+
+//New describes responses decoded into target, a value or pointer of the type. required and known are JSON paths,
+//a known path can match every element of an array with "[]", such as "$.hourly.data[].icon"
+func New(target interface{}, required []string, known []string) *Schema {
+	schema := &Schema{target: reflect.TypeOf(target), required: required, known: make(map[string]bool)}
+	for _, path := range known {
+		schema.known[path] = true
+	}
+	return schema
+}
+
+//Check compares body to the schema, it only fails when body is not JSON at all
+func (s *Schema) Check(body []byte) ([]Drift, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	var drifts []Drift
+	s.walk("$", value, s.target, &drifts)
+	for _, path := range s.required {
+		if !present(value, path) {
+			drifts = append(drifts, Drift{Path: path, Kind: MissingField})
+		}
+	}
+	return drifts, nil
+}
+
+func (s *Schema) walk(path string, value interface{}, target reflect.Type, drifts *[]Drift) {
+	nullable := target.Kind() == reflect.Ptr || target.Kind() == reflect.Interface || target.Kind() == reflect.Slice || target.Kind() == reflect.Map
+	for target.Kind() == reflect.Ptr {
+		target = target.Elem()
+	}
+	if value == nil {
+		//null decodes to the zero value, which silently hides a value that went missing, unless the field can be nil
+		if !nullable {
+			*drifts = append(*drifts, Drift{Path: path, Kind: TypeMismatch, Expected: kindOf(target), Actual: "null"})
+		}
+		return
+	}
+	mismatch := func() {
+		*drifts = append(*drifts, Drift{Path: path, Kind: TypeMismatch, Expected: kindOf(target), Actual: jsonKind(value)})
+	}
+	switch target.Kind() {
+	case reflect.Interface:
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			mismatch()
+			return
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fieldPath := path + "." + name
+			field, ok := fieldByJsonName(target, name)
+			if !ok {
+				if !s.known[fieldPath] && !s.known[anyIndex.ReplaceAllString(fieldPath, "[]")] {
+					*drifts = append(*drifts, Drift{Path: fieldPath, Kind: UnknownField})
+				}
+				continue
+			}
+			s.walk(fieldPath, object[name], field.Type, drifts)
+		}
+	case reflect.Map:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			mismatch()
+			return
+		}
+		for name, element := range object {
+			s.walk(path+"."+name, element, target.Elem(), drifts)
+		}
+	case reflect.Slice, reflect.Array:
+		array, ok := value.([]interface{})
+		if !ok {
+			mismatch()
+			return
+		}
+		for i, element := range array {
+			s.walk(fmt.Sprintf("%s[%d]", path, i), element, target.Elem(), drifts)
+		}
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			mismatch()
+		}
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			mismatch()
+		}
+	case reflect.Float32, reflect.Float64:
+		if _, ok := value.(json.Number); !ok {
+			mismatch()
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		number, ok := value.(json.Number)
+		if !ok {
+			mismatch()
+		} else if _, err := number.Int64(); err != nil {
+			*drifts = append(*drifts, Drift{Path: path, Kind: TypeMismatch, Expected: "integer", Actual: "number"})
+		}
+	}
+}
+
+//fieldByJsonName finds the field encoding/json would decode name into: an exact match first, then ignoring case
+func fieldByJsonName(target reflect.Type, name string) (reflect.StructField, bool) {
+	var folded *reflect.StructField
+	for i := 0; i < target.NumField(); i++ {
+		field := target.Field(i)
+		tag := strings.Split(field.Tag.Get("json"), ",")[0]
+		if field.PkgPath != "" || tag == "-" {
+			continue
+		}
+		if tag == "" {
+			tag = field.Name
+		}
+		if tag == name {
+			return field, true
+		}
+		if folded == nil && strings.EqualFold(tag, name) {
+			folded = &field
+		}
+	}
+	if folded != nil {
+		return *folded, true
+	}
+	return reflect.StructField{}, false
+}
+
+//present tells whether value has a non null field at path, a dotted path without array indexes
+func present(value interface{}, path string) bool {
+	for _, segment := range strings.Split(strings.TrimPrefix(path, "$."), ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if value, ok = object[segment]; !ok || value == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func kindOf(target reflect.Type) string {
+	switch target.Kind() {
+	case reflect.Struct, reflect.Map:
+		return "object"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	default:
+		return "number"
+	}
+}
+
+func jsonKind(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	default:
+		return "null"
+	}
+}
+
+//Coerce rewrites body so it decodes into the schema type without failing: numeric strings become numbers, while
+//nulls and values of another type are dropped. It returns the paths of the dropped values, along with the paths
+//of the fields missing from blocks, objects such as "$.currently" whose every field is expected
+func (s *Schema) Coerce(body []byte, blocks ...string) ([]byte, []string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, nil, err
+	}
+	var dropped []string
+	value, _ = coerce("$", value, s.target, &dropped)
+	for _, block := range blocks {
+		target, ok := typeAt(s.target, block)
+		if !ok || target.Kind() != reflect.Struct {
+			continue
+		}
+		object, _ := valueAt(value, block).(map[string]interface{})
+		for i := 0; i < target.NumField(); i++ {
+			field := target.Field(i)
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if field.PkgPath != "" || name == "-" || field.Type.Kind() == reflect.Slice || field.Type.Kind() == reflect.Ptr {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			path := block + "." + name
+			if _, present := object[name]; !present && !contains(dropped, path) {
+				dropped = append(dropped, path)
+			}
+		}
+	}
+	sort.Strings(dropped)
+	coerced, err := json.Marshal(value)
+	return coerced, dropped, err
+}
+
+//coerce returns value fixed up for target, or false when it has to be dropped
+func coerce(path string, value interface{}, target reflect.Type, dropped *[]string) (interface{}, bool) {
+	for target.Kind() == reflect.Ptr {
+		target = target.Elem()
+	}
+	drop := func() (interface{}, bool) {
+		*dropped = append(*dropped, path)
+		return nil, false
+	}
+	if value == nil {
+		switch target.Kind() {
+		case reflect.Interface, reflect.Slice, reflect.Map:
+			return value, true
+		}
+		return drop()
+	}
+	switch target.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return drop()
+		}
+		for name, element := range object {
+			field, ok := fieldByJsonName(target, name)
+			if !ok {
+				continue
+			}
+			if fixed, keep := coerce(path+"."+name, element, field.Type, dropped); keep {
+				object[name] = fixed
+			} else {
+				delete(object, name)
+			}
+		}
+		return object, true
+	case reflect.Map:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return drop()
+		}
+		for name, element := range object {
+			if fixed, keep := coerce(path+"."+name, element, target.Elem(), dropped); keep {
+				object[name] = fixed
+			} else {
+				delete(object, name)
+			}
+		}
+		return object, true
+	case reflect.Slice, reflect.Array:
+		array, ok := value.([]interface{})
+		if !ok {
+			return drop()
+		}
+		kept := make([]interface{}, 0, len(array))
+		for i, element := range array {
+			if fixed, keep := coerce(fmt.Sprintf("%s[%d]", path, i), element, target.Elem(), dropped); keep {
+				kept = append(kept, fixed)
+			}
+		}
+		return kept, true
+	case reflect.String:
+		if _, ok := value.(string); !ok {
+			return drop()
+		}
+	case reflect.Bool:
+		if _, ok := value.(bool); !ok {
+			return drop()
+		}
+	case reflect.Float32, reflect.Float64:
+		return coerceNumber(value, false, drop)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return coerceNumber(value, true, drop)
+	}
+	return value, true
+}
+
+func coerceNumber(value interface{}, integer bool, drop func() (interface{}, bool)) (interface{}, bool) {
+	number, ok := value.(json.Number)
+	if text, isString := value.(string); isString {
+		number, ok = json.Number(strings.TrimSpace(text)), true
+	}
+	if !ok {
+		return drop()
+	}
+	if integer {
+		if _, err := number.Int64(); err != nil {
+			return drop()
+		}
+	} else if _, err := number.Float64(); err != nil {
+		return drop()
+	}
+	return number, true
+}
+
+//typeAt is the type of the field at path, a dotted path without array indexes
+func typeAt(target reflect.Type, path string) (reflect.Type, bool) {
+	for _, segment := range strings.Split(strings.TrimPrefix(path, "$."), ".") {
+		for target.Kind() == reflect.Ptr {
+			target = target.Elem()
+		}
+		if target.Kind() != reflect.Struct {
+			return nil, false
+		}
+		field, ok := fieldByJsonName(target, segment)
+		if !ok {
+			return nil, false
+		}
+		target = field.Type
+	}
+	for target.Kind() == reflect.Ptr {
+		target = target.Elem()
+	}
+	return target, true
+}
+
+func valueAt(value interface{}, path string) interface{} {
+	for _, segment := range strings.Split(strings.TrimPrefix(path, "$."), ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[segment]
+	}
+	return value
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
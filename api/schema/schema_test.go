@@ -0,0 +1,87 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testCurrently struct {
+	Temperature float64 `json:"temperature"`
+	Summary     string  `json:"summary"`
+	Time        int64   `json:"time"`
+}
+
+type testWeather struct {
+	Latitude  float64         `json:"latitude"`
+	TimeZone  string          `json:"timezone"`
+	Currently testCurrently   `json:"currently"`
+	Flags     *struct{}       `json:"flags,omitempty"`
+	Alerts    []testCurrently `json:"alerts"`
+	Ignored   string          `json:"-"`
+}
+
+var testSchema = New(testWeather{}, []string{"$.latitude", "$.currently", "$.currently.temperature"}, []string{"$.offset"})
+
+func TestCheckMatchingResponse(t *testing.T) {
+	drifts, err := testSchema.Check([]byte(`{"latitude": 44.3, "timezone": "America/New_York", "offset": -5, "flags": null, "alerts": null,
+		"currently": {"temperature": 40.22, "summary": "Clear", "time": 1583064000}}`))
+	assert.Nil(t, err)
+	assert.Empty(t, drifts)
+}
+
+func TestCheckUnknownFields(t *testing.T) {
+	drifts, _ := testSchema.Check([]byte(`{"latitude": 44.3, "currently": {"temperature": 40.22, "uvIndex": 3, "Summary": "Clear"}, "Ignored": "x"}`))
+	assert.EqualValues(t, []Drift{
+		{Path: "$.Ignored", Kind: UnknownField},
+		{Path: "$.currently.uvIndex", Kind: UnknownField},
+	}, drifts)
+}
+
+func TestCheckMissingFields(t *testing.T) {
+	drifts, _ := testSchema.Check([]byte(`{"latitude": null, "timezone": "UTC"}`))
+	assert.EqualValues(t, []Drift{
+		{Path: "$.latitude", Kind: TypeMismatch, Expected: "number", Actual: "null"},
+		{Path: "$.latitude", Kind: MissingField},
+		{Path: "$.currently", Kind: MissingField},
+		{Path: "$.currently.temperature", Kind: MissingField},
+	}, drifts)
+}
+
+func TestCheckTypeMismatches(t *testing.T) {
+	drifts, _ := testSchema.Check([]byte(`{"latitude": "44.3", "timezone": 5, "currently": {"temperature": 40.22, "time": 1.5},
+		"alerts": [{"summary": true}], "flags": []}`))
+	assert.EqualValues(t, []Drift{
+		{Path: "$.alerts[0].summary", Kind: TypeMismatch, Expected: "string", Actual: "boolean"},
+		{Path: "$.currently.time", Kind: TypeMismatch, Expected: "integer", Actual: "number"},
+		{Path: "$.flags", Kind: TypeMismatch, Expected: "object", Actual: "array"},
+		{Path: "$.latitude", Kind: TypeMismatch, Expected: "number", Actual: "string"},
+		{Path: "$.timezone", Kind: TypeMismatch, Expected: "string", Actual: "number"},
+	}, drifts)
+	assert.EqualValues(t, "$.latitude: expected number, got string", drifts[3].String())
+}
+
+func TestCheckInvalidJson(t *testing.T) {
+	_, err := testSchema.Check([]byte(`{"latitude": `))
+	assert.NotNil(t, err)
+}
+
+func TestCoerce(t *testing.T) {
+	coerced, dropped, err := testSchema.Coerce([]byte(`{"latitude": "44.3", "timezone": null, "alerts": [{"temperature": "x"}],
+		"currently": {"temperature": " 40.22 ", "summary": 3, "time": "1583064000.5"}}`), "$.currently")
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"latitude": 44.3, "alerts": [{}], "currently": {"temperature": 40.22}}`, string(coerced))
+	assert.EqualValues(t, []string{"$.alerts[0].temperature", "$.currently.summary", "$.currently.time", "$.timezone"}, dropped)
+}
+
+func TestCoerceMissingBlock(t *testing.T) {
+	_, dropped, err := testSchema.Coerce([]byte(`{"latitude": 44.3}`), "$.currently")
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"$.currently.summary", "$.currently.temperature", "$.currently.time"}, dropped)
+
+	_, dropped, _ = testSchema.Coerce([]byte(`{"latitude": 44.3, "currently": "none"}`), "$.currently")
+	assert.EqualValues(t, []string{"$.currently", "$.currently.summary", "$.currently.temperature", "$.currently.time"}, dropped)
+
+	_, _, err = testSchema.Coerce([]byte(`{"latitude":`))
+	assert.NotNil(t, err)
+}
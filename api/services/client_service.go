@@ -0,0 +1,179 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"interface-testing/api/domain/client_domain"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/stores/client_store"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	clientKeyPrefix = "wk_"
+)
+
+type clientService struct{}
+
+type clientServiceInterface interface {
+	Create(request client_domain.ClientRequest) (*client_domain.IssuedKey, weather_domain.WeatherErrorInterface)
+	List() []client_domain.Client
+	//Rotate issues a new key to the client, the previous one stops working right away
+	Rotate(id string) (*client_domain.IssuedKey, weather_domain.WeatherErrorInterface)
+	Revoke(id string) (*client_domain.Client, weather_domain.WeatherErrorInterface)
+	//Authenticate returns the client a key was issued to, as long as it is neither revoked nor expired
+	Authenticate(key string) (*client_domain.Client, weather_domain.WeatherErrorInterface)
+}
+
+var (
+	ClientService clientServiceInterface = &clientService{}
+
+	errClientRevoked = errors.New("client is revoked")
+)
+
+func (s *clientService) Create(request client_domain.ClientRequest) (*client_domain.IssuedKey, weather_domain.WeatherErrorInterface) {
+	request.Owner = strings.TrimSpace(request.Owner)
+	if request.Owner == "" {
+		return nil, weather_domain.NewBadRequestError("owner is required")
+	}
+	if request.Tier == "" {
+		request.Tier = client_domain.TierFree
+	}
+	if !client_domain.Tiers[request.Tier] {
+		return nil, weather_domain.NewBadRequestError(fmt.Sprintf("unknown tier %s", request.Tier))
+	}
+	now := time.Now().UTC()
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(now) {
+		return nil, weather_domain.NewBadRequestError("expiresAt must be in the future")
+	}
+	id, err := randomToken(8)
+	if err != nil {
+		return nil, keyError(err)
+	}
+	client := client_domain.Client{
+		Id:        hex.EncodeToString(id),
+		Owner:     request.Owner,
+		Tier:      request.Tier,
+		CreatedAt: now,
+		ExpiresAt: request.ExpiresAt,
+	}
+	return issue(client)
+}
+
+func (s *clientService) List() []client_domain.Client {
+	return client_store.ClientStore.List()
+}
+
+//Rotate and Revoke change the stored client in place rather than saving a copy of it, so a rotation
+//running alongside a revocation cannot bring the key back to life
+func (s *clientService) Rotate(id string) (*client_domain.IssuedKey, weather_domain.WeatherErrorInterface) {
+	key, err := newKey()
+	if err != nil {
+		return nil, keyError(err)
+	}
+	client, err := client_store.ClientStore.Update(id, func(client *client_domain.Client) error {
+		if client.Revoked() {
+			return errClientRevoked
+		}
+		now := time.Now().UTC()
+		client.RotatedAt = &now
+		setKey(client, key)
+		return nil
+	})
+	switch err {
+	case nil:
+		return &client_domain.IssuedKey{Client: *client, Key: key}, nil
+	case client_store.ErrNotFound:
+		return nil, weather_domain.NewNotFoundError(fmt.Sprintf("client %s not found", id))
+	case errClientRevoked:
+		return nil, weather_domain.NewBadRequestError(fmt.Sprintf("client %s is revoked", id))
+	default:
+		return nil, keyError(err)
+	}
+}
+
+func (s *clientService) Revoke(id string) (*client_domain.Client, weather_domain.WeatherErrorInterface) {
+	client, err := client_store.ClientStore.Update(id, func(client *client_domain.Client) error {
+		if !client.Revoked() {
+			now := time.Now().UTC()
+			client.RevokedAt = &now
+		}
+		return nil
+	})
+	switch err {
+	case nil:
+		return client, nil
+	case client_store.ErrNotFound:
+		return nil, weather_domain.NewNotFoundError(fmt.Sprintf("client %s not found", id))
+	default:
+		return nil, keyError(err)
+	}
+}
+
+func (s *clientService) Authenticate(key string) (*client_domain.Client, weather_domain.WeatherErrorInterface) {
+	if key == "" {
+		return nil, weather_domain.NewUnauthorizedError("api key is required")
+	}
+	client, ok := client_store.ClientStore.GetByKeyHash(hashKey(key))
+	if !ok {
+		return nil, weather_domain.NewUnauthorizedError("invalid api key")
+	}
+	if client.Revoked() {
+		return nil, weather_domain.NewUnauthorizedError("api key revoked")
+	}
+	if client.Expired(time.Now()) {
+		return nil, weather_domain.NewUnauthorizedError("api key expired")
+	}
+	return client, nil
+}
+
+//issue gives client a new key and saves it with the hash of that key
+func issue(client client_domain.Client) (*client_domain.IssuedKey, weather_domain.WeatherErrorInterface) {
+	key, err := newKey()
+	if err != nil {
+		return nil, keyError(err)
+	}
+	setKey(&client, key)
+	if err := client_store.ClientStore.Save(client); err != nil {
+		return nil, keyError(err)
+	}
+	return &client_domain.IssuedKey{Client: client, Key: key}, nil
+}
+
+func newKey() (string, error) {
+	secret, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	return clientKeyPrefix + base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+//setKey keeps the hash of key, to recognize it, and its first characters, to tell keys apart in listings
+func setKey(client *client_domain.Client, key string) {
+	client.KeyHash = hashKey(key)
+	client.KeyPrefix = key[:len(clientKeyPrefix)+6]
+}
+
+//hashKey needs no salt nor slow hash: the keys are random 256 bit values, not passwords someone could guess
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomToken(size int) ([]byte, error) {
+	token := make([]byte, size)
+	_, err := rand.Read(token)
+	return token, err
+}
+
+func keyError(err error) weather_domain.WeatherErrorInterface {
+	log.Println(fmt.Sprintf("error when trying to store client key: %s", err.Error()))
+	return weather_domain.NewWeatherError(http.StatusInternalServerError, "client keys are unavailable")
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"interface-testing/api/domain/client_domain"
+	"interface-testing/api/stores/client_store"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func emptyClientStore() {
+	client_store.ClientStore, _ = client_store.NewFileStore("")
+}
+
+func TestClientServiceCreateAndAuthenticate(t *testing.T) {
+	emptyClientStore()
+	issued, err := ClientService.Create(client_domain.ClientRequest{Owner: "mobile team"})
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(issued.Key, "wk_"))
+	assert.True(t, strings.HasPrefix(issued.Key, issued.KeyPrefix))
+	assert.EqualValues(t, client_domain.TierFree, issued.Tier)
+	assert.NotEqual(t, issued.Key, issued.KeyHash)
+
+	client, err := ClientService.Authenticate(issued.Key)
+	assert.Nil(t, err)
+	assert.EqualValues(t, issued.Id, client.Id)
+	assert.EqualValues(t, "mobile team", client.Owner)
+
+	_, err = ClientService.Authenticate("wk_guessed")
+	assert.EqualValues(t, http.StatusUnauthorized, err.Status())
+	assert.EqualValues(t, "invalid api key", err.Message())
+	_, err = ClientService.Authenticate("")
+	assert.EqualValues(t, "api key is required", err.Message())
+}
+
+func TestClientServiceCreateInvalid(t *testing.T) {
+	emptyClientStore()
+	_, err := ClientService.Create(client_domain.ClientRequest{Owner: " "})
+	assert.EqualValues(t, "owner is required", err.Message())
+	_, err = ClientService.Create(client_domain.ClientRequest{Owner: "web", Tier: "gold"})
+	assert.EqualValues(t, "unknown tier gold", err.Message())
+	past := time.Now().Add(-time.Hour)
+	_, err = ClientService.Create(client_domain.ClientRequest{Owner: "web", ExpiresAt: &past})
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+	assert.EqualValues(t, 0, len(ClientService.List()))
+}
+
+func TestClientServiceRotate(t *testing.T) {
+	emptyClientStore()
+	issued, _ := ClientService.Create(client_domain.ClientRequest{Owner: "web", Tier: client_domain.TierStandard})
+	rotated, err := ClientService.Rotate(issued.Id)
+	assert.Nil(t, err)
+	assert.EqualValues(t, issued.Id, rotated.Id)
+	assert.EqualValues(t, client_domain.TierStandard, rotated.Tier)
+	assert.NotNil(t, rotated.RotatedAt)
+	assert.NotEqual(t, issued.Key, rotated.Key)
+
+	_, err = ClientService.Authenticate(issued.Key)
+	assert.EqualValues(t, http.StatusUnauthorized, err.Status())
+	_, err = ClientService.Authenticate(rotated.Key)
+	assert.Nil(t, err)
+
+	_, err = ClientService.Rotate("unknown")
+	assert.EqualValues(t, http.StatusNotFound, err.Status())
+}
+
+func TestClientServiceRevoke(t *testing.T) {
+	emptyClientStore()
+	issued, _ := ClientService.Create(client_domain.ClientRequest{Owner: "web"})
+	client, err := ClientService.Revoke(issued.Id)
+	assert.Nil(t, err)
+	assert.NotNil(t, client.RevokedAt)
+
+	_, err = ClientService.Authenticate(issued.Key)
+	assert.EqualValues(t, "api key revoked", err.Message())
+	_, err = ClientService.Rotate(issued.Id)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+	//Revoked clients stay listed
+	assert.EqualValues(t, 1, len(ClientService.List()))
+}
+
+func TestClientServiceRotateRevokeRace(t *testing.T) {
+	for i := 0; i < 200; i++ {
+		emptyClientStore()
+		issued, _ := ClientService.Create(client_domain.ClientRequest{Owner: "web"})
+		var wg sync.WaitGroup
+		wg.Add(2)
+		start := make(chan struct{})
+		var rotated *client_domain.IssuedKey
+		go func() {
+			defer wg.Done()
+			<-start
+			rotated, _ = ClientService.Rotate(issued.Id)
+		}()
+		go func() {
+			defer wg.Done()
+			<-start
+			ClientService.Revoke(issued.Id)
+		}()
+		close(start)
+		wg.Wait()
+
+		//Whichever ran first, the client ends up revoked and no key of it works
+		client := ClientService.List()[0]
+		assert.NotNil(t, client.RevokedAt)
+		_, err := ClientService.Authenticate(issued.Key)
+		assert.NotNil(t, err)
+		if rotated != nil {
+			_, err = ClientService.Authenticate(rotated.Key)
+			assert.EqualValues(t, "api key revoked", err.Message())
+		}
+	}
+}
+
+func TestClientServiceExpiredKey(t *testing.T) {
+	emptyClientStore()
+	soon := time.Now().Add(20 * time.Millisecond)
+	issued, err := ClientService.Create(client_domain.ClientRequest{Owner: "web", ExpiresAt: &soon})
+	assert.Nil(t, err)
+	_, err = ClientService.Authenticate(issued.Key)
+	assert.Nil(t, err)
+
+	time.Sleep(30 * time.Millisecond)
+	_, err = ClientService.Authenticate(issued.Key)
+	assert.EqualValues(t, "api key expired", err.Message())
+}
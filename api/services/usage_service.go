@@ -0,0 +1,76 @@
+package services
+
+import (
+	"fmt"
+	"interface-testing/api/domain/client_domain"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/stores/client_store"
+	"interface-testing/api/stores/usage_store"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type usageService struct {
+	//mu makes checking and counting a request one step, so concurrent requests cannot overshoot a quota
+	mu sync.Mutex
+}
+
+type usageServiceInterface interface {
+	//Allow counts a request of client against its monthly quota and refuses it with a 429 once the quota is used up.
+	//It returns the quota, 0 when unlimited, and how many requests the client made this month
+	Allow(client *client_domain.Client) (int64, int64, weather_domain.WeatherErrorInterface)
+	//AllowN is Allow for n requests at once, such as the points of a grid. They are refused together when they
+	//do not all fit in what is left of the quota
+	AllowN(client *client_domain.Client, n int64) (int64, int64, weather_domain.WeatherErrorInterface)
+	//Served records whether a request of the client was answered by the provider or from the cache
+	Served(clientId string, cache *weather_domain.CacheInfo)
+	//Export returns the usage of every client between the days from and to included, either can be empty
+	Export(from string, to string) []client_domain.UsageRecord
+}
+
+var (
+	UsageService usageServiceInterface = &usageService{}
+)
+
+func (s *usageService) Allow(client *client_domain.Client) (int64, int64, weather_domain.WeatherErrorInterface) {
+	return s.AllowN(client, 1)
+}
+
+func (s *usageService) AllowN(client *client_domain.Client, n int64) (int64, int64, weather_domain.WeatherErrorInterface) {
+	now := time.Now().UTC()
+	quota := client_domain.MonthlyQuotas[client.Tier]
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	used := usage_store.UsageStore.Month(client.Id, now.Format(client_domain.MonthFormat)).Requests
+	if quota > 0 && used+n > quota {
+		resets := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC).Format(client_domain.DayFormat)
+		if used >= quota {
+			return quota, used, weather_domain.NewWeatherError(http.StatusTooManyRequests,
+				fmt.Sprintf("monthly quota of %d requests exceeded, it resets on %s", quota, resets))
+		}
+		return quota, used, weather_domain.NewWeatherError(http.StatusTooManyRequests,
+			fmt.Sprintf("%d requests exceed the %d left of the monthly quota of %d requests, it resets on %s", n, quota-used, quota, resets))
+	}
+	usage_store.UsageStore.Add(client.Id, now.Format(client_domain.DayFormat), client_domain.Usage{Requests: n})
+	return quota, used + n, nil
+}
+
+func (s *usageService) Served(clientId string, cache *weather_domain.CacheInfo) {
+	delta := client_domain.Usage{UpstreamCalls: 1}
+	if cache != nil && cache.Status != weather_domain.CacheMiss {
+		delta = client_domain.Usage{CacheHits: 1}
+	}
+	usage_store.UsageStore.Add(clientId, time.Now().UTC().Format(client_domain.DayFormat), delta)
+}
+
+func (s *usageService) Export(from string, to string) []client_domain.UsageRecord {
+	records := usage_store.UsageStore.List(from, to)
+	for i := range records {
+		if client, ok := client_store.ClientStore.Get(records[i].ClientId); ok {
+			records[i].Owner = client.Owner
+			records[i].Tier = client.Tier
+		}
+	}
+	return records
+}
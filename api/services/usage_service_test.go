@@ -0,0 +1,96 @@
+package services
+
+import (
+	"fmt"
+	"interface-testing/api/domain/client_domain"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/stores/usage_store"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsageServiceQuota(t *testing.T) {
+	usage_store.UsageStore, _ = usage_store.NewFileStore("")
+	defer func(quota int64) { client_domain.MonthlyQuotas[client_domain.TierFree] = quota }(client_domain.MonthlyQuotas[client_domain.TierFree])
+	client_domain.MonthlyQuotas[client_domain.TierFree] = 2
+	client := &client_domain.Client{Id: "abc", Tier: client_domain.TierFree}
+
+	quota, used, err := UsageService.Allow(client)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, quota)
+	assert.EqualValues(t, 1, used)
+	_, used, err = UsageService.Allow(client)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, used)
+
+	_, used, err = UsageService.Allow(client)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusTooManyRequests, err.Status())
+	now := time.Now().UTC()
+	resets := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+	assert.EqualValues(t, fmt.Sprintf("monthly quota of 2 requests exceeded, it resets on %s", resets), err.Message())
+	//Refused requests are not counted
+	assert.EqualValues(t, 2, used)
+	assert.EqualValues(t, 2, usage_store.UsageStore.Month("abc", now.Format("2006-01")).Requests)
+}
+
+func TestUsageServiceAllowN(t *testing.T) {
+	usage_store.UsageStore, _ = usage_store.NewFileStore("")
+	defer func(quota int64) { client_domain.MonthlyQuotas[client_domain.TierFree] = quota }(client_domain.MonthlyQuotas[client_domain.TierFree])
+	client_domain.MonthlyQuotas[client_domain.TierFree] = 10
+	client := &client_domain.Client{Id: "abc", Tier: client_domain.TierFree}
+
+	_, used, err := UsageService.AllowN(client, 8)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 8, used)
+
+	//3 more do not fit in the 2 left, none of them is counted
+	_, used, err = UsageService.AllowN(client, 3)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusTooManyRequests, err.Status())
+	assert.Contains(t, err.Message(), "3 requests exceed the 2 left of the monthly quota of 10 requests, it resets on ")
+	assert.EqualValues(t, 8, used)
+
+	_, used, err = UsageService.AllowN(client, 2)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 10, used)
+}
+
+func TestUsageServiceUnlimitedTier(t *testing.T) {
+	usage_store.UsageStore, _ = usage_store.NewFileStore("")
+	client := &client_domain.Client{Id: "abc", Tier: client_domain.TierEnterprise}
+	for i := 0; i < 3; i++ {
+		quota, _, err := UsageService.Allow(client)
+		assert.Nil(t, err)
+		assert.EqualValues(t, 0, quota)
+	}
+}
+
+func TestUsageServiceServedAndExport(t *testing.T) {
+	usage_store.UsageStore, _ = usage_store.NewFileStore("")
+	emptyClientStore()
+	issued, _ := ClientService.Create(client_domain.ClientRequest{Owner: "mobile team", Tier: client_domain.TierStandard})
+
+	UsageService.Served(issued.Id, &weather_domain.CacheInfo{Status: weather_domain.CacheMiss})
+	UsageService.Served(issued.Id, nil)
+	UsageService.Served(issued.Id, &weather_domain.CacheInfo{Status: weather_domain.CacheHit})
+	UsageService.Served(issued.Id, &weather_domain.CacheInfo{Status: weather_domain.CacheStale})
+	UsageService.Served("deleted", &weather_domain.CacheInfo{Status: weather_domain.CacheHit})
+
+	records := UsageService.Export("", "")
+	assert.EqualValues(t, 2, len(records))
+	for _, record := range records {
+		if record.ClientId == issued.Id {
+			assert.EqualValues(t, "mobile team", record.Owner)
+			assert.EqualValues(t, client_domain.TierStandard, record.Tier)
+			assert.EqualValues(t, 2, record.UpstreamCalls)
+			assert.EqualValues(t, 2, record.CacheHits)
+		} else {
+			assert.EqualValues(t, "", record.Owner)
+		}
+	}
+	assert.EqualValues(t, 0, len(UsageService.Export("2000-01-01", "2000-01-31")))
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/providers/weather_provider"
+	"interface-testing/api/redact"
+	"interface-testing/api/tracing"
+	"log"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//withAirQuality fetches the weather and the air quality of request at the same time. The air quality is an extra:
+//when it fails or takes longer than a provider may, the weather is served without it
+func withAirQuality(ctx context.Context, request weather_domain.WeatherRequest, fetch func() (*weather_domain.Weather, *weather_domain.WeatherError)) (*weather_domain.Weather, *weather_domain.WeatherError) {
+	var airQuality *weather_domain.AirQualityInfo
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		airCtx, span := tracing.Tracer().Start(ctx, "provider "+weather_provider.OpenMeteoAirQuality,
+			trace.WithAttributes(attribute.String("weather.provider", weather_provider.OpenMeteoAirQuality)))
+		defer span.End()
+		airCtx, cancel := context.WithTimeout(airCtx, weather_provider.DefaultProviderTimeout)
+		defer cancel()
+		var err *weather_domain.WeatherError
+		if airQuality, err = weather_provider.AirQualityProvider.GetAirQuality(airCtx, request); err != nil {
+			span.SetStatus(codes.Error, redact.Context(ctx, err.ErrorMessage))
+			log.Println(fmt.Sprintf("error when trying to get air quality, serving the weather without it: %s", redact.Context(ctx, err.ErrorMessage)))
+		}
+	}()
+	weather, err := fetch()
+	<-done
+	if err != nil {
+		return nil, err
+	}
+	//The provider may hand out the same response twice, the air quality goes on a copy
+	result := *weather
+	result.AirQuality = airQuality
+	return &result, nil
+}
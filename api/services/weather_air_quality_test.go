@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/providers/weather_provider"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var getAirQualityFunc func(request weather_domain.WeatherRequest) (*weather_domain.AirQualityInfo, *weather_domain.WeatherError)
+
+type airQualityProviderMock struct{}
+
+func (m *airQualityProviderMock) GetAirQuality(ctx context.Context, request weather_domain.WeatherRequest) (*weather_domain.AirQualityInfo, *weather_domain.WeatherError) {
+	return getAirQualityFunc(request)
+}
+
+func airQualityReturning(airQuality *weather_domain.AirQualityInfo, err *weather_domain.WeatherError) *int32 {
+	var calls int32
+	getAirQualityFunc = func(request weather_domain.WeatherRequest) (*weather_domain.AirQualityInfo, *weather_domain.WeatherError) {
+		atomic.AddInt32(&calls, 1)
+		return airQuality, err
+	}
+	weather_provider.AirQualityProvider = &airQualityProviderMock{}
+	return &calls
+}
+
+func TestWeatherServiceAirQuality(t *testing.T) {
+	providerReturning(10)
+	pm25 := 12.0
+	calls := airQualityReturning(&weather_domain.AirQualityInfo{
+		Pollutants: weather_domain.PollutantsInfo{PM25: &pm25},
+		Aqi:        weather_domain.NewAqiInfo(weather_domain.PollutantsInfo{PM25: &pm25}),
+	}, nil)
+
+	request := cacheRequest
+	request.AirQuality = true
+	result, err := WeatherService.GetWeather(context.Background(), request)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 10, result.Currently.Temperature)
+	assert.NotNil(t, result.AirQuality)
+	assert.EqualValues(t, 56, result.AirQuality.Aqi.Epa.Value)
+	assert.EqualValues(t, 1, atomic.LoadInt32(calls))
+}
+
+func TestWeatherServiceAirQualityNotRequested(t *testing.T) {
+	providerReturning(10)
+	calls := airQualityReturning(&weather_domain.AirQualityInfo{}, nil)
+
+	result, err := WeatherService.GetWeather(context.Background(), cacheRequest)
+	assert.Nil(t, err)
+	assert.Nil(t, result.AirQuality)
+	assert.EqualValues(t, 0, atomic.LoadInt32(calls))
+}
+
+func TestWeatherServiceAirQualityFails(t *testing.T) {
+	providerReturning(10)
+	airQualityReturning(nil, &weather_domain.WeatherError{Code: http.StatusBadGateway, ErrorMessage: "unavailable"})
+
+	request := cacheRequest
+	request.AirQuality = true
+	result, err := WeatherService.GetWeather(context.Background(), request)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 10, result.Currently.Temperature)
+	assert.Nil(t, result.AirQuality)
+}
+
+func TestWeatherServiceAirQualityWeatherFails(t *testing.T) {
+	providerReturning()
+	airQualityReturning(&weather_domain.AirQualityInfo{}, nil)
+
+	request := cacheRequest
+	request.AirQuality = true
+	result, err := WeatherService.GetWeather(context.Background(), request)
+	assert.Nil(t, result)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusServiceUnavailable, err.Status())
+}
+
+func TestCacheKeyAirQuality(t *testing.T) {
+	request := cacheRequest
+	request.AirQuality = true
+	assert.NotEqual(t, cacheKey(cacheRequest), cacheKey(request))
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"interface-testing/api/cache"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/metrics"
+	"sync"
+	"time"
+)
+
+type fetchFunc func() (*weather_domain.Weather, *weather_domain.WeatherError)
+
+//refresher makes sure only one background refresh runs per cache key
+type refresher struct {
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+var (
+	//A response is fresh for CacheTTL. Once expired it is still served for StaleWhileRevalidate while a
+	//background refresh runs, and for StaleIfError when the provider fails. A zero CacheTTL disables the cache
+	CacheTTL             = 5 * time.Minute
+	StaleWhileRevalidate = 5 * time.Minute
+	StaleIfError         = time.Hour
+
+	cacheLookups   = metrics.NewCounterVec("weather_cache_lookups_total", "Weather cache lookups by how they were served.", "status")
+	cacheRefreshes = metrics.NewCounter("weather_cache_refreshes_total", "Background refreshes of stale weather cache entries.")
+
+	refreshing = &refresher{running: make(map[string]bool)}
+)
+
+//cachedWeather serves request from the weather cache when it can and fetches it otherwise
+func cachedWeather(request weather_domain.WeatherRequest, fetch fetchFunc) (*weather_domain.Weather, *weather_domain.CacheInfo, *weather_domain.WeatherError) {
+	if CacheTTL <= 0 {
+		response, err := fetch()
+		return response, &weather_domain.CacheInfo{Status: weather_domain.CacheMiss, FetchedAt: time.Now()}, err
+	}
+	key := cacheKey(request)
+	entry, found := cache.WeatherCache.Get(key)
+	var age time.Duration
+	if found {
+		age = time.Since(entry.StoredAt)
+		if age < CacheTTL {
+			cacheLookups.With(weather_domain.CacheHit).Inc()
+			return entry.Weather, &weather_domain.CacheInfo{Status: weather_domain.CacheHit, Age: age, MaxAge: CacheTTL - age, FetchedAt: entry.StoredAt}, nil
+		}
+		if age < CacheTTL+StaleWhileRevalidate {
+			refreshing.start(key, fetch)
+			cacheLookups.With(weather_domain.CacheStale).Inc()
+			return entry.Weather, &weather_domain.CacheInfo{Status: weather_domain.CacheStale, Age: age, FetchedAt: entry.StoredAt}, nil
+		}
+	}
+
+	response, err := fetch()
+	if err == nil {
+		storedAt := store(key, response)
+		cacheLookups.With(weather_domain.CacheMiss).Inc()
+		return response, &weather_domain.CacheInfo{Status: weather_domain.CacheMiss, MaxAge: CacheTTL, FetchedAt: storedAt}, nil
+	}
+	//Client errors, such as a wrong api key, are not the provider failing and must reach the caller
+	if found && err.Retryable() && age < CacheTTL+StaleIfError {
+		cacheLookups.With(weather_domain.CacheStaleIfError).Inc()
+		return entry.Weather, &weather_domain.CacheInfo{Status: weather_domain.CacheStaleIfError, Age: age, FetchedAt: entry.StoredAt}, nil
+	}
+	return nil, nil, err
+}
+
+//cacheKey identifies the response of a request, the api key is hashed so it is never stored in clear
+func cacheKey(request weather_domain.WeatherRequest) string {
+	key := fmt.Sprintf("%x:%g:%g:%s:%s:%s", sha256.Sum256([]byte(request.ApiKey)), request.Latitude, request.Longitude,
+		request.Units, request.Lang, request.Ensemble)
+	//Suffixed rather than inserted, so that the entries cached before hourly forecasts existed stay valid
+	if request.Hourly {
+		key += ":hourly"
+	}
+	if request.AirQuality {
+		key += ":air"
+	}
+	return key
+}
+
+//store caches response and returns when it was stored, which is when it was fetched
+func store(key string, response *weather_domain.Weather) time.Time {
+	retention := StaleWhileRevalidate
+	if StaleIfError > retention {
+		retention = StaleIfError
+	}
+	storedAt := time.Now()
+	cache.WeatherCache.Set(key, &cache.Entry{Weather: response, StoredAt: storedAt}, CacheTTL+retention)
+	return storedAt
+}
+
+//start refreshes key in the background unless a refresh of it is already running
+func (r *refresher) start(key string, fetch fetchFunc) {
+	r.mu.Lock()
+	if r.running[key] {
+		r.mu.Unlock()
+		return
+	}
+	r.running[key] = true
+	r.mu.Unlock()
+
+	cacheRefreshes.Inc()
+	go func() {
+		defer func() {
+			r.mu.Lock()
+			delete(r.running, key)
+			r.mu.Unlock()
+		}()
+		if response, err := fetch(); err == nil {
+			store(key, response)
+		}
+	}()
+}
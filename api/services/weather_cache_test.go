@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"interface-testing/api/cache"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/providers/weather_provider"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//The other tests of the package give the same request different provider answers, so only the cache tests cache
+func TestMain(m *testing.M) {
+	CacheTTL = 0
+	os.Exit(m.Run())
+}
+
+//withCache runs a test against an empty cache with the given freshness and staleness windows
+func withCache(ttl time.Duration, staleWhileRevalidate time.Duration, staleIfError time.Duration) func() {
+	CacheTTL, StaleWhileRevalidate, StaleIfError = ttl, staleWhileRevalidate, staleIfError
+	cache.WeatherCache = cache.NewMemoryCache()
+	return func() { CacheTTL = 0 }
+}
+
+var cacheRequest = weather_domain.WeatherRequest{ApiKey: "api_key", Latitude: 51.5, Longitude: -0.12}
+
+func providerReturning(temperatures ...float64) *int32 {
+	var calls int32
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		call := atomic.AddInt32(&calls, 1)
+		if int(call) > len(temperatures) {
+			return nil, &weather_domain.WeatherError{Code: http.StatusServiceUnavailable, ErrorMessage: "unavailable"}
+		}
+		return &weather_domain.Weather{Currently: weather_domain.CurrentlyInfo{Temperature: temperatures[call-1]}}, nil
+	}
+	weather_provider.WeatherProvider = &getProviderMock{}
+	return &calls
+}
+
+func TestWeatherServiceCacheHit(t *testing.T) {
+	defer withCache(time.Minute, time.Minute, time.Minute)()
+	calls := providerReturning(10, 20)
+
+	result, err := WeatherService.GetWeather(context.Background(), cacheRequest)
+	assert.Nil(t, err)
+	assert.EqualValues(t, weather_domain.CacheMiss, result.Cache.Status)
+	assert.EqualValues(t, time.Minute, result.Cache.MaxAge)
+	result, err = WeatherService.GetWeather(context.Background(), cacheRequest)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 10, result.Currently.Temperature)
+	assert.EqualValues(t, weather_domain.CacheHit, result.Cache.Status)
+	assert.EqualValues(t, time.Minute, result.Cache.Age+result.Cache.MaxAge)
+	assert.EqualValues(t, 1, atomic.LoadInt32(calls))
+
+	//Another api key must not be answered from this key's entry
+	_, err = WeatherService.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "other_key", Latitude: 51.5, Longitude: -0.12})
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(calls))
+}
+
+//Without an observation time from the upstream, the local time is the one of the fetch and not of the rendering,
+//so that a cached response has the same body, and so the same ETag, every time
+func TestWeatherServiceCacheKeepsObservedTime(t *testing.T) {
+	defer withCache(time.Minute, time.Minute, time.Minute)()
+	providerReturning(10)
+
+	fetched, err := WeatherService.GetWeather(context.Background(), cacheRequest)
+	assert.Nil(t, err)
+	assert.EqualValues(t, weather_domain.CacheMiss, fetched.Cache.Status)
+	assert.True(t, time.Unix(fetched.Cache.FetchedAt.Unix(), 0).Equal(fetched.LocalTime.ObservedUtc))
+
+	storedAt := time.Now().Add(-30 * time.Second)
+	cache.WeatherCache.Set(cacheKey(cacheRequest), &cache.Entry{Weather: &weather_domain.Weather{Currently: weather_domain.CurrentlyInfo{Temperature: 10}}, StoredAt: storedAt}, time.Hour)
+	first, err := WeatherService.GetWeather(context.Background(), cacheRequest)
+	assert.Nil(t, err)
+	second, err := WeatherService.GetWeather(context.Background(), cacheRequest)
+	assert.Nil(t, err)
+	assert.EqualValues(t, weather_domain.CacheHit, second.Cache.Status)
+	assert.True(t, time.Unix(storedAt.Unix(), 0).Equal(first.LocalTime.ObservedUtc), "%s", first.LocalTime.ObservedUtc)
+	assert.EqualValues(t, first.LocalTime, second.LocalTime)
+	assert.EqualValues(t, first.Astronomy, second.Astronomy)
+}
+
+func TestWeatherServiceStaleWhileRevalidate(t *testing.T) {
+	defer withCache(20*time.Millisecond, time.Minute, 0)()
+	calls := providerReturning(10, 20)
+
+	WeatherService.GetWeather(context.Background(), cacheRequest)
+	time.Sleep(30 * time.Millisecond)
+
+	//The expired entry is served right away and refreshed in the background
+	result, err := WeatherService.GetWeather(context.Background(), cacheRequest)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 10, result.Currently.Temperature)
+	assert.EqualValues(t, weather_domain.CacheStale, result.Cache.Status)
+	assert.True(t, result.Cache.Age >= 20*time.Millisecond)
+
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(calls) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	result, err = WeatherService.GetWeather(context.Background(), cacheRequest)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 20, result.Currently.Temperature)
+	assert.EqualValues(t, weather_domain.CacheHit, result.Cache.Status)
+}
+
+func TestWeatherServiceStaleIfError(t *testing.T) {
+	defer withCache(10*time.Millisecond, 0, time.Minute)()
+	calls := providerReturning(10)
+
+	WeatherService.GetWeather(context.Background(), cacheRequest)
+	time.Sleep(20 * time.Millisecond)
+
+	result, err := WeatherService.GetWeather(context.Background(), cacheRequest)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 10, result.Currently.Temperature)
+	assert.EqualValues(t, weather_domain.CacheStaleIfError, result.Cache.Status)
+	assert.EqualValues(t, 2, atomic.LoadInt32(calls))
+}
+
+func TestWeatherServiceStaleIfErrorSkipsClientErrors(t *testing.T) {
+	defer withCache(10*time.Millisecond, 0, time.Minute)()
+	providerReturning(10)
+	WeatherService.GetWeather(context.Background(), cacheRequest)
+	time.Sleep(20 * time.Millisecond)
+
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		return nil, &weather_domain.WeatherError{Code: http.StatusForbidden, ErrorMessage: "permission denied"}
+	}
+	result, err := WeatherService.GetWeather(context.Background(), cacheRequest)
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusForbidden, err.Status())
+}
+
+func TestWeatherServiceStaleTooLong(t *testing.T) {
+	defer withCache(10*time.Millisecond, 0, 10*time.Millisecond)()
+	providerReturning(10)
+	WeatherService.GetWeather(context.Background(), cacheRequest)
+	time.Sleep(30 * time.Millisecond)
+
+	result, err := WeatherService.GetWeather(context.Background(), cacheRequest)
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusServiceUnavailable, err.Status())
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/metrics"
+	"sync"
+)
+
+//inflightCall is an upstream call that identical requests arriving meanwhile wait for instead of making their own
+type inflightCall struct {
+	done     chan struct{}
+	response *weather_domain.Weather
+	err      *weather_domain.WeatherError
+}
+
+type coalescer struct {
+	mu    sync.Mutex
+	calls map[weather_domain.WeatherRequest]*inflightCall
+}
+
+var (
+	upstreamRequests  = metrics.NewCounter("weather_upstream_requests_total", "Weather requests sent to the provider.")
+	coalescedRequests = metrics.NewCounter("weather_coalesced_requests_total", "Weather requests that shared an identical in-flight provider call.")
+
+	inflight = &coalescer{calls: make(map[weather_domain.WeatherRequest]*inflightCall)}
+)
+
+//do runs fetch for request unless the same request is already in flight, in which case it waits
+//for that call and returns its result or error. The result is shared, so callers must not modify it
+func (c *coalescer) do(request weather_domain.WeatherRequest, fetch func() (*weather_domain.Weather, *weather_domain.WeatherError)) (*weather_domain.Weather, *weather_domain.WeatherError) {
+	c.mu.Lock()
+	if call, ok := c.calls[request]; ok {
+		c.mu.Unlock()
+		coalescedRequests.Inc()
+		<-call.done
+		return call.response, call.err
+	}
+	call := &inflightCall{done: make(chan struct{})}
+	c.calls[request] = call
+	c.mu.Unlock()
+
+	upstreamRequests.Inc()
+	defer func() {
+		c.mu.Lock()
+		delete(c.calls, request)
+		c.mu.Unlock()
+		close(call.done)
+	}()
+	call.response, call.err = fetch()
+	return call.response, call.err
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/providers/weather_provider"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//callConcurrently fires n identical requests and only lets the provider answer once all of them have joined the first call
+func callConcurrently(t *testing.T, n int, request weather_domain.WeatherRequest, release chan struct{}) ([]*weather_domain.Weather, []weather_domain.WeatherErrorInterface) {
+	coalescedBefore := coalescedRequests.Value()
+	results := make([]*weather_domain.Weather, n)
+	errs := make([]weather_domain.WeatherErrorInterface, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = WeatherService.GetWeather(context.Background(), request)
+		}(i)
+	}
+	deadline := time.Now().Add(time.Second)
+	for coalescedRequests.Value()-coalescedBefore < int64(n-1) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+	assert.EqualValues(t, n-1, coalescedRequests.Value()-coalescedBefore)
+	return results, errs
+}
+
+func TestWeatherServiceCoalescesIdenticalRequests(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return &weather_domain.Weather{Latitude: request.Latitude, Longitude: request.Longitude, Currently: weather_domain.CurrentlyInfo{Summary: "Clear"}}, nil
+	}
+	weather_provider.WeatherProvider = &getProviderMock{}
+	upstreamBefore := upstreamRequests.Value()
+
+	results, errs := callConcurrently(t, 10, weather_domain.WeatherRequest{ApiKey: "api_key", Latitude: 39.12, Longitude: 49.12}, release)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+	assert.EqualValues(t, 1, upstreamRequests.Value()-upstreamBefore)
+	for i := range results {
+		assert.Nil(t, errs[i])
+		assert.EqualValues(t, "Clear", results[i].Currently.Summary)
+	}
+	//Every caller gets a copy it is free to change
+	results[0].Currently.Summary = "changed"
+	assert.EqualValues(t, "Clear", results[1].Currently.Summary)
+}
+
+func TestWeatherServiceCoalescesErrors(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return nil, &weather_domain.WeatherError{Code: http.StatusForbidden, ErrorMessage: "permission denied"}
+	}
+	weather_provider.WeatherProvider = &getProviderMock{}
+
+	results, errs := callConcurrently(t, 5, weather_domain.WeatherRequest{ApiKey: "wrong_key", Latitude: 39.12, Longitude: 49.12}, release)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+	for i := range results {
+		assert.Nil(t, results[i])
+		assert.EqualValues(t, http.StatusForbidden, errs[i].Status())
+		assert.EqualValues(t, "permission denied", errs[i].Message())
+	}
+}
+
+func TestWeatherServiceDoesNotCoalesceDifferentRequests(t *testing.T) {
+	var calls int32
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		atomic.AddInt32(&calls, 1)
+		return &weather_domain.Weather{Latitude: request.Latitude, Longitude: request.Longitude}, nil
+	}
+	weather_provider.WeatherProvider = &getProviderMock{}
+
+	first, _ := WeatherService.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "api_key", Latitude: 1, Longitude: 2})
+	second, _ := WeatherService.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "api_key", Latitude: 1, Longitude: 2, Units: "si"})
+	assert.EqualValues(t, 2, atomic.LoadInt32(&calls))
+	assert.EqualValues(t, 1, first.Latitude)
+	assert.EqualValues(t, 1, second.Latitude)
+}
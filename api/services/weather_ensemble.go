@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/providers/weather_provider"
+	"net/http"
+)
+
+const (
+	EnsembleProviderName = "ensemble"
+)
+
+//getEnsemble asks every provider and merges their answers. Providers pick different units for "auto",
+//so an ensemble asks all of them for si instead
+func getEnsemble(ctx context.Context, request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+	units := request.Units
+	if units == "auto" {
+		units = weather_domain.UnitsSI
+		request.Units = units
+	}
+	if units == "" {
+		units = weather_domain.UnitsUS
+	}
+	members := weather_provider.EnsembleProvider.GetWeathers(ctx, request)
+	merged, spread, ok := weather_domain.MergeEnsemble(members, request.Ensemble)
+	if !ok {
+		//Every provider failed: a client error such as a bad location is more telling than an outage
+		var err *weather_domain.WeatherError
+		for _, member := range members {
+			if member.Error != nil && (err == nil || (err.Retryable() && !member.Error.Retryable())) {
+				err = member.Error
+			}
+		}
+		if err == nil {
+			err = &weather_domain.WeatherError{Code: http.StatusBadGateway, ErrorMessage: "no weather provider is configured"}
+		}
+		return nil, err
+	}
+	timeZone := ""
+	for _, member := range members {
+		if member.TimeZone != "" {
+			timeZone = member.TimeZone
+			break
+		}
+	}
+	return &weather_domain.Weather{
+		Latitude:  request.Latitude,
+		Longitude: request.Longitude,
+		TimeZone:  timeZone,
+		Currently: merged,
+		Flags:     &weather_domain.FlagsInfo{Units: units},
+		Provider:  EnsembleProviderName,
+		Ensemble: &weather_domain.EnsembleInfo{
+			Method:    request.Ensemble,
+			Spread:    spread,
+			Providers: members,
+		},
+	}, nil
+}
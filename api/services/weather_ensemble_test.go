@@ -0,0 +1,64 @@
+package services
+
+import (
+	"context"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/providers/weather_provider"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	getWeathersFunc func(request weather_domain.WeatherRequest) []weather_domain.EnsembleMember
+)
+
+type ensembleProviderMock struct{}
+
+func (e *ensembleProviderMock) GetWeathers(ctx context.Context, request weather_domain.WeatherRequest) []weather_domain.EnsembleMember {
+	return getWeathersFunc(request)
+}
+
+func TestWeatherServiceEnsemble(t *testing.T) {
+	var asked weather_domain.WeatherRequest
+	getWeathersFunc = func(request weather_domain.WeatherRequest) []weather_domain.EnsembleMember {
+		asked = request
+		return []weather_domain.EnsembleMember{
+			{Provider: "darksky", Weight: 1, TimeZone: "Europe/Madrid", Currently: &weather_domain.CurrentlyInfo{Temperature: 20, Summary: "Clear", Humidity: 0.4}},
+			{Provider: "openmeteo", Weight: 1, TimeZone: "Europe/Madrid", Currently: &weather_domain.CurrentlyInfo{Temperature: 22, Summary: "Clear", Humidity: 0.5}},
+		}
+	}
+	weather_provider.EnsembleProvider = &ensembleProviderMock{}
+
+	request := weather_domain.WeatherRequest{ApiKey: "api_key", Latitude: 40.41, Longitude: -3.7, Units: "auto", Lang: "es", Ensemble: weather_domain.EnsembleMean}
+	result, err := WeatherService.GetWeather(context.Background(), request)
+	assert.Nil(t, err)
+	//Every provider is asked for the same units, so their values can be merged
+	assert.EqualValues(t, weather_domain.UnitsSI, asked.Units)
+	assert.EqualValues(t, 40.41, result.Latitude)
+	assert.EqualValues(t, "Europe/Madrid", result.TimeZone)
+	assert.EqualValues(t, 21, result.Currently.Temperature)
+	assert.EqualValues(t, "Despejado", result.Currently.Summary)
+	assert.EqualValues(t, weather_domain.UnitsSI, result.Flags.Units)
+	assert.EqualValues(t, EnsembleProviderName, result.Provider)
+	assert.NotNil(t, result.Comfort)
+	assert.EqualValues(t, weather_domain.EnsembleMean, result.Ensemble.Method)
+	assert.EqualValues(t, weather_domain.RangeInfo{Min: 20, Max: 22}, result.Ensemble.Spread.Temperature)
+	assert.EqualValues(t, 2, len(result.Ensemble.Providers))
+}
+
+func TestWeatherServiceEnsembleAllFail(t *testing.T) {
+	getWeathersFunc = func(request weather_domain.WeatherRequest) []weather_domain.EnsembleMember {
+		return []weather_domain.EnsembleMember{
+			{Provider: "darksky", Error: &weather_domain.WeatherError{Code: http.StatusBadGateway, ErrorMessage: "unreachable"}},
+			{Provider: "openmeteo", Error: &weather_domain.WeatherError{Code: http.StatusBadRequest, ErrorMessage: "The given location is invalid"}},
+		}
+	}
+	weather_provider.EnsembleProvider = &ensembleProviderMock{}
+
+	result, err := WeatherService.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "api_key", Latitude: 123, Longitude: -3.7, Ensemble: weather_domain.EnsembleMedian})
+	assert.Nil(t, result)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+	assert.EqualValues(t, "The given location is invalid", err.Message())
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type weatherGridService struct{}
+
+type weatherGridServiceInterface interface {
+	GetGrid(ctx context.Context, request weather_domain.GridRequest) (*weather_domain.Grid, weather_domain.WeatherErrorInterface)
+}
+
+var (
+	WeatherGridService weatherGridServiceInterface = &weatherGridService{}
+
+	//MaxGridPoints caps the points of one grid, every point the cache does not have costs an upstream call
+	MaxGridPoints = 100
+	//GridConcurrency is how many points of one grid are fetched at the same time
+	GridConcurrency = 4
+)
+
+//GetGrid fetches every point of the grid through the WeatherService. A point that fails is reported in its cell, the grid only fails when every point did
+func (s *weatherGridService) GetGrid(ctx context.Context, request weather_domain.GridRequest) (*weather_domain.Grid, weather_domain.WeatherErrorInterface) {
+	if err := validateGrid(request); err != nil {
+		return nil, err
+	}
+	grid := weather_domain.NewGrid(request)
+	if err := chargePoints(request.Client, len(grid.Cells)); err != nil {
+		return nil, err
+	}
+	ctx, span := tracing.Tracer().Start(ctx, "WeatherGridService.GetGrid", trace.WithAttributes(
+		attribute.Int("weather.grid.rows", grid.Rows), attribute.Int("weather.grid.columns", grid.Columns)))
+	defer span.End()
+
+	requests := make([]weather_domain.WeatherRequest, len(grid.Cells))
+	for i, cell := range grid.Cells {
+		requests[i] = request.Request
+		requests[i].Latitude, requests[i].Longitude = cell.Latitude, cell.Longitude
+	}
+	weathers, errs := getPoints(ctx, requests, GridConcurrency)
+	for i := range grid.Cells {
+		grid.Cells[i].Weather, grid.Cells[i].Error = weathers[i], errs[i]
+	}
+
+	for _, cell := range grid.Cells {
+		if cell.Error == nil {
+			return grid, nil
+		}
+	}
+	return nil, grid.Cells[0].Error
+}
+
+func validateGrid(request weather_domain.GridRequest) weather_domain.WeatherErrorInterface {
+	//Written as negations so that NaN fails them too
+	switch {
+	case !(request.Resolution > 0):
+		return weather_domain.NewBadRequestError("resolution must be a positive number of degrees")
+	case !(request.South >= -90 && request.North <= 90 && request.West >= -180 && request.East <= 180):
+		return weather_domain.NewBadRequestError("bounding box is out of range")
+	case request.South > request.North:
+		return weather_domain.NewBadRequestError("south must not be north of north")
+	case request.West > request.East:
+		return weather_domain.NewBadRequestError("west must not be east of east, boxes across the antimeridian are not supported")
+	}
+	rows, columns := request.GridSize()
+	if rows*columns > float64(MaxGridPoints) {
+		return weather_domain.NewBadRequestError(fmt.Sprintf("grid of %.0f x %.0f points exceeds the limit of %d points, use a coarser resolution or a smaller box",
+			rows, columns, MaxGridPoints))
+	}
+	return nil
+}
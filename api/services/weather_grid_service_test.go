@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"interface-testing/api/domain/client_domain"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/providers/weather_provider"
+	"interface-testing/api/stores/usage_store"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var gridRequest = weather_domain.GridRequest{
+	Request: weather_domain.WeatherRequest{ApiKey: "api_key", Units: weather_domain.UnitsSI},
+	South:   40, West: -74, North: 41, East: -72, Resolution: 1,
+}
+
+func TestWeatherGridService(t *testing.T) {
+	var mu sync.Mutex
+	var requests []weather_domain.WeatherRequest
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		mu.Lock()
+		requests = append(requests, request)
+		mu.Unlock()
+		if request.Longitude == -73 && request.Latitude == 41 {
+			return nil, &weather_domain.WeatherError{Code: http.StatusBadGateway, ErrorMessage: "upstream failed"}
+		}
+		return &weather_domain.Weather{Latitude: request.Latitude, Longitude: request.Longitude, Currently: weather_domain.CurrentlyInfo{Temperature: request.Latitude}}, nil
+	}
+	weather_provider.WeatherProvider = &getProviderMock{}
+
+	grid, err := WeatherGridService.GetGrid(context.Background(), gridRequest)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, grid.Rows)
+	assert.EqualValues(t, 3, grid.Columns)
+	assert.Len(t, requests, 6)
+	for _, request := range requests {
+		assert.EqualValues(t, "api_key", request.ApiKey)
+		assert.EqualValues(t, weather_domain.UnitsSI, request.Units)
+	}
+	assert.EqualValues(t, 40, grid.Cells[0].Weather.Currently.Temperature)
+	//A failed point is reported in its cell without failing the grid
+	assert.Nil(t, grid.Cells[4].Weather)
+	assert.EqualValues(t, http.StatusBadGateway, grid.Cells[4].Error.Code)
+	assert.EqualValues(t, "upstream failed", grid.Cells[4].Error.ErrorMessage)
+}
+
+func TestWeatherGridServiceConcurrencyLimit(t *testing.T) {
+	defer func(concurrency int) { GridConcurrency = concurrency }(GridConcurrency)
+	GridConcurrency = 2
+	var running, most int32
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		now := atomic.AddInt32(&running, 1)
+		for {
+			seen := atomic.LoadInt32(&most)
+			if now <= seen || atomic.CompareAndSwapInt32(&most, seen, now) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return &weather_domain.Weather{}, nil
+	}
+	weather_provider.WeatherProvider = &getProviderMock{}
+
+	_, err := WeatherGridService.GetGrid(context.Background(), gridRequest)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&most))
+}
+
+func TestWeatherGridServiceUsesCache(t *testing.T) {
+	defer withCache(time.Minute, 0, 0)()
+	calls := providerReturning(1, 2, 3, 4, 5, 6, 7, 8)
+
+	_, err := WeatherGridService.GetGrid(context.Background(), gridRequest)
+	assert.Nil(t, err)
+	//An overlapping grid only asks the upstream for the points it does not share
+	overlapping := gridRequest
+	overlapping.West, overlapping.East = -73, -71
+	grid, err := WeatherGridService.GetGrid(context.Background(), overlapping)
+	assert.Nil(t, err)
+	assert.EqualValues(t, weather_domain.CacheHit, grid.Cells[0].Weather.Cache.Status)
+	assert.EqualValues(t, weather_domain.CacheMiss, grid.Cells[2].Weather.Cache.Status)
+	assert.EqualValues(t, 8, atomic.LoadInt32(calls))
+}
+
+func TestWeatherGridServiceAllFail(t *testing.T) {
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		return nil, &weather_domain.WeatherError{Code: http.StatusForbidden, ErrorMessage: "permission denied"}
+	}
+	weather_provider.WeatherProvider = &getProviderMock{}
+
+	grid, err := WeatherGridService.GetGrid(context.Background(), gridRequest)
+	assert.Nil(t, grid)
+	assert.EqualValues(t, http.StatusForbidden, err.Status())
+}
+
+func TestWeatherGridServiceLimits(t *testing.T) {
+	defer func(points int) { MaxGridPoints = points }(MaxGridPoints)
+	MaxGridPoints = 5
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		t.Fatal("a refused grid must not reach the upstream")
+		return nil, nil
+	}
+	weather_provider.WeatherProvider = &getProviderMock{}
+
+	_, err := WeatherGridService.GetGrid(context.Background(), gridRequest)
+	assert.EqualValues(t, http.StatusBadRequest, err.Status())
+	assert.EqualValues(t, "grid of 2 x 3 points exceeds the limit of 5 points, use a coarser resolution or a smaller box", err.Message())
+
+	for _, invalid := range []weather_domain.GridRequest{
+		{South: 40, West: -74, North: 41, East: -72, Resolution: 0},
+		{South: 41, West: -74, North: 40, East: -72, Resolution: 1},
+		{South: 40, West: 170, North: 41, East: -170, Resolution: 1},
+		{South: -91, West: -74, North: 41, East: -72, Resolution: 1},
+		{South: 40, West: -74, North: 41, East: -72, Resolution: 1e-300},
+	} {
+		_, err := WeatherGridService.GetGrid(context.Background(), invalid)
+		assert.EqualValues(t, http.StatusBadRequest, err.Status(), invalid)
+	}
+}
+
+func TestWeatherGridServiceChargesEveryPoint(t *testing.T) {
+	usage_store.UsageStore, _ = usage_store.NewFileStore("")
+	defer func(quota int64) { client_domain.MonthlyQuotas[client_domain.TierFree] = quota }(client_domain.MonthlyQuotas[client_domain.TierFree])
+	client_domain.MonthlyQuotas[client_domain.TierFree] = 10
+	client := &client_domain.Client{Id: "abc", Tier: client_domain.TierFree}
+	month := time.Now().UTC().Format("2006-01")
+	var calls int32
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		atomic.AddInt32(&calls, 1)
+		return &weather_domain.Weather{Latitude: request.Latitude, Longitude: request.Longitude}, nil
+	}
+	weather_provider.WeatherProvider = &getProviderMock{}
+
+	//The middleware counted the request, the grid adds the other 5 of its 6 points
+	UsageService.Allow(client)
+	request := gridRequest
+	request.Client = client
+	_, err := WeatherGridService.GetGrid(context.Background(), request)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 6, usage_store.UsageStore.Month("abc", month).Requests)
+
+	//4 requests are left, not enough for another grid, which is refused before any point is fetched
+	atomic.StoreInt32(&calls, 0)
+	UsageService.Allow(client)
+	_, err = WeatherGridService.GetGrid(context.Background(), request)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusTooManyRequests, err.Status())
+	assert.Contains(t, err.Message(), "5 requests exceed the 3 left of the monthly quota of 10 requests")
+	assert.EqualValues(t, 0, atomic.LoadInt32(&calls))
+	assert.EqualValues(t, 7, usage_store.UsageStore.Month("abc", month).Requests)
+}
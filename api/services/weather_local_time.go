@@ -0,0 +1,23 @@
+package services
+
+import (
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/timezone"
+	"time"
+)
+
+//localTime places the observation of weather in its time zone. When the provider sent no zone, or one that is not
+//an IANA name, such as "Local", the zone is looked up from the coordinates and replaces it in weather
+func localTime(weather *weather_domain.Weather, observed time.Time) *weather_domain.LocalTimeInfo {
+	derived := false
+	location, err := timezone.Location(weather.TimeZone)
+	if err != nil {
+		weather.TimeZone, derived = timezone.Lookup(weather.Latitude, weather.Longitude), true
+		if location, err = timezone.Location(weather.TimeZone); err != nil {
+			return nil
+		}
+	}
+	localTime := weather_domain.NewLocalTimeInfo(observed, location)
+	localTime.TimeZoneDerived = derived
+	return &localTime
+}
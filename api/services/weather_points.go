@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"interface-testing/api/domain/client_domain"
+	"interface-testing/api/domain/weather_domain"
+	"net/http"
+	"sync"
+)
+
+//getPoints asks the WeatherService for every request, at most concurrency at a time, so that the points share its
+//cache and coalescing. Each request gets either a weather or an error, at the same index
+func getPoints(ctx context.Context, requests []weather_domain.WeatherRequest, concurrency int) ([]*weather_domain.Weather, []*weather_domain.WeatherError) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	weathers, errs := make([]*weather_domain.Weather, len(requests)), make([]*weather_domain.WeatherError, len(requests))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range requests {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			//The client is gone, what is left is not worth the upstream calls
+			errs[i] = &weather_domain.WeatherError{Code: http.StatusServiceUnavailable, ErrorMessage: ctx.Err().Error()}
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			weather, err := WeatherService.GetWeather(ctx, requests[i])
+			if err != nil {
+				errs[i] = &weather_domain.WeatherError{Code: err.Status(), ErrorMessage: err.Message()}
+				return
+			}
+			weathers[i] = weather
+		}(i)
+	}
+	wg.Wait()
+	return weathers, errs
+}
+
+//chargePoints counts every point against the quota of client before any is fetched, so that one request cannot
+//fan out past the quota. The ClientKey middleware already counted the request itself, which pays for a point
+func chargePoints(client *client_domain.Client, points int) weather_domain.WeatherErrorInterface {
+	if client == nil || points <= 1 {
+		return nil
+	}
+	_, _, err := UsageService.AllowN(client, int64(points-1))
+	return err
+}
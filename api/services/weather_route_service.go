@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/geo"
+	"interface-testing/api/tracing"
+	"math"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//maxRouteDuration is well past the end of any hourly forecast, it keeps the ETAs from overflowing
+const maxRouteDuration = 14 * 24 * time.Hour
+
+type weatherRouteService struct{}
+
+type weatherRouteServiceInterface interface {
+	GetRoute(ctx context.Context, request weather_domain.RouteRequest) (*weather_domain.Route, weather_domain.WeatherErrorInterface)
+}
+
+var (
+	WeatherRouteService weatherRouteServiceInterface = &weatherRouteService{}
+
+	//RouteInterval is the distance between two samples of a route in kilometers, when the request sets none
+	RouteInterval = 25.0
+	//MaxRoutePoints caps the samples of one route, every sample the cache does not have costs an upstream call
+	MaxRoutePoints = 50
+	//RouteConcurrency is how many samples of one route are fetched at the same time
+	RouteConcurrency = 4
+)
+
+//GetRoute samples the route, works out when the vehicle gets to each sample and picks the hourly forecast for
+//that time. A sample that fails is reported in its point, the route only fails when every sample did
+func (s *weatherRouteService) GetRoute(ctx context.Context, request weather_domain.RouteRequest) (*weather_domain.Route, weather_domain.WeatherErrorInterface) {
+	if request.Interval == 0 {
+		request.Interval = RouteInterval
+	}
+	if request.Departure.IsZero() {
+		request.Departure = time.Now()
+	}
+	if err := validateRoute(request); err != nil {
+		return nil, err
+	}
+	samples := geo.SamplePath(request.Path, request.Interval)
+	if err := chargePoints(request.Client, len(samples)); err != nil {
+		return nil, err
+	}
+	ctx, span := tracing.Tracer().Start(ctx, "WeatherRouteService.GetRoute", trace.WithAttributes(
+		attribute.Int("weather.route.points", len(samples))))
+	defer span.End()
+
+	distance := samples[len(samples)-1].Distance
+	route := &weather_domain.Route{
+		Departure: request.Departure.UTC(),
+		Arrival:   eta(request, distance),
+		Distance:  distance,
+		Speed:     request.Speed,
+		Points:    make([]weather_domain.RoutePoint, len(samples)),
+	}
+	requests := make([]weather_domain.WeatherRequest, len(samples))
+	for i, sample := range samples {
+		route.Points[i] = weather_domain.RoutePoint{
+			Latitude:  roundCoordinate(sample.Latitude),
+			Longitude: roundCoordinate(sample.Longitude),
+			Distance:  sample.Distance,
+			Eta:       eta(request, sample.Distance),
+		}
+		requests[i] = request.Request
+		requests[i].Latitude, requests[i].Longitude = route.Points[i].Latitude, route.Points[i].Longitude
+		requests[i].Hourly = true
+	}
+	weathers, errs := getPoints(ctx, requests, RouteConcurrency)
+
+	failed := 0
+	for i := range route.Points {
+		point := &route.Points[i]
+		point.Error = errs[i]
+		if weathers[i] != nil {
+			point.TimeZone, point.Cache = weathers[i].TimeZone, weathers[i].Cache
+			forecast, ok := weather_domain.ForecastAt(weathers[i].Hourly, point.Eta)
+			if ok {
+				point.Forecast = forecast
+			} else {
+				point.Error = &weather_domain.WeatherError{Code: http.StatusUnprocessableEntity,
+					ErrorMessage: fmt.Sprintf("no hourly forecast for %s", point.Eta.Format(time.RFC3339))}
+			}
+		}
+		if point.Error != nil {
+			failed++
+		}
+	}
+	if failed == len(route.Points) {
+		return nil, route.Points[0].Error
+	}
+	return route, nil
+}
+
+func validateRoute(request weather_domain.RouteRequest) weather_domain.WeatherErrorInterface {
+	if len(request.Path) == 0 {
+		return weather_domain.NewBadRequestError("the route needs at least one point")
+	}
+	for _, point := range request.Path {
+		//Written as negations so that NaN fails them too
+		if !(point.Latitude >= -90 && point.Latitude <= 90 && point.Longitude >= -180 && point.Longitude <= 180) {
+			return weather_domain.NewBadRequestError(fmt.Sprintf("route point %g,%g is out of range", point.Latitude, point.Longitude))
+		}
+	}
+	if !(request.Speed > 0) {
+		return weather_domain.NewBadRequestError("speed must be a positive number of km/h")
+	}
+	if !(request.Interval > 0) {
+		return weather_domain.NewBadRequestError("interval must be a positive number of kilometers")
+	}
+	length := geo.Length(request.Path)
+	//Counted before sampling, an absurdly short interval would otherwise allocate the samples first
+	if points := math.Ceil(length/request.Interval) + 1; points > float64(MaxRoutePoints) {
+		return weather_domain.NewBadRequestError(fmt.Sprintf("a %.0f km route sampled every %g km exceeds the limit of %d points, use a longer interval",
+			length, request.Interval, MaxRoutePoints))
+	}
+	if length/request.Speed > maxRouteDuration.Hours() {
+		return weather_domain.NewBadRequestError(fmt.Sprintf("at %g km/h the route takes longer than %s", request.Speed, maxRouteDuration))
+	}
+	return nil
+}
+
+//eta is when the vehicle of request has travelled distance kilometers, to the second
+func eta(request weather_domain.RouteRequest, distance float64) time.Time {
+	travel := time.Duration(distance / request.Speed * float64(time.Hour))
+	return request.Departure.Add(travel).UTC().Round(time.Second)
+}
+
+//roundCoordinate keeps a sample to about ten meters, so that the same route sampled twice hits the cache
+func roundCoordinate(degrees float64) float64 {
+	return math.Round(degrees*1e4) / 1e4
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"interface-testing/api/domain/client_domain"
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/geo"
+	"interface-testing/api/providers/weather_provider"
+	"interface-testing/api/stores/usage_store"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+//routeDeparture is on the hour of the first forecast hour of hourlyProvider
+var routeDeparture = time.Unix(1583064000, 0)
+
+//hourlyProvider forecasts six hours from routeDeparture, the temperature of an hour being its index
+func hourlyProvider() {
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		if !request.Hourly {
+			return nil, &weather_domain.WeatherError{Code: http.StatusInternalServerError, ErrorMessage: "hourly forecast not asked for"}
+		}
+		weather := &weather_domain.Weather{Latitude: request.Latitude, Longitude: request.Longitude, TimeZone: "Africa/Abidjan"}
+		for hour := 0; hour < 6; hour++ {
+			weather.Hourly = append(weather.Hourly, weather_domain.HourlyInfo{Time: routeDeparture.Unix() + int64(hour*3600), Temperature: float64(hour)})
+		}
+		return weather, nil
+	}
+	weather_provider.WeatherProvider = &getProviderMock{}
+}
+
+func TestWeatherRouteService(t *testing.T) {
+	hourlyProvider()
+	//About 333.6 km along the equator at 100 km/h, sampled every 100 km
+	request := weather_domain.RouteRequest{
+		Request:   weather_domain.WeatherRequest{ApiKey: "api_key", Units: weather_domain.UnitsSI},
+		Path:      []geo.Point{{Latitude: 0, Longitude: 0}, {Latitude: 0, Longitude: 3}},
+		Departure: routeDeparture,
+		Speed:     100,
+		Interval:  100,
+	}
+	route, err := WeatherRouteService.GetRoute(context.Background(), request)
+	assert.Nil(t, err)
+	assert.Len(t, route.Points, 5)
+	assert.InDelta(t, 333.6, route.Distance, 0.1)
+	assert.EqualValues(t, routeDeparture.UTC(), route.Departure)
+	assert.EqualValues(t, route.Points[4].Eta, route.Arrival)
+
+	for i, point := range route.Points[:4] {
+		assert.EqualValues(t, float64(i*100), point.Distance)
+		assert.EqualValues(t, routeDeparture.Add(time.Duration(i)*time.Hour).UTC(), point.Eta)
+		assert.EqualValues(t, "Africa/Abidjan", point.TimeZone)
+		//The forecast is the one of the hour the vehicle is there
+		assert.EqualValues(t, i, point.Forecast.Temperature)
+	}
+	assert.EqualValues(t, 0, route.Points[0].Longitude)
+	assert.EqualValues(t, 0.8993, route.Points[1].Longitude)
+	assert.EqualValues(t, 3, route.Points[4].Longitude)
+	assert.EqualValues(t, 3, route.Points[4].Forecast.Temperature)
+}
+
+func TestWeatherRouteServiceBeyondForecast(t *testing.T) {
+	hourlyProvider()
+	request := weather_domain.RouteRequest{
+		Path:      []geo.Point{{Latitude: 0, Longitude: 0}, {Latitude: 0, Longitude: 9}},
+		Departure: routeDeparture.Add(4 * time.Hour),
+		Speed:     500,
+		Interval:  500,
+	}
+	route, err := WeatherRouteService.GetRoute(context.Background(), request)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 4, route.Points[0].Forecast.Temperature)
+	assert.EqualValues(t, 5, route.Points[1].Forecast.Temperature)
+	//Two hours after departure is past the six hours of forecast
+	assert.Nil(t, route.Points[2].Forecast)
+	assert.EqualValues(t, http.StatusUnprocessableEntity, route.Points[2].Error.Code)
+	assert.EqualValues(t, "no hourly forecast for 2020-03-01T18:00:00Z", route.Points[2].Error.ErrorMessage)
+
+	//When no point has a forecast the route fails
+	request.Departure = routeDeparture.Add(-2 * time.Hour)
+	request.Path = request.Path[:1]
+	_, apiErr := WeatherRouteService.GetRoute(context.Background(), request)
+	assert.EqualValues(t, http.StatusUnprocessableEntity, apiErr.Status())
+}
+
+func TestWeatherRouteServiceInvalid(t *testing.T) {
+	defer func(points int) { MaxRoutePoints = points }(MaxRoutePoints)
+	MaxRoutePoints = 4
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		t.Fatal("a refused route must not reach the upstream")
+		return nil, nil
+	}
+	weather_provider.WeatherProvider = &getProviderMock{}
+
+	path := []geo.Point{{Latitude: 0, Longitude: 0}, {Latitude: 0, Longitude: 3}}
+	for message, request := range map[string]weather_domain.RouteRequest{
+		"the route needs at least one point":                                                       {Speed: 80},
+		"route point 91,0 is out of range":                                                         {Path: []geo.Point{{Latitude: 91}}, Speed: 80},
+		"speed must be a positive number of km/h":                                                  {Path: path},
+		"interval must be a positive number of kilometers":                                         {Path: path, Speed: 80, Interval: -1},
+		"a 334 km route sampled every 100 km exceeds the limit of 4 points, use a longer interval": {Path: path, Speed: 80, Interval: 100},
+		"at 0.1 km/h the route takes longer than 336h0m0s":                                         {Path: path, Speed: 0.1, Interval: 200},
+	} {
+		_, err := WeatherRouteService.GetRoute(context.Background(), request)
+		if assert.NotNil(t, err, message) {
+			assert.EqualValues(t, http.StatusBadRequest, err.Status())
+			assert.EqualValues(t, message, err.Message())
+		}
+	}
+}
+
+func TestWeatherRouteServiceChargesEverySample(t *testing.T) {
+	usage_store.UsageStore, _ = usage_store.NewFileStore("")
+	defer func(quota int64) { client_domain.MonthlyQuotas[client_domain.TierFree] = quota }(client_domain.MonthlyQuotas[client_domain.TierFree])
+	client_domain.MonthlyQuotas[client_domain.TierFree] = 8
+	client := &client_domain.Client{Id: "abc", Tier: client_domain.TierFree}
+	month := time.Now().UTC().Format("2006-01")
+	hourlyProvider()
+	request := weather_domain.RouteRequest{
+		Request:   weather_domain.WeatherRequest{ApiKey: "api_key"},
+		Path:      []geo.Point{{Latitude: 0, Longitude: 0}, {Latitude: 0, Longitude: 3}},
+		Departure: routeDeparture,
+		Speed:     100,
+		Interval:  100,
+		Client:    client,
+	}
+
+	//The middleware counted the request, the route adds the other 4 of its 5 samples
+	UsageService.Allow(client)
+	_, err := WeatherRouteService.GetRoute(context.Background(), request)
+	assert.Nil(t, err)
+	assert.EqualValues(t, 5, usage_store.UsageStore.Month("abc", month).Requests)
+
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		t.Fatal("a route over the quota must not reach the upstream")
+		return nil, nil
+	}
+	UsageService.Allow(client)
+	_, err = WeatherRouteService.GetRoute(context.Background(), request)
+	assert.NotNil(t, err)
+	assert.EqualValues(t, http.StatusTooManyRequests, err.Status())
+	assert.EqualValues(t, 6, usage_store.UsageStore.Month("abc", month).Requests)
+}
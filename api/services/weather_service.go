@@ -1,27 +1,71 @@
 package services
 
 import (
+	"context"
+	"interface-testing/api/i18n"
 	"interface-testing/api/domain/weather_domain"
 	"interface-testing/api/providers/weather_provider"
+	"interface-testing/api/redact"
+	"interface-testing/api/tracing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type weatherService struct {}
 
+//languageSupporter is implemented by providers that can write summaries in the requested language themselves
+type languageSupporter interface {
+	SupportsLanguage(lang string) bool
+}
+
 type weatherServiceInterface interface {
-	GetWeather(input weather_domain.WeatherRequest) (*weather_domain.Weather, weather_domain.WeatherErrorInterface)
+	GetWeather(ctx context.Context, input weather_domain.WeatherRequest) (*weather_domain.Weather, weather_domain.WeatherErrorInterface)
 }
 var (
 	WeatherService weatherServiceInterface = &weatherService{}
 )
 
-func (w *weatherService) GetWeather(input weather_domain.WeatherRequest) (*weather_domain.Weather, weather_domain.WeatherErrorInterface){
+func (w *weatherService) GetWeather(ctx context.Context, input weather_domain.WeatherRequest) (*weather_domain.Weather, weather_domain.WeatherErrorInterface){
+	ctx = redact.WithSecrets(ctx, input.ApiKey)
+	ctx, span := tracing.Tracer().Start(ctx, "WeatherService.GetWeather", trace.WithAttributes(
+		attribute.Float64("weather.latitude", input.Latitude), attribute.Float64("weather.longitude", input.Longitude),
+		attribute.String("weather.units", input.Units), attribute.String("weather.ensemble", input.Ensemble)))
+	defer span.End()
 	request := weather_domain.WeatherRequest{
-		ApiKey:    input.ApiKey,
-		Latitude:  input.Latitude,
-		Longitude: input.Longitude,
+		ApiKey:     input.ApiKey,
+		Latitude:   input.Latitude,
+		Longitude:  input.Longitude,
+		Units:      input.Units,
+		Lang:       input.Lang,
+		Ensemble:   input.Ensemble,
+		Hourly:     input.Hourly,
+		AirQuality: input.AirQuality,
+	}
+	//The upstream call is shared with the coalesced requests and the background refresh, so it must not be
+	//cancelled along with this one
+	upstreamCtx := tracing.Detach(ctx)
+	response, cacheInfo, err := cachedWeather(request, func() (*weather_domain.Weather, *weather_domain.WeatherError) {
+		return inflight.do(request, func() (*weather_domain.Weather, *weather_domain.WeatherError) {
+			fetch := func() (*weather_domain.Weather, *weather_domain.WeatherError) {
+				if request.Ensemble != "" {
+					return getEnsemble(upstreamCtx, request)
+				}
+				return weather_provider.WeatherProvider.GetWeather(upstreamCtx, request)
+			}
+			if request.AirQuality {
+				return withAirQuality(upstreamCtx, request, fetch)
+			}
+			return fetch()
+		})
+	})
+	if cacheInfo != nil {
+		span.SetAttributes(attribute.String("weather.cache", cacheInfo.Status))
 	}
-	response, err := weather_provider.WeatherProvider.GetWeather(request)
 	if err != nil {
+		span.SetStatus(codes.Error, redact.Context(ctx, err.ErrorMessage))
 		return nil, weather_domain.NewWeatherError(err.Code, err.ErrorMessage)
 	}
 	result := weather_domain.Weather{
@@ -34,7 +78,69 @@ func (w *weatherService) GetWeather(input weather_domain.WeatherRequest) (*weath
 			DewPoint:    response.Currently.DewPoint,
 			Pressure:    response.Currently.Pressure,
 			Humidity:    response.Currently.Humidity,
+			WindSpeed:   response.Currently.WindSpeed,
+			WindGust:    response.Currently.WindGust,
+			Time:        response.Currently.Time,
+			Unavailable: response.Currently.Unavailable,
 		},
+		Flags:      response.Flags,
+		Provider:   response.Provider,
+		Ensemble:   response.Ensemble,
+		Hourly:     response.Hourly,
+		AirQuality: response.AirQuality,
+		Cache:      cacheInfo,
+	}
+	if comfortAvailable(result.Currently) {
+		comfort := weather_domain.NewComfortInfo(result.Currently, unitsOf(input, response))
+		result.Comfort = &comfort
+	}
+	observed := observedAt(result.Currently, cacheInfo.FetchedAt)
+	result.LocalTime = localTime(&result, observed)
+	//The sun is that of the calendar day in the time zone of the location, UTC when there is none
+	local := observed.UTC()
+	if result.LocalTime != nil {
+		local = result.LocalTime.ObservedLocal
+	}
+	astronomy := weather_domain.NewAstronomyInfo(result.Latitude, result.Longitude, local)
+	result.Astronomy = &astronomy
+	//An ensemble summary may come from any provider, so it is always translated
+	if supporter, ok := weather_provider.WeatherProvider.(languageSupporter); input.Lang != "" && (!ok || !supporter.SupportsLanguage(input.Lang) || input.Ensemble != "") {
+		result.Currently.Summary = i18n.Translate(input.Lang, result.Currently.Summary)
+		//The cached response shares the hours, they are translated in a copy
+		result.Hourly = append([]weather_domain.HourlyInfo(nil), result.Hourly...)
+		for i := range result.Hourly {
+			result.Hourly[i].Summary = i18n.Translate(input.Lang, result.Hourly[i].Summary)
+		}
 	}
 	return &result, nil
-}
\ No newline at end of file
+}
+
+//comfortAvailable tells whether the upstream sent every value the comfort indices are computed from
+func comfortAvailable(currently weather_domain.CurrentlyInfo) bool {
+	for _, field := range []string{"temperature", "dewPoint", "humidity", "windSpeed"} {
+		if currently.IsUnavailable(field) {
+			return false
+		}
+	}
+	return true
+}
+
+//observedAt is when the current conditions were observed. When the upstream did not say it is when they were
+//fetched, to the second like the upstream times, so that a cached response renders the same every time
+func observedAt(currently weather_domain.CurrentlyInfo, fetchedAt time.Time) time.Time {
+	if currently.Time == 0 {
+		return time.Unix(fetchedAt.Unix(), 0)
+	}
+	return time.Unix(currently.Time, 0)
+}
+
+//unitsOf is the unit system of the response: the one we asked for, or the one the upstream picked for "auto"
+func unitsOf(request weather_domain.WeatherRequest, response *weather_domain.Weather) string {
+	if request.Units != "" && request.Units != "auto" {
+		return request.Units
+	}
+	if response.Flags != nil && response.Flags.Units != "" {
+		return response.Flags.Units
+	}
+	return weather_domain.UnitsUS
+}
@@ -1,10 +1,12 @@
 package services
 
 import (
+	"context"
 	"interface-testing/api/domain/weather_domain"
 	"interface-testing/api/providers/weather_provider"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -14,7 +16,7 @@ var (
 )
 type getProviderMock struct{}
 
-func (c *getProviderMock) GetWeather(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+func (c *getProviderMock) GetWeather(ctx context.Context, request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
 	return getWeatherProviderFunc(request)
 }
 
@@ -28,7 +30,7 @@ func TestWeatherServiceNoAuthKey(t *testing.T) {
 	weather_provider.WeatherProvider = &getProviderMock{} //without this line, the real api is fired
 
 	request := weather_domain.WeatherRequest{ApiKey: "wrong_key", Latitude: 44.3601, Longitude: -71.0589}
-	result, err := WeatherService.GetWeather(request)
+	result, err := WeatherService.GetWeather(context.Background(), request)
 	assert.Nil(t, result)
 	assert.NotNil(t, err)
 	assert.EqualValues(t, http.StatusForbidden, err.Status())
@@ -45,7 +47,7 @@ func TestWeatherServiceWrongLatitude(t *testing.T) {
 	weather_provider.WeatherProvider = &getProviderMock{} //without this line, the real api is fired
 
 	request := weather_domain.WeatherRequest{ApiKey: "api_key", Latitude: 123443, Longitude: -71.0589}
-	result, err := WeatherService.GetWeather(request)
+	result, err := WeatherService.GetWeather(context.Background(), request)
 	assert.Nil(t, result)
 	assert.NotNil(t, err)
 	assert.EqualValues(t, http.StatusBadRequest, err.Status())
@@ -62,7 +64,7 @@ func TestWeatherServiceWrongLongitude(t *testing.T) {
 	weather_provider.WeatherProvider = &getProviderMock{} //without this line, the real api is fired
 
 	request := weather_domain.WeatherRequest{ApiKey: "api_key", Latitude: 39.12, Longitude: 122332}
-	result, err := WeatherService.GetWeather(request)
+	result, err := WeatherService.GetWeather(context.Background(), request)
 	assert.Nil(t, result)
 	assert.NotNil(t, err)
 	assert.EqualValues(t, http.StatusBadRequest, err.Status())
@@ -82,12 +84,13 @@ func TestWeatherServiceSuccess(t *testing.T) {
 				Pressure:    12.90,
 				Humidity:    16.54,
 			},
+			Provider: "darksky",
 		}, nil
 	}
 	weather_provider.WeatherProvider = &getProviderMock{} //without this line, the real api is fired
 
 	request := weather_domain.WeatherRequest{ApiKey: "api_key", Latitude: 39.12, Longitude: 49.12}
-	result, err := WeatherService.GetWeather(request)
+	result, err := WeatherService.GetWeather(context.Background(), request)
 	assert.NotNil(t, result)
 	assert.Nil(t, err)
 	assert.EqualValues(t, 39.12, result.Latitude)
@@ -98,4 +101,185 @@ func TestWeatherServiceSuccess(t *testing.T) {
 	assert.EqualValues(t, 50.22, result.Currently.DewPoint)
 	assert.EqualValues(t, 12.90, result.Currently.Pressure)
 	assert.EqualValues(t, 16.54, result.Currently.Humidity)
+	assert.EqualValues(t, "darksky", result.Provider)
+}
+
+type getLocalizedProviderMock struct {
+	getProviderMock
+}
+
+func (c *getLocalizedProviderMock) SupportsLanguage(lang string) bool {
+	return lang == "fr"
+}
+
+func TestWeatherServiceTranslatesSummary(t *testing.T) {
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		return &weather_domain.Weather{Latitude: 39.12, Longitude: 49.12, Currently: weather_domain.CurrentlyInfo{Summary: "Overcast"}}, nil
+	}
+	weather_provider.WeatherProvider = &getProviderMock{} //this provider cannot localize, so the service does
+
+	result, err := WeatherService.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "api_key", Latitude: 39.12, Longitude: 49.12, Lang: "de"})
+	assert.Nil(t, err)
+	assert.EqualValues(t, "Bedeckt", result.Currently.Summary)
+
+	result, err = WeatherService.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "api_key", Latitude: 39.12, Longitude: 49.12})
+	assert.Nil(t, err)
+	assert.EqualValues(t, "Overcast", result.Currently.Summary)
+}
+
+func TestWeatherServiceTranslatesHourly(t *testing.T) {
+	hours := []weather_domain.HourlyInfo{{Time: 1583064000, Summary: "Overcast"}}
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		return &weather_domain.Weather{Hourly: hours}, nil
+	}
+	weather_provider.WeatherProvider = &getProviderMock{}
+
+	result, err := WeatherService.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "api_key", Latitude: 39.12, Longitude: 49.12, Lang: "de", Hourly: true})
+	assert.Nil(t, err)
+	assert.EqualValues(t, "Bedeckt", result.Hourly[0].Summary)
+	//The hours of the provider, which the cache keeps, are left in English
+	assert.EqualValues(t, "Overcast", hours[0].Summary)
+}
+
+func TestWeatherServiceUnavailableFields(t *testing.T) {
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		return &weather_domain.Weather{Latitude: 39.12, Longitude: 49.12, Currently: weather_domain.CurrentlyInfo{
+			Temperature: 12, Summary: "Overcast", Unavailable: []string{"humidity"},
+		}}, nil
+	}
+	weather_provider.WeatherProvider = &getProviderMock{}
+
+	result, err := WeatherService.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "api_key", Latitude: 39.12, Longitude: 49.12})
+	assert.Nil(t, err)
+	assert.EqualValues(t, []string{"humidity"}, result.Currently.Unavailable)
+	//A comfort index computed from a missing humidity would be made up
+	assert.Nil(t, result.Comfort)
+}
+
+func TestWeatherServiceProviderLocalizes(t *testing.T) {
+	var received weather_domain.WeatherRequest
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		received = request
+		return &weather_domain.Weather{Latitude: 39.12, Longitude: 49.12, Currently: weather_domain.CurrentlyInfo{Summary: "Ciel couvert"}}, nil
+	}
+	weather_provider.WeatherProvider = &getLocalizedProviderMock{}
+
+	result, err := WeatherService.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "api_key", Latitude: 39.12, Longitude: 49.12, Lang: "fr"})
+	assert.Nil(t, err)
+	assert.EqualValues(t, "fr", received.Lang)
+	assert.EqualValues(t, "Ciel couvert", result.Currently.Summary)
+}
+
+func TestWeatherServiceComfort(t *testing.T) {
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		return &weather_domain.Weather{
+			Latitude:  39.12,
+			Longitude: 49.12,
+			Currently: weather_domain.CurrentlyInfo{Temperature: 35, DewPoint: 25, Humidity: 0.56, WindSpeed: 5, WindGust: 9},
+			Flags:     &weather_domain.FlagsInfo{Units: "si"},
+		}, nil
+	}
+	weather_provider.WeatherProvider = &getProviderMock{}
+
+	//"auto" lets the upstream pick the units, the flags tell which one it picked
+	result, err := WeatherService.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "api_key", Latitude: 39.12, Longitude: 49.12, Units: "auto"})
+	assert.Nil(t, err)
+	assert.NotNil(t, result.Comfort)
+	assert.EqualValues(t, 5, result.Currently.WindSpeed)
+	assert.EqualValues(t, 9, result.Currently.WindGust)
+	assert.EqualValues(t, 47.3, result.Comfort.Humidex)
+	assert.EqualValues(t, weather_domain.ComfortDangerousHeat, result.Comfort.Category)
+
+	//Without flags the upstream default, us, applies
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		return &weather_domain.Weather{Currently: weather_domain.CurrentlyInfo{Temperature: 95, DewPoint: 77, Humidity: 0.56, WindSpeed: 11.18}}, nil
+	}
+	result, err = WeatherService.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "api_key", Latitude: 39.12, Longitude: 49.12})
+	assert.Nil(t, err)
+	assert.InDelta(t, 117.1, result.Comfort.Humidex, 0.2)
+	assert.EqualValues(t, weather_domain.ComfortDangerousHeat, result.Comfort.Category)
+}
+
+func TestWeatherServiceAstronomy(t *testing.T) {
+	observed := time.Date(2024, 6, 21, 12, 0, 0, 0, time.UTC)
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		return &weather_domain.Weather{
+			Latitude:  51.5074,
+			Longitude: -0.1278,
+			Currently: weather_domain.CurrentlyInfo{Temperature: 20, Time: observed.Unix()},
+		}, nil
+	}
+	weather_provider.WeatherProvider = &getProviderMock{}
+
+	result, err := WeatherService.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "api_key", Latitude: 51.5074, Longitude: -0.1278})
+	assert.Nil(t, err)
+	assert.NotNil(t, result.Astronomy)
+	london, _ := time.LoadLocation("Europe/London")
+	assert.EqualValues(t, weather_domain.NewAstronomyInfo(51.5074, -0.1278, observed.In(london)), *result.Astronomy)
+	assert.EqualValues(t, 2024, time.Unix(result.Astronomy.Sunrise, 0).UTC().Year())
+
+	//Just after midnight in Madrid it is still the evening of the day before by the sun and in UTC, the sun is that
+	//of the calendar day in Madrid
+	observed = time.Date(2024, 6, 21, 22, 30, 0, 0, time.UTC)
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		return &weather_domain.Weather{
+			Latitude:  40.4168,
+			Longitude: -3.7038,
+			TimeZone:  "Europe/Madrid",
+			Currently: weather_domain.CurrentlyInfo{Temperature: 25, Time: observed.Unix()},
+		}, nil
+	}
+	result, err = WeatherService.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "api_key", Latitude: 40.4168, Longitude: -3.7038})
+	assert.Nil(t, err)
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	assert.EqualValues(t, "2024-06-22 00:30", observed.In(madrid).Format("2006-01-02 15:04"))
+	assert.EqualValues(t, "2024-06-22", time.Unix(result.Astronomy.SolarNoon, 0).In(madrid).Format("2006-01-02"))
+}
+
+func TestWeatherServiceLocalTime(t *testing.T) {
+	observed := time.Date(2024, 7, 1, 16, 0, 0, 0, time.UTC)
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		return &weather_domain.Weather{
+			Latitude:  40.7128,
+			Longitude: -74.0060,
+			TimeZone:  "America/New_York",
+			Currently: weather_domain.CurrentlyInfo{Temperature: 80, Time: observed.Unix()},
+		}, nil
+	}
+	weather_provider.WeatherProvider = &getProviderMock{}
+
+	result, err := WeatherService.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "api_key", Latitude: 40.7128, Longitude: -74.0060})
+	assert.Nil(t, err)
+	assert.NotNil(t, result.LocalTime)
+	assert.EqualValues(t, "America/New_York", result.TimeZone)
+	assert.True(t, observed.Equal(result.LocalTime.ObservedUtc))
+	assert.EqualValues(t, "2024-07-01T12:00:00-04:00", result.LocalTime.ObservedLocal.Format(time.RFC3339))
+	assert.EqualValues(t, -4*60*60, result.LocalTime.UtcOffset)
+	assert.EqualValues(t, "EDT", result.LocalTime.Abbreviation)
+	assert.True(t, result.LocalTime.Dst)
+	assert.False(t, result.LocalTime.TimeZoneDerived)
+}
+
+func TestWeatherServiceLocalTimeDerived(t *testing.T) {
+	observed := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	for _, timeZone := range []string{"", "Not/A_Zone", "Local"} {
+		getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+			return &weather_domain.Weather{
+				Latitude:  48.8566,
+				Longitude: 2.3522,
+				TimeZone:  timeZone,
+				Currently: weather_domain.CurrentlyInfo{Temperature: 5, Time: observed.Unix()},
+			}, nil
+		}
+		weather_provider.WeatherProvider = &getProviderMock{}
+
+		result, err := WeatherService.GetWeather(context.Background(), weather_domain.WeatherRequest{ApiKey: "api_key", Latitude: 48.8566, Longitude: 2.3522})
+		assert.Nil(t, err)
+		assert.EqualValues(t, "Europe/Paris", result.TimeZone, timeZone)
+		assert.NotNil(t, result.LocalTime)
+		assert.EqualValues(t, 60*60, result.LocalTime.UtcOffset)
+		assert.EqualValues(t, "CET", result.LocalTime.Abbreviation)
+		assert.False(t, result.LocalTime.Dst)
+		assert.True(t, result.LocalTime.TimeZoneDerived)
+	}
 }
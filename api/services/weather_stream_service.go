@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"interface-testing/api/domain/weather_domain"
+	"reflect"
+	"sync"
+	"time"
+)
+
+type weatherStreamService struct {
+	mu      sync.Mutex
+	pollers map[weather_domain.WeatherRequest]*weatherPoller
+}
+
+type weatherStreamServiceInterface interface {
+	Subscribe(request weather_domain.WeatherRequest, updates chan<- weather_domain.WeatherUpdate) (unsubscribe func())
+}
+
+//weatherPoller polls the upstream for one location and fans the changes out to every subscriber of that location
+type weatherPoller struct {
+	request     weather_domain.WeatherRequest
+	subscribers map[chan<- weather_domain.WeatherUpdate]int
+	//missed holds the subscribers whose channel was full when the last update went out, to be resent on the next poll
+	missed map[chan<- weather_domain.WeatherUpdate]bool
+	last   *weather_domain.WeatherUpdate
+	stop   chan struct{}
+}
+
+var (
+	WeatherStreamService weatherStreamServiceInterface = &weatherStreamService{
+		pollers: make(map[weather_domain.WeatherRequest]*weatherPoller),
+	}
+	StreamPollInterval = 30 * time.Second
+)
+
+func (s *weatherStreamService) Subscribe(request weather_domain.WeatherRequest, updates chan<- weather_domain.WeatherUpdate) func() {
+	s.mu.Lock()
+	poller, ok := s.pollers[request]
+	if !ok {
+		poller = &weatherPoller{
+			request:     request,
+			subscribers: make(map[chan<- weather_domain.WeatherUpdate]int),
+			missed:      make(map[chan<- weather_domain.WeatherUpdate]bool),
+			stop:        make(chan struct{}),
+		}
+		s.pollers[request] = poller
+		go s.poll(poller)
+	}
+	poller.subscribers[updates]++
+	//A late subscriber should not wait a whole interval for its first payload
+	if poller.last != nil {
+		poller.deliver(updates)
+	}
+	s.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { s.unsubscribe(poller, updates) })
+	}
+}
+
+func (s *weatherStreamService) unsubscribe(poller *weatherPoller, updates chan<- weather_domain.WeatherUpdate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	poller.subscribers[updates]--
+	if poller.subscribers[updates] <= 0 {
+		delete(poller.subscribers, updates)
+		delete(poller.missed, updates)
+	}
+	if len(poller.subscribers) == 0 {
+		close(poller.stop)
+		delete(s.pollers, poller.request)
+	}
+}
+
+func (s *weatherStreamService) poll(poller *weatherPoller) {
+	ticker := time.NewTicker(StreamPollInterval)
+	defer ticker.Stop()
+	for {
+		s.refresh(poller)
+		select {
+		case <-poller.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *weatherStreamService) refresh(poller *weatherPoller) {
+	result, err := WeatherService.GetWeather(context.Background(), poller.request)
+	update := weather_domain.WeatherUpdate{Request: poller.request, Weather: result, Err: err}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	changed := poller.last == nil || !sameUpdate(*poller.last, update)
+	if changed {
+		poller.last = &update
+	}
+	for subscriber := range poller.subscribers {
+		if changed || poller.missed[subscriber] {
+			poller.deliver(subscriber)
+		}
+	}
+}
+
+//deliver sends the latest update to a subscriber, remembering it as missed when its channel is full
+func (poller *weatherPoller) deliver(subscriber chan<- weather_domain.WeatherUpdate) {
+	if send(subscriber, *poller.last) {
+		delete(poller.missed, subscriber)
+	} else {
+		poller.missed[subscriber] = true
+	}
+}
+
+func sameUpdate(a, b weather_domain.WeatherUpdate) bool {
+	if a.Err != nil || b.Err != nil {
+		return a.Err != nil && b.Err != nil && a.Err.Status() == b.Err.Status() && a.Err.Message() == b.Err.Message()
+	}
+	if a.Weather == nil || b.Weather == nil {
+		return a.Weather == b.Weather
+	}
+	//The age of a cached response changes on every poll without the weather changing, and so do the local time
+	//and the astronomy when the provider gives no observation time and they are computed for now
+	aWeather, bWeather := *a.Weather, *b.Weather
+	aWeather.Cache, bWeather.Cache = nil, nil
+	aWeather.LocalTime, bWeather.LocalTime = nil, nil
+	aWeather.Astronomy, bWeather.Astronomy = nil, nil
+	return reflect.DeepEqual(aWeather, bWeather)
+}
+
+//send never blocks the poller: a subscriber that is not keeping up gets the latest update on a later poll
+func send(updates chan<- weather_domain.WeatherUpdate, update weather_domain.WeatherUpdate) bool {
+	select {
+	case updates <- update:
+		return true
+	default:
+		return false
+	}
+}
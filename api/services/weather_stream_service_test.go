@@ -0,0 +1,108 @@
+package services
+
+import (
+	"interface-testing/api/domain/weather_domain"
+	"interface-testing/api/providers/weather_provider"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func receive(t *testing.T, updates chan weather_domain.WeatherUpdate) weather_domain.WeatherUpdate {
+	select {
+	case update := <-updates:
+		return update
+	case <-time.After(time.Second):
+		t.Fatal("no weather update received")
+	}
+	return weather_domain.WeatherUpdate{}
+}
+
+func TestWeatherStreamSharesOnePoll(t *testing.T) {
+	var calls int32
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		atomic.AddInt32(&calls, 1)
+		return &weather_domain.Weather{Latitude: request.Latitude, Longitude: request.Longitude, Currently: weather_domain.CurrentlyInfo{Temperature: 40.22}}, nil
+	}
+	weather_provider.WeatherProvider = &getProviderMock{}
+	StreamPollInterval = time.Hour
+
+	request := weather_domain.WeatherRequest{ApiKey: "api_key", Latitude: 39.12, Longitude: 49.12}
+	first := make(chan weather_domain.WeatherUpdate, 1)
+	second := make(chan weather_domain.WeatherUpdate, 1)
+	unsubscribeFirst := WeatherStreamService.Subscribe(request, first)
+	defer unsubscribeFirst()
+	assert.EqualValues(t, 40.22, receive(t, first).Weather.Currently.Temperature)
+
+	unsubscribeSecond := WeatherStreamService.Subscribe(request, second)
+	defer unsubscribeSecond()
+	assert.EqualValues(t, 40.22, receive(t, second).Weather.Currently.Temperature)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+}
+
+func TestWeatherStreamPushesOnlyChanges(t *testing.T) {
+	var calls int32
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		call := atomic.AddInt32(&calls, 1)
+		temperature := 40.22
+		if call >= 3 {
+			temperature = 41.5
+		}
+		return &weather_domain.Weather{Latitude: request.Latitude, Longitude: request.Longitude, Currently: weather_domain.CurrentlyInfo{Temperature: temperature}}, nil
+	}
+	weather_provider.WeatherProvider = &getProviderMock{}
+	StreamPollInterval = 10 * time.Millisecond
+
+	updates := make(chan weather_domain.WeatherUpdate, 4)
+	unsubscribe := WeatherStreamService.Subscribe(weather_domain.WeatherRequest{ApiKey: "api_key", Latitude: 10, Longitude: 20}, updates)
+	defer unsubscribe()
+
+	assert.EqualValues(t, 40.22, receive(t, updates).Weather.Currently.Temperature)
+	assert.EqualValues(t, 41.5, receive(t, updates).Weather.Currently.Temperature)
+	assert.True(t, atomic.LoadInt32(&calls) >= 3)
+}
+
+func TestWeatherStreamError(t *testing.T) {
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		return nil, &weather_domain.WeatherError{Code: http.StatusForbidden, ErrorMessage: "permission denied"}
+	}
+	weather_provider.WeatherProvider = &getProviderMock{}
+	StreamPollInterval = time.Hour
+
+	updates := make(chan weather_domain.WeatherUpdate, 1)
+	unsubscribe := WeatherStreamService.Subscribe(weather_domain.WeatherRequest{ApiKey: "wrong_key", Latitude: 1, Longitude: 2}, updates)
+	defer unsubscribe()
+
+	update := receive(t, updates)
+	assert.Nil(t, update.Weather)
+	assert.NotNil(t, update.Err)
+	assert.EqualValues(t, http.StatusForbidden, update.Err.Status())
+	assert.EqualValues(t, "permission denied", update.Err.Message())
+}
+
+func TestWeatherStreamResendsMissedUpdate(t *testing.T) {
+	var calls int32
+	getWeatherProviderFunc = func(request weather_domain.WeatherRequest) (*weather_domain.Weather, *weather_domain.WeatherError) {
+		temperature := 40.22
+		if atomic.AddInt32(&calls, 1) >= 2 {
+			temperature = 41.5
+		}
+		return &weather_domain.Weather{Latitude: request.Latitude, Longitude: request.Longitude, Currently: weather_domain.CurrentlyInfo{Temperature: temperature}}, nil
+	}
+	weather_provider.WeatherProvider = &getProviderMock{}
+	StreamPollInterval = 10 * time.Millisecond
+
+	//The subscriber does not read until the weather has changed and settled, so the change finds its channel full
+	updates := make(chan weather_domain.WeatherUpdate, 1)
+	unsubscribe := WeatherStreamService.Subscribe(weather_domain.WeatherRequest{ApiKey: "api_key", Latitude: 11, Longitude: 21}, updates)
+	defer unsubscribe()
+	for atomic.LoadInt32(&calls) < 4 {
+		time.Sleep(StreamPollInterval)
+	}
+
+	assert.EqualValues(t, 40.22, receive(t, updates).Weather.Currently.Temperature)
+	assert.EqualValues(t, 41.5, receive(t, updates).Weather.Currently.Temperature)
+}
@@ -0,0 +1,163 @@
+package client_store
+
+import (
+	"encoding/json"
+	"errors"
+	"interface-testing/api/domain/client_domain"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+//record is how a client is written to the file, Client leaves its key hash out of its json
+type record struct {
+	client_domain.Client
+	KeyHash string `json:"keyHash"`
+}
+
+type fileStore struct {
+	mu      sync.Mutex
+	path    string
+	clients map[string]client_domain.Client
+}
+
+type clientStoreInterface interface {
+	//Save adds the client or replaces the one with the same Id
+	Save(client client_domain.Client) error
+	//Update applies update to the stored client and saves the result, with no other change coming in between.
+	//Nothing is saved when update fails, its error is returned as is, and ErrNotFound is returned for an unknown id
+	Update(id string, update func(client *client_domain.Client) error) (*client_domain.Client, error)
+	Get(id string) (*client_domain.Client, bool)
+	GetByKeyHash(keyHash string) (*client_domain.Client, bool)
+	//List returns every client, revoked ones included, oldest first
+	List() []client_domain.Client
+}
+
+var (
+	ClientStore clientStoreInterface = &fileStore{clients: make(map[string]client_domain.Client)}
+
+	ErrNotFound = errors.New("client not found")
+)
+
+//NewFileStore keeps the clients in the json file at path, loading the ones it already holds.
+//An empty path keeps them in memory only
+func NewFileStore(path string) (clientStoreInterface, error) {
+	store := &fileStore{path: path, clients: make(map[string]client_domain.Client)}
+	if path == "" {
+		return store, nil
+	}
+	bytes, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var records []record
+	if err := json.Unmarshal(bytes, &records); err != nil {
+		return nil, err
+	}
+	for _, r := range records {
+		r.Client.KeyHash = r.KeyHash
+		store.clients[r.Id] = r.Client
+	}
+	return store, nil
+}
+
+func (s *fileStore) Save(client client_domain.Client) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, existed := s.clients[client.Id]
+	s.clients[client.Id] = client
+	if err := s.write(); err != nil {
+		if existed {
+			s.clients[client.Id] = previous
+		} else {
+			delete(s.clients, client.Id)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *fileStore) Update(id string, update func(client *client_domain.Client) error) (*client_domain.Client, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	previous, ok := s.clients[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	client := previous
+	if err := update(&client); err != nil {
+		return nil, err
+	}
+	s.clients[id] = client
+	if err := s.write(); err != nil {
+		s.clients[id] = previous
+		return nil, err
+	}
+	return &client, nil
+}
+
+func (s *fileStore) Get(id string) (*client_domain.Client, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	client, ok := s.clients[id]
+	if !ok {
+		return nil, false
+	}
+	return &client, true
+}
+
+func (s *fileStore) GetByKeyHash(keyHash string) (*client_domain.Client, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, client := range s.clients {
+		if client.KeyHash == keyHash {
+			return &client, true
+		}
+	}
+	return nil, false
+}
+
+func (s *fileStore) List() []client_domain.Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clients := make([]client_domain.Client, 0, len(s.clients))
+	for _, client := range s.clients {
+		clients = append(clients, client)
+	}
+	sort.Slice(clients, func(i, j int) bool { return clients[i].CreatedAt.Before(clients[j].CreatedAt) })
+	return clients
+}
+
+//write replaces the file through a temporary one, so a crash never leaves it half written
+func (s *fileStore) write() error {
+	if s.path == "" {
+		return nil
+	}
+	records := make([]record, 0, len(s.clients))
+	for _, client := range s.clients {
+		records = append(records, record{Client: client, KeyHash: client.KeyHash})
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].CreatedAt.Before(records[j].CreatedAt) })
+	bytes, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	temp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(bytes); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), s.path)
+}
@@ -0,0 +1,99 @@
+package client_store
+
+import (
+	"errors"
+	"interface-testing/api/domain/client_domain"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileStorePersistsClients(t *testing.T) {
+	dir, err := ioutil.TempDir("", "client_store")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "keys.json")
+
+	store, err := NewFileStore(path)
+	assert.Nil(t, err)
+	created := time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)
+	assert.Nil(t, store.Save(client_domain.Client{Id: "b", Owner: "mobile", Tier: client_domain.TierFree, KeyHash: "hash-b", CreatedAt: created.Add(time.Hour)}))
+	assert.Nil(t, store.Save(client_domain.Client{Id: "a", Owner: "web", Tier: client_domain.TierStandard, KeyHash: "hash-a", CreatedAt: created}))
+
+	reloaded, err := NewFileStore(path)
+	assert.Nil(t, err)
+	clients := reloaded.List()
+	assert.EqualValues(t, 2, len(clients))
+	assert.EqualValues(t, "a", clients[0].Id)
+	assert.EqualValues(t, "b", clients[1].Id)
+	client, ok := reloaded.GetByKeyHash("hash-b")
+	assert.True(t, ok)
+	assert.EqualValues(t, "mobile", client.Owner)
+
+	info, err := os.Stat(path)
+	assert.Nil(t, err)
+	assert.EqualValues(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestFileStoreReplacesClient(t *testing.T) {
+	store, _ := NewFileStore("")
+	store.Save(client_domain.Client{Id: "a", KeyHash: "old"})
+	store.Save(client_domain.Client{Id: "a", KeyHash: "new"})
+
+	_, ok := store.GetByKeyHash("old")
+	assert.False(t, ok)
+	client, ok := store.Get("a")
+	assert.True(t, ok)
+	assert.EqualValues(t, "new", client.KeyHash)
+	_, ok = store.Get("b")
+	assert.False(t, ok)
+}
+
+func TestFileStoreUpdate(t *testing.T) {
+	store, _ := NewFileStore("")
+	store.Save(client_domain.Client{Id: "a", Owner: "web", KeyHash: "old"})
+
+	client, err := store.Update("a", func(client *client_domain.Client) error {
+		client.KeyHash = "new"
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.EqualValues(t, "new", client.KeyHash)
+	assert.EqualValues(t, "web", client.Owner)
+	stored, _ := store.Get("a")
+	assert.EqualValues(t, "new", stored.KeyHash)
+
+	refused := errors.New("refused")
+	_, err = store.Update("a", func(client *client_domain.Client) error {
+		client.KeyHash = "dropped"
+		return refused
+	})
+	assert.EqualValues(t, refused, err)
+	stored, _ = store.Get("a")
+	assert.EqualValues(t, "new", stored.KeyHash)
+
+	_, err = store.Update("b", func(client *client_domain.Client) error { return nil })
+	assert.EqualValues(t, ErrNotFound, err)
+}
+
+func TestFileStoreWriteFailureKeepsPreviousClient(t *testing.T) {
+	store, _ := NewFileStore(filepath.Join(os.TempDir(), "missing-directory", "keys.json"))
+	assert.NotNil(t, store.Save(client_domain.Client{Id: "a", KeyHash: "hash"}))
+	_, ok := store.Get("a")
+	assert.False(t, ok)
+}
+
+func TestFileStoreInvalidFile(t *testing.T) {
+	file, err := ioutil.TempFile("", "keys")
+	assert.Nil(t, err)
+	defer os.Remove(file.Name())
+	file.WriteString("{not json")
+	file.Close()
+
+	_, err = NewFileStore(file.Name())
+	assert.NotNil(t, err)
+}
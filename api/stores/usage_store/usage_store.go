@@ -0,0 +1,144 @@
+package usage_store
+
+import (
+	"encoding/json"
+	"interface-testing/api/domain/client_domain"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+type usageKey struct {
+	clientId string
+	day      string
+}
+
+//fileStore counts in memory and only writes the file on Flush, the counts change on every request.
+//months keeps the running total of each client per month, keyed by the month in place of the day,
+//so that checking a quota does not go through every day
+type fileStore struct {
+	mu     sync.Mutex
+	path   string
+	days   map[usageKey]client_domain.Usage
+	months map[usageKey]client_domain.Usage
+	dirty  bool
+}
+
+type usageStoreInterface interface {
+	//Add adds delta to the usage of a client on day, formatted as client_domain.DayFormat
+	Add(clientId string, day string, delta client_domain.Usage)
+	//Month is the usage of a client over month, formatted as client_domain.MonthFormat
+	Month(clientId string, month string) client_domain.Usage
+	//List returns the usage of every client between from and to included, by day then client
+	List(from string, to string) []client_domain.UsageRecord
+	//Flush writes the counts to the file when they changed since the last time
+	Flush() error
+}
+
+var (
+	UsageStore usageStoreInterface = &fileStore{days: make(map[usageKey]client_domain.Usage), months: make(map[usageKey]client_domain.Usage)}
+)
+
+//NewFileStore keeps the usage in the json file at path, loading what it already holds.
+//An empty path keeps it in memory only
+func NewFileStore(path string) (usageStoreInterface, error) {
+	store := &fileStore{path: path, days: make(map[usageKey]client_domain.Usage), months: make(map[usageKey]client_domain.Usage)}
+	if path == "" {
+		return store, nil
+	}
+	bytes, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var records []client_domain.UsageRecord
+	if err := json.Unmarshal(bytes, &records); err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		store.add(record.ClientId, record.Day, record.Usage)
+	}
+	return store, nil
+}
+
+func (s *fileStore) Add(clientId string, day string, delta client_domain.Usage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.add(clientId, day, delta)
+	s.dirty = true
+}
+
+func (s *fileStore) add(clientId string, day string, delta client_domain.Usage) {
+	key := usageKey{clientId, day}
+	usage := s.days[key]
+	usage.Add(delta)
+	s.days[key] = usage
+	if len(day) >= len(client_domain.MonthFormat) {
+		key.day = day[:len(client_domain.MonthFormat)]
+		usage = s.months[key]
+		usage.Add(delta)
+		s.months[key] = usage
+	}
+}
+
+func (s *fileStore) Month(clientId string, month string) client_domain.Usage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.months[usageKey{clientId, month}]
+}
+
+func (s *fileStore) List(from string, to string) []client_domain.UsageRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.list(from, to)
+}
+
+func (s *fileStore) list(from string, to string) []client_domain.UsageRecord {
+	records := []client_domain.UsageRecord{}
+	for key, usage := range s.days {
+		if (from == "" || key.day >= from) && (to == "" || key.day <= to) {
+			records = append(records, client_domain.UsageRecord{ClientId: key.clientId, Day: key.day, Usage: usage})
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Day != records[j].Day {
+			return records[i].Day < records[j].Day
+		}
+		return records[i].ClientId < records[j].ClientId
+	})
+	return records
+}
+
+//Flush replaces the file through a temporary one, so a crash never leaves it half written
+func (s *fileStore) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.path == "" || !s.dirty {
+		return nil
+	}
+	bytes, err := json.MarshalIndent(s.list("", ""), "", "  ")
+	if err != nil {
+		return err
+	}
+	temp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(bytes); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(temp.Name(), s.path); err != nil {
+		return err
+	}
+	s.dirty = false
+	return nil
+}
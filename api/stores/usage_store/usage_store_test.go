@@ -0,0 +1,50 @@
+package usage_store
+
+import (
+	"interface-testing/api/domain/client_domain"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsageStoreAggregatesByDay(t *testing.T) {
+	store, _ := NewFileStore("")
+	store.Add("a", "2020-03-01", client_domain.Usage{Requests: 1})
+	store.Add("a", "2020-03-01", client_domain.Usage{Requests: 1, UpstreamCalls: 1})
+	store.Add("a", "2020-03-02", client_domain.Usage{Requests: 1, CacheHits: 1})
+	store.Add("a", "2020-04-01", client_domain.Usage{Requests: 1})
+	store.Add("b", "2020-03-01", client_domain.Usage{Requests: 5})
+
+	assert.EqualValues(t, client_domain.Usage{Requests: 3, UpstreamCalls: 1, CacheHits: 1}, store.Month("a", "2020-03"))
+	assert.EqualValues(t, client_domain.Usage{Requests: 1}, store.Month("a", "2020-04"))
+	assert.EqualValues(t, client_domain.Usage{}, store.Month("c", "2020-03"))
+
+	records := store.List("2020-03-01", "2020-03-31")
+	assert.EqualValues(t, 3, len(records))
+	assert.EqualValues(t, client_domain.UsageRecord{ClientId: "a", Day: "2020-03-01", Usage: client_domain.Usage{Requests: 2, UpstreamCalls: 1}}, records[0])
+	assert.EqualValues(t, "b", records[1].ClientId)
+	assert.EqualValues(t, "2020-03-02", records[2].Day)
+	assert.EqualValues(t, 4, len(store.List("", "")))
+}
+
+func TestUsageStoreFlush(t *testing.T) {
+	dir, err := ioutil.TempDir("", "usage_store")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "usage.json")
+
+	store, err := NewFileStore(path)
+	assert.Nil(t, err)
+	assert.Nil(t, store.Flush())
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	store.Add("a", "2020-03-01", client_domain.Usage{Requests: 2, CacheHits: 1})
+	assert.Nil(t, store.Flush())
+	reloaded, err := NewFileStore(path)
+	assert.Nil(t, err)
+	assert.EqualValues(t, client_domain.Usage{Requests: 2, CacheHits: 1}, reloaded.Month("a", "2020-03"))
+}
@@ -0,0 +1,251 @@
+//go:build ignore
+
+//gen builds boundaries.gz from the time zone boundaries of timezone-boundary-builder with oceans, in the protobuf
+//form tzf-rel-lite publishes them (combined-with-oceans.reduce.bin, https://github.com/ringsaturn/tzf-rel-lite):
+//
+//	go run gen.go combined-with-oceans.reduce.bin
+//
+//Each polygon is simplified to within tolerance degrees and written on a line of its own: the zone name followed by
+//its rings as polylines, the outer ring first and its holes after, separated by tabs
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"interface-testing/api/geo"
+	"io/ioutil"
+	"log"
+	"math"
+	"os"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	tolerance = 0.02
+	precision = 3
+)
+
+type zone struct {
+	name     string
+	polygons [][][]geo.Point
+}
+
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatal("usage: go run gen.go combined-with-oceans.reduce.bin")
+	}
+	data, err := ioutil.ReadFile(os.Args[1])
+	if err != nil {
+		log.Fatal(err)
+	}
+	var zones []zone
+	var version string
+	each(data, func(field protowire.Number, value []byte) {
+		switch field {
+		case 1:
+			zones = append(zones, decodeZone(value))
+		case 3:
+			version = string(value)
+		}
+	})
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "# timezone-boundary-builder %s with oceans, (c) OpenStreetMap contributors, ODbL 1.0, simplified to %g degrees\n",
+		version, tolerance)
+	for _, zone := range zones {
+		for _, polygon := range zone.polygons {
+			var rings []string
+			for i, ring := range polygon {
+				simplified := simplifyRing(ring)
+				if len(simplified) < 3 {
+					if i == 0 {
+						break
+					}
+					continue
+				}
+				rings = append(rings, geo.EncodePolyline(simplified, precision))
+			}
+			if len(rings) > 0 {
+				text.WriteString(zone.name + "\t" + strings.Join(rings, "\t") + "\n")
+			}
+		}
+	}
+	var compressed bytes.Buffer
+	writer, _ := gzip.NewWriterLevel(&compressed, gzip.BestCompression)
+	_, _ = writer.Write([]byte(text.String()))
+	_ = writer.Close()
+	if err := ioutil.WriteFile("boundaries.gz", compressed.Bytes(), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+//each calls fn with the length delimited fields of a message, the only kind the tzf messages hold besides floats
+func each(message []byte, fn func(field protowire.Number, value []byte)) {
+	for len(message) > 0 {
+		field, kind, n := protowire.ConsumeTag(message)
+		if n < 0 {
+			log.Fatal(protowire.ParseError(n))
+		}
+		message = message[n:]
+		if kind == protowire.BytesType {
+			value, m := protowire.ConsumeBytes(message)
+			if m < 0 {
+				log.Fatal(protowire.ParseError(m))
+			}
+			fn(field, value)
+			n = m
+		} else {
+			n = protowire.ConsumeFieldValue(field, kind, message)
+		}
+		message = message[n:]
+	}
+}
+
+func decodeZone(message []byte) zone {
+	var z zone
+	each(message, func(field protowire.Number, value []byte) {
+		switch field {
+		case 1:
+			z.polygons = append(z.polygons, decodePolygon(value))
+		case 2:
+			z.name = string(value)
+		}
+	})
+	return z
+}
+
+//decodePolygon returns the outer ring then the holes
+func decodePolygon(message []byte) [][]geo.Point {
+	rings := [][]geo.Point{nil}
+	each(message, func(field protowire.Number, value []byte) {
+		switch field {
+		case 1:
+			rings[0] = append(rings[0], decodePoint(value))
+		case 2:
+			rings = append(rings, decodePolygon(value)[0])
+		}
+	})
+	return rings
+}
+
+func decodePoint(message []byte) geo.Point {
+	var point geo.Point
+	for len(message) > 0 {
+		field, kind, n := protowire.ConsumeTag(message)
+		message = message[n:]
+		if kind != protowire.Fixed32Type {
+			log.Fatal("unexpected point field")
+		}
+		bits, m := protowire.ConsumeFixed32(message)
+		message = message[m:]
+		if field == 1 {
+			point.Longitude = float64(math.Float32frombits(bits))
+		} else {
+			point.Latitude = float64(math.Float32frombits(bits))
+		}
+	}
+	return point
+}
+
+//simplifyRing runs Douglas-Peucker on the two halves of the ring, split at its point furthest from the first.
+//A ring too small to survive keeps its extreme points, so that small islands stay in their zone
+func simplifyRing(ring []geo.Point) []geo.Point {
+	if len(ring) > 1 && ring[0] == ring[len(ring)-1] {
+		ring = ring[:len(ring)-1]
+	}
+	if len(ring) < 3 {
+		return nil
+	}
+	far := 0
+	for i, point := range ring {
+		if squared(point, ring[0]) > squared(ring[far], ring[0]) {
+			far = i
+		}
+	}
+	first := simplify(ring[:far+1])
+	second := simplify(append(append([]geo.Point(nil), ring[far:]...), ring[0]))
+	simplified := append(first[:len(first)-1], second[:len(second)-1]...)
+	if len(simplified) >= 3 {
+		return simplified
+	}
+	simplified = nil
+	for _, extreme := range extremes(ring) {
+		if !contains(simplified, extreme) {
+			simplified = append(simplified, extreme)
+		}
+	}
+	return simplified
+}
+
+func simplify(points []geo.Point) []geo.Point {
+	keep := make([]bool, len(points))
+	keep[0], keep[len(points)-1] = true, true
+	stack := [][2]int{{0, len(points) - 1}}
+	for len(stack) > 0 {
+		a, b := stack[len(stack)-1][0], stack[len(stack)-1][1]
+		stack = stack[:len(stack)-1]
+		furthest, distance := -1, -1.0
+		for i := a + 1; i < b; i++ {
+			if d := segmentDistance(points[i], points[a], points[b]); d > distance {
+				furthest, distance = i, d
+			}
+		}
+		if distance > tolerance {
+			keep[furthest] = true
+			stack = append(stack, [2]int{a, furthest}, [2]int{furthest, b})
+		}
+	}
+	var simplified []geo.Point
+	for i, point := range points {
+		if keep[i] {
+			simplified = append(simplified, point)
+		}
+	}
+	return simplified
+}
+
+//segmentDistance is the distance in degrees from p to the line through a and b
+func segmentDistance(p, a, b geo.Point) float64 {
+	dx, dy := b.Longitude-a.Longitude, b.Latitude-a.Latitude
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return math.Hypot(p.Longitude-a.Longitude, p.Latitude-a.Latitude)
+	}
+	return math.Abs(dy*(p.Longitude-a.Longitude)-dx*(p.Latitude-a.Latitude)) / length
+}
+
+func squared(a, b geo.Point) float64 {
+	return (a.Latitude-b.Latitude)*(a.Latitude-b.Latitude) + (a.Longitude-b.Longitude)*(a.Longitude-b.Longitude)
+}
+
+//extremes are the southern, northern, western and eastern points of ring
+func extremes(ring []geo.Point) []geo.Point {
+	south, north, west, east := ring[0], ring[0], ring[0], ring[0]
+	for _, point := range ring {
+		if point.Latitude < south.Latitude {
+			south = point
+		}
+		if point.Latitude > north.Latitude {
+			north = point
+		}
+		if point.Longitude < west.Longitude {
+			west = point
+		}
+		if point.Longitude > east.Longitude {
+			east = point
+		}
+	}
+	return []geo.Point{south, north, west, east}
+}
+
+func contains(points []geo.Point, point geo.Point) bool {
+	for _, p := range points {
+		if p == point {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+//Package timezone resolves IANA time zones with the tzdata built into the binary, so that they load on hosts
+//without a zoneinfo database, and finds the zone of a location offline.
+package timezone
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"fmt"
+	"interface-testing/api/geo"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata"
+)
+
+//go:generate go run gen.go combined-with-oceans.reduce.bin
+
+//boundaries are the zones of timezone-boundary-builder 2025b, oceans included, from OpenStreetMap under the ODbL.
+//They are simplified to about 2 km, a location closer than that to a border may get the zone across it
+//
+//go:embed boundaries.gz
+var boundaries []byte
+
+//polygon is a zone polygon, its outer ring and holes alike, with the box around it to skip it quickly
+type polygon struct {
+	zone                     string
+	rings                    [][]geo.Point
+	south, west, north, east float64
+}
+
+var (
+	loadPolygons sync.Once
+	polygons     []polygon
+
+	//locations caches the parsed zones, time.LoadLocation reads and parses the tzdata on every call
+	locations sync.Map
+)
+
+//Lookup returns the IANA zone at a location. Outside every boundary, which only happens in the gaps the
+//simplification leaves along borders, it falls back to the nautical zone of the longitude, such as "Etc/GMT+3"
+func Lookup(latitude float64, longitude float64) string {
+	loadPolygons.Do(func() {
+		var err error
+		if polygons, err = parse(boundaries); err != nil {
+			log.Println(fmt.Sprintf("error when trying to read the time zone boundaries %s", err.Error()))
+		}
+	})
+	for i := range polygons {
+		if polygons[i].contains(latitude, longitude) {
+			return polygons[i].zone
+		}
+	}
+	return nautical(longitude)
+}
+
+//Location loads the IANA zone name, or fails like time.LoadLocation when there is no such zone. Unlike it, ""
+//and "Local" are refused too: they are not IANA names but UTC and the zone of the host
+func Location(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	if location, ok := locations.Load(name); ok {
+		return location.(*time.Location), nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locations.Store(name, location)
+	return location, nil
+}
+
+//nautical is the zone of the 15 degrees of longitude around longitude. The Etc zones count west as positive
+func nautical(longitude float64) string {
+	hours := int(math.Round(longitude / 15))
+	if hours == 0 {
+		return "Etc/GMT"
+	}
+	return fmt.Sprintf("Etc/GMT%+d", -hours)
+}
+
+//parse reads the lines of the boundaries: a zone name then the rings of one of its polygons as polylines,
+//tab separated. Lines starting with # are comments
+func parse(compressed []byte) ([]polygon, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	var parsed []polygon
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), "#") {
+			continue
+		}
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("invalid boundary line %q", scanner.Text())
+		}
+		p := polygon{zone: fields[0], south: 90, west: 180, north: -90, east: -180}
+		for _, encoded := range fields[1:] {
+			ring, err := geo.DecodePolyline(encoded, 3)
+			if err != nil {
+				return nil, fmt.Errorf("invalid boundary of %s: %s", fields[0], err.Error())
+			}
+			for _, point := range ring {
+				p.south, p.north = math.Min(p.south, point.Latitude), math.Max(p.north, point.Latitude)
+				p.west, p.east = math.Min(p.west, point.Longitude), math.Max(p.east, point.Longitude)
+			}
+			p.rings = append(p.rings, ring)
+		}
+		parsed = append(parsed, p)
+	}
+	//The oceans go last, so that a coastal location the simplification put at sea on one side stays on land
+	sort.SliceStable(parsed, func(i, j int) bool {
+		return !strings.HasPrefix(parsed[i].zone, "Etc/") && strings.HasPrefix(parsed[j].zone, "Etc/")
+	})
+	return parsed, scanner.Err()
+}
+
+//contains casts a ray east of the location and counts the edges it crosses, an odd count is inside. Counting the
+//holes along with the outer ring takes them out
+func (p *polygon) contains(latitude float64, longitude float64) bool {
+	if latitude < p.south || latitude > p.north || longitude < p.west || longitude > p.east {
+		return false
+	}
+	inside := false
+	for _, ring := range p.rings {
+		for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+			a, b := ring[i], ring[j]
+			if (a.Latitude > latitude) != (b.Latitude > latitude) &&
+				longitude < (b.Longitude-a.Longitude)*(latitude-a.Latitude)/(b.Latitude-a.Latitude)+a.Longitude {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
@@ -0,0 +1,87 @@
+package timezone
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookup(t *testing.T) {
+	places := []struct {
+		name                string
+		latitude, longitude float64
+		zone                string
+	}{
+		{"Paris", 48.8566, 2.3522, "Europe/Paris"},
+		{"London", 51.5074, -0.1278, "Europe/London"},
+		{"New York", 40.7128, -74.0060, "America/New_York"},
+		{"Chicago", 41.8781, -87.6298, "America/Chicago"},
+		{"Denver", 39.7392, -104.9903, "America/Denver"},
+		{"Phoenix", 33.4484, -112.0740, "America/Phoenix"},
+		{"Los Angeles", 34.0522, -118.2437, "America/Los_Angeles"},
+		{"Tokyo", 35.6762, 139.6503, "Asia/Tokyo"},
+		{"Kolkata", 22.5726, 88.3639, "Asia/Kolkata"},
+		{"Sydney", -33.8688, 151.2093, "Australia/Sydney"},
+		{"Nouakchott", 18.0735, -15.9582, "Africa/Nouakchott"},
+		{"Sao Paulo", -23.5505, -46.6333, "America/Sao_Paulo"},
+		{"Honolulu", 21.3069, -157.8583, "Pacific/Honolulu"},
+		{"Johannesburg", -26.2041, 28.0473, "Africa/Johannesburg"},
+		//Lesotho is a hole in South Africa
+		{"Maseru", -29.3151, 27.4869, "Africa/Maseru"},
+	}
+	for _, place := range places {
+		assert.EqualValues(t, place.zone, Lookup(place.latitude, place.longitude), place.name)
+	}
+}
+
+func TestLookupOcean(t *testing.T) {
+	assert.EqualValues(t, "Etc/GMT+3", Lookup(30, -40))
+	assert.EqualValues(t, "Etc/GMT-6", Lookup(-30, 90))
+	assert.EqualValues(t, "Etc/GMT", Lookup(0, -5))
+}
+
+func TestNautical(t *testing.T) {
+	assert.EqualValues(t, "Etc/GMT", nautical(7.4))
+	assert.EqualValues(t, "Etc/GMT+1", nautical(-7.6))
+	assert.EqualValues(t, "Etc/GMT-12", nautical(180))
+	assert.EqualValues(t, "Etc/GMT+12", nautical(-180))
+	for _, longitude := range []float64{-180, -100, 0, 45, 180} {
+		_, err := Location(nautical(longitude))
+		assert.Nil(t, err)
+	}
+}
+
+func TestLocation(t *testing.T) {
+	location, err := Location("America/New_York")
+	assert.Nil(t, err)
+	summer := time.Date(2024, 7, 1, 12, 0, 0, 0, location)
+	name, offset := summer.Zone()
+	assert.EqualValues(t, "EDT", name)
+	assert.EqualValues(t, -4*60*60, offset)
+	assert.True(t, summer.IsDST())
+
+	cached, err := Location("America/New_York")
+	assert.Nil(t, err)
+	assert.True(t, location == cached)
+
+	_, err = Location("Mars/Olympus_Mons")
+	assert.NotNil(t, err)
+	for _, name := range []string{"", "Local"} {
+		_, err = Location(name)
+		assert.NotNil(t, err, "%q", name)
+	}
+	utc, err := Location("UTC")
+	assert.Nil(t, err)
+	assert.EqualValues(t, "UTC", utc.String())
+}
+
+//Every zone of the boundaries must load from the embedded tzdata
+func TestBoundariesLoad(t *testing.T) {
+	Lookup(0, 0)
+	assert.True(t, len(polygons) > 1000)
+	for _, p := range polygons {
+		_, err := Location(p.zone)
+		assert.Nil(t, err, p.zone)
+	}
+}
@@ -0,0 +1,72 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//Where the spans are exported
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	//ExporterOtlp sends the spans over OTLP/HTTP to a collector, by default the one on localhost:4318
+	ExporterOtlp = "otlp"
+
+	ServiceName = "weather-api"
+	tracerName  = "interface-testing/api"
+)
+
+func init() {
+	//Trace context is propagated in and out even when no exporter is set up
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+}
+
+//Tracer is the tracer every package of the api starts its spans with
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+//Setup installs a tracer provider exporting to exporter, endpoint being the host:port of the OTLP collector.
+//shutdown flushes the spans still buffered
+func Setup(exporter string, endpoint string) (shutdown func(context.Context) error, err error) {
+	var spanExporter sdktrace.SpanExporter
+	switch exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		spanExporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterOtlp:
+		options := []otlptracehttp.Option{otlptracehttp.WithInsecure()}
+		if endpoint != "" {
+			options = append(options, otlptracehttp.WithEndpoint(endpoint))
+		}
+		spanExporter, err = otlptracehttp.New(context.Background(), options...)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+//Detach returns a context carrying the span of ctx but none of its cancellation, for work that is shared with
+//other requests or outlives this one, such as a coalesced upstream call or a background refresh
+func Detach(ctx context.Context) context.Context {
+	return trace.ContextWithSpan(context.Background(), trace.SpanFromContext(ctx))
+}
@@ -0,0 +1,42 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestSetup(t *testing.T) {
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	shutdown, err := Setup("", "")
+	assert.Nil(t, err)
+	assert.Nil(t, shutdown(context.Background()))
+
+	shutdown, err = Setup(ExporterStdout, "")
+	assert.Nil(t, err)
+	_, isSdk := otel.GetTracerProvider().(*sdktrace.TracerProvider)
+	assert.True(t, isSdk)
+	assert.Nil(t, shutdown(context.Background()))
+
+	_, err = Setup("zipkin", "")
+	assert.EqualValues(t, `unknown trace exporter "zipkin"`, err.Error())
+}
+
+func TestDetach(t *testing.T) {
+	provider := sdktrace.NewTracerProvider()
+	ctx, span := provider.Tracer("test").Start(context.Background(), "request")
+	defer span.End()
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	detached := Detach(ctx)
+	assert.NotNil(t, ctx.Err())
+	assert.Nil(t, detached.Err())
+	assert.EqualValues(t, span.SpanContext(), trace.SpanContextFromContext(detached))
+}
@@ -1,11 +1,12 @@
 package main
 
 import (
-	"interface-testing/api/app"
+	"interface-testing/api/cli"
+	"os"
 )
 
 func main(){
 
-	app.RunApp()
+	os.Exit(cli.Run(os.Args[1:], os.Stdout, os.Stderr))
 
 }